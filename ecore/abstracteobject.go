@@ -198,6 +198,16 @@ func eContainmentFeature(o EObject, container EObject, containerFeatureID int) E
 			if isReference {
 				return reference
 			}
+			// contained in a feature map : containment feature is the feature of the entry
+			if featureMap, _ := container.EGetResolve(feature, false).(EFeatureMap); featureMap != nil {
+				for i := 0; i < featureMap.Size(); i++ {
+					if featureMap.GetValue(i) == o {
+						if reference, isReference := featureMap.GetEStructuralFeature(i).(EReference); isReference {
+							return reference
+						}
+					}
+				}
+			}
 		} else {
 			feature := o.EClass().GetEStructuralFeature(containerFeatureID)
 			reference, isReference := feature.(EReference)
@@ -355,7 +365,19 @@ func (o *AbstractEObject) eDynamicPropertiesCreateMap(feature EStructuralFeature
 }
 
 func (o *AbstractEObject) eDynamicPropertiesCreateList(feature EStructuralFeature) EList {
+	if feature.IsDerived() {
+		// values of derived features of a group or a mixed content are stored in a feature map
+		eObject := o.AsEObject()
+		if featureMapFeature := defaultExtendedMetaData.GetFeatureMap(eObject.EClass(), feature); featureMapFeature != nil {
+			if featureMap, _ := eObject.EGetResolve(featureMapFeature, false).(EFeatureMap); featureMap != nil {
+				return featureMap.GetList(feature)
+			}
+		}
+	}
 	if attribute, isAttribute := feature.(EAttribute); isAttribute {
+		if IsFeatureMap(attribute) {
+			return NewBasicEFeatureMap(o.AsEObjectInternal(), attribute.GetFeatureID())
+		}
 		return NewBasicEDataTypeList(o.AsEObjectInternal(), attribute.GetFeatureID(), attribute.IsUnique())
 	} else if ref, isRef := feature.(EReference); isRef {
 		inverse := false
@@ -382,7 +404,7 @@ func (o *AbstractEObject) eDynamicPropertiesCreateList(feature EStructuralFeatur
 				opposite = false
 			}
 		}
-		return NewBasicEObjectList(o.AsEObjectInternal(), ref.GetFeatureID(), reverseFeatureID, containment, inverse, opposite, ref.EIsProxy(), ref.IsUnsettable())
+		return NewBasicEObjectList(o.AsEObjectInternal(), ref.GetFeatureID(), reverseFeatureID, containment, inverse, opposite, ref.IsResolveProxies(), ref.IsUnsettable())
 	}
 	return nil
 }
@@ -891,6 +913,25 @@ func (o *AbstractEObject) EObjectForFragmentSegment(uriSegment string) EObject {
 		panic("Expecting @ at index 0 of '" + uriSegment + "'")
 	}
 
+	// key based segment : @feature[key='value']
+	if uriSegment[lastIndex] == ']' {
+		if index := strings.IndexByte(uriSegment, '['); index != -1 {
+			keys, ok := parseFragmentKeys(uriSegment[index+1 : lastIndex])
+			if !ok {
+				return nil
+			}
+			eFeature := o.eStructuralFeature(uriSegment[1:index])
+			if list, _ := o.AsEObject().EGetResolve(eFeature, false).(EList); list != nil {
+				for it := list.Iterator(); it.HasNext(); {
+					if eObject, _ := it.Next().(EObject); eObject != nil && matchFragmentKeys(eObject, keys) {
+						return eObject
+					}
+				}
+			}
+			return nil
+		}
+	}
+
 	index := -1
 	r, _ := utf8.DecodeLastRuneInString(uriSegment)
 	if unicode.IsDigit(r) {
@@ -898,8 +939,13 @@ func (o *AbstractEObject) EObjectForFragmentSegment(uriSegment string) EObject {
 			pos, _ := strconv.Atoi(uriSegment[index+1:])
 			eFeatureName := uriSegment[1:index]
 			eFeature := o.eStructuralFeature(eFeatureName)
-			list, _ := o.AsEObject().EGetResolve(eFeature, false).(EList)
-			if list != nil && pos < list.Size() {
+			value := o.AsEObject().EGetResolve(eFeature, false)
+			if featureMap, _ := value.(EFeatureMap); featureMap != nil {
+				if pos < featureMap.Size() {
+					eObject, _ := featureMap.GetValue(pos).(EObject)
+					return eObject
+				}
+			} else if list, _ := value.(EList); list != nil && pos < list.Size() {
 				return list.Get(pos).(EObject)
 			}
 		}
@@ -916,8 +962,24 @@ func (o *AbstractEObject) EURIFragmentSegment(feature EStructuralFeature, object
 	s := "@"
 	s += feature.GetName()
 	if feature.IsMany() {
+		if reference, _ := feature.(EReference); reference != nil && !reference.GetEKeys().Empty() {
+			var builder strings.Builder
+			builder.WriteString(s)
+			writeFragmentKeys(&builder, reference, object)
+			return builder.String()
+		}
 		v := o.AsEObject().EGetResolve(feature, false)
-		i := v.(EList).IndexOf(object)
+		i := -1
+		if featureMap, _ := v.(EFeatureMap); featureMap != nil {
+			for j := 0; j < featureMap.Size(); j++ {
+				if featureMap.GetValue(j) == object {
+					i = j
+					break
+				}
+			}
+		} else {
+			i = v.(EList).IndexOf(object)
+		}
 		s += "." + strconv.Itoa(i)
 	}
 	return s
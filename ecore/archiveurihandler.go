@@ -0,0 +1,284 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+)
+
+const archiveSeparator = "!/"
+
+type archiveFormat int
+
+const (
+	archiveZip archiveFormat = iota
+	archiveTar
+	archiveTarGz
+)
+
+type archiveEntry struct {
+	name    string
+	content []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// ArchiveURIHandler reads and writes entries of zip and tar archives with
+// uris like 'archive:file:/path/bundle.zip!/models/lib.xml'.
+// Archives are read and written with the uri converter so that they can be located anywhere.
+type ArchiveURIHandler struct {
+	uriConverter EURIConverter
+	mutex        sync.Mutex
+}
+
+type archiveWriter struct {
+	bytes.Buffer
+	ctx        context.Context
+	handler    *ArchiveURIHandler
+	archiveURI *URI
+	entryName  string
+}
+
+func (w *archiveWriter) Close() error {
+	return w.handler.writeEntry(w.ctx, w.archiveURI, w.entryName, w.Bytes())
+}
+
+func (w *archiveWriter) Abort() error {
+	w.Reset()
+	return nil
+}
+
+func NewArchiveURIHandler(uriConverter EURIConverter) *ArchiveURIHandler {
+	return &ArchiveURIHandler{uriConverter: uriConverter}
+}
+
+func (h *ArchiveURIHandler) CanHandle(uri *URI) bool {
+	return uri.scheme == "archive" && strings.Contains(uri.path, archiveSeparator)
+}
+
+func (h *ArchiveURIHandler) CreateReader(uri *URI) (io.ReadCloser, error) {
+	return h.CreateReaderContext(context.Background(), uri)
+}
+
+func (h *ArchiveURIHandler) CreateReaderContext(ctx context.Context, uri *URI) (io.ReadCloser, error) {
+	archiveURI, entryName, err := splitArchiveURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	entries, err := h.readArchive(ctx, archiveURI)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.name == entryName {
+			return io.NopCloser(bytes.NewReader(entry.content)), nil
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: uri.String(), Err: fs.ErrNotExist}
+}
+
+func (h *ArchiveURIHandler) CreateWriter(uri *URI) (io.WriteCloser, error) {
+	return h.CreateWriterContext(context.Background(), uri)
+}
+
+func (h *ArchiveURIHandler) CreateWriterContext(ctx context.Context, uri *URI) (io.WriteCloser, error) {
+	archiveURI, entryName, err := splitArchiveURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &archiveWriter{ctx: ctx, handler: h, archiveURI: archiveURI, entryName: entryName}, nil
+}
+
+func (h *ArchiveURIHandler) writeEntry(ctx context.Context, archiveURI *URI, entryName string, content []byte) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	entries, err := h.readArchive(ctx, archiveURI)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	// replace or add entry
+	replaced := false
+	for _, entry := range entries {
+		if entry.name == entryName {
+			entry.content = content
+			entry.modTime = time.Now()
+			replaced = true
+		}
+	}
+	if !replaced {
+		entries = append(entries, &archiveEntry{name: entryName, content: content, mode: 0644, modTime: time.Now()})
+	}
+	// rewrite archive
+	return h.writeArchive(ctx, archiveURI, entries)
+}
+
+func (h *ArchiveURIHandler) readArchive(ctx context.Context, archiveURI *URI) ([]*archiveEntry, error) {
+	r, err := createReaderContext(ctx, h.uriConverter, archiveURI)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch format := getArchiveFormat(archiveURI); format {
+	case archiveZip:
+		return readZipArchive(content)
+	default:
+		return readTarArchive(content, format)
+	}
+}
+
+func (h *ArchiveURIHandler) writeArchive(ctx context.Context, archiveURI *URI, entries []*archiveEntry) error {
+	// writers of the uri converter compress archives and replace files atomically
+	w, err := createWriterContext(ctx, h.uriConverter, archiveURI)
+	if err != nil {
+		return err
+	}
+	if err := writeArchive(w, getArchiveFormat(archiveURI), entries); err != nil {
+		if abortWriter, _ := w.(EAbortWriter); abortWriter != nil {
+			abortWriter.Abort()
+		} else {
+			w.Close()
+		}
+		return err
+	}
+	return w.Close()
+}
+
+func splitArchiveURI(uri *URI) (*URI, string, error) {
+	path := uri.path
+	index := strings.LastIndex(path, archiveSeparator)
+	if uri.scheme != "archive" || index == -1 {
+		return nil, "", fmt.Errorf("invalid archive uri '%s'", uri)
+	}
+	return NewURI(path[:index]), path[index+len(archiveSeparator):], nil
+}
+
+func getArchiveFormat(archiveURI *URI) archiveFormat {
+	// compressed archives are decompressed by the uri converter
+	path := trimCompressionExtension(archiveURI.Path())
+	if strings.HasSuffix(path, ".tgz") {
+		return archiveTarGz
+	} else if strings.HasSuffix(path, ".tar") {
+		return archiveTar
+	}
+	return archiveZip
+}
+
+func readZipArchive(content []byte) ([]*archiveEntry, error) {
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	entries := []*archiveEntry{}
+	for _, f := range r.File {
+		entry := &archiveEntry{name: f.Name, mode: f.Mode(), modTime: f.Modified}
+		if !f.FileInfo().IsDir() {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			entry.content, err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func readTarArchive(content []byte, format archiveFormat) ([]*archiveEntry, error) {
+	var r io.Reader = bytes.NewReader(content)
+	if format == archiveTarGz {
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	}
+	tr := tar.NewReader(r)
+	entries := []*archiveEntry{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		entry := &archiveEntry{name: header.Name, mode: header.FileInfo().Mode(), modTime: header.ModTime}
+		if entry.content, err = io.ReadAll(tr); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func writeArchive(w io.Writer, format archiveFormat, entries []*archiveEntry) error {
+	switch format {
+	case archiveZip:
+		zw := zip.NewWriter(w)
+		for _, entry := range entries {
+			header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate, Modified: entry.modTime}
+			header.SetMode(entry.mode)
+			fw, err := zw.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+			if _, err := fw.Write(entry.content); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	default:
+		var gw *gzip.Writer
+		if format == archiveTarGz {
+			gw = gzip.NewWriter(w)
+			w = gw
+		}
+		tw := tar.NewWriter(w)
+		for _, entry := range entries {
+			header := &tar.Header{Name: entry.name, Mode: int64(entry.mode.Perm()), Size: int64(len(entry.content)), ModTime: entry.modTime, Typeflag: tar.TypeReg}
+			if entry.mode.IsDir() {
+				header.Typeflag = tar.TypeDir
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if _, err := tw.Write(entry.content); err != nil {
+				return err
+			}
+		}
+		if err := tw.Close(); err != nil {
+			return err
+		}
+		if gw != nil {
+			return gw.Close()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+package ecore
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestArchive(t *testing.T, archiveName string, fileNames []string) *URI {
+	archivePath := filepath.Join(t.TempDir(), archiveName)
+	entries := []*archiveEntry{}
+	for _, fileName := range fileNames {
+		content, err := os.ReadFile(filepath.Join("testdata", fileName))
+		require.NoError(t, err)
+		entries = append(entries, &archiveEntry{name: "models/" + fileName, content: content, mode: 0644})
+	}
+	// compressed archives are compressed by the uri converter
+	archiveURI := CreateFileURI(archivePath)
+	w, err := NewEURIConverterImpl().CreateWriter(archiveURI)
+	require.NoError(t, err)
+	require.NoError(t, writeArchive(w, getArchiveFormat(archiveURI), entries))
+	require.NoError(t, w.Close())
+	return archiveURI
+}
+
+func TestArchiveURIHandler_CanHandle(t *testing.T) {
+	h := NewArchiveURIHandler(NewEURIConverterImpl())
+	assert.True(t, h.CanHandle(NewURI("archive:file:/path/bundle.zip!/models/lib.xml")))
+	assert.False(t, h.CanHandle(NewURI("archive:file:/path/bundle.zip")))
+	assert.False(t, h.CanHandle(NewURI("file:/path/bundle.zip!/models/lib.xml")))
+}
+
+func TestArchiveURIHandler_CreateReader(t *testing.T) {
+	for _, archiveName := range []string{"bundle.zip", "bundle.tar", "bundle.tar.gz", "bundle.tgz"} {
+		archiveURI := createTestArchive(t, archiveName, []string{"shop.ecore"})
+		h := NewArchiveURIHandler(NewEURIConverterImpl())
+		r, err := h.CreateReader(NewURI("archive:" + archiveURI.String() + "!/models/shop.ecore"))
+		require.NoError(t, err, archiveName)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		expected, err := os.ReadFile("testdata/shop.ecore")
+		require.NoError(t, err)
+		assert.Equal(t, expected, content)
+
+		_, err = h.CreateReader(NewURI("archive:" + archiveURI.String() + "!/models/unknown.ecore"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	}
+}
+
+func TestArchiveURIHandler_CreateWriter(t *testing.T) {
+	for _, archiveName := range []string{"bundle.zip", "bundle.tar", "bundle.tar.gz", "bundle.tgz"} {
+		archiveURI := createTestArchive(t, archiveName, []string{"shop.ecore"})
+		h := NewArchiveURIHandler(NewEURIConverterImpl())
+		w, err := h.CreateWriter(NewURI("archive:" + archiveURI.String() + "!/models/new.txt"))
+		require.NoError(t, err)
+		_, err = w.Write([]byte("content"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		// new entry is added, others are kept
+		entries, err := h.readArchive(context.Background(), archiveURI)
+		require.NoError(t, err)
+		require.Len(t, entries, 2, archiveName)
+		assert.Equal(t, "models/shop.ecore", entries[0].name)
+		assert.Equal(t, "models/new.txt", entries[1].name)
+		assert.Equal(t, "content", string(entries[1].content))
+
+		// no temporary files left
+		files, err := os.ReadDir(filepath.Dir(archiveURI.Path()))
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	}
+}
+
+func TestArchiveURIHandler_CreateWriter_NewArchive(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "bundle.zip")
+	h := NewArchiveURIHandler(NewEURIConverterImpl())
+	w, err := h.CreateWriter(NewURI("archive:" + CreateFileURI(archivePath).String() + "!/lib.xml"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("content"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err := zip.OpenReader(archivePath)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Len(t, r.File, 1)
+	assert.Equal(t, "lib.xml", r.File[0].Name)
+}
+
+func TestArchiveURIHandler_ResourceSet(t *testing.T) {
+	archiveURI := createTestArchive(t, "bundle.zip", []string{"shop.ecore", "shop.xml", "orders.ecore", "orders.xml"})
+	baseURI := NewURI("archive:" + archiveURI.String() + "!/models/")
+
+	resourceSet := NewEResourceSetImpl()
+	_, eShopPackage := loadTestPackage(t, resourceSet, baseURI.Resolve(NewURI("shop.ecore")))
+	require.NotNil(t, eShopPackage)
+	_, eOrdersPackage := loadTestPackage(t, resourceSet, baseURI.Resolve(NewURI("orders.ecore")))
+	require.NotNil(t, eOrdersPackage)
+	eOrdersResource, eOrders := loadTestModel(t, resourceSet, baseURI.Resolve(NewURI("orders.xml")))
+	require.NotNil(t, eOrders)
+
+	// references between entries are resolved in the archive
+	ResolveAllInResourceSet(resourceSet)
+	eOrdersClass, _ := eOrdersPackage.GetEClassifier("Orders").(EClass)
+	require.NotNil(t, eOrdersClass)
+	eOrderClass, _ := eOrdersPackage.GetEClassifier("Order").(EClass)
+	require.NotNil(t, eOrderClass)
+	eOrder := eOrders.EGet(eOrdersClass.GetEStructuralFeatureFromName("order")).(EList).Get(0).(EObject)
+	eProduct, _ := eOrder.EGet(eOrderClass.GetEStructuralFeatureFromName("product")).(EObject)
+	require.NotNil(t, eProduct)
+	assert.False(t, eProduct.EIsProxy())
+	assert.Equal(t, baseURI.Resolve(NewURI("shop.xml")), eProduct.EResource().GetURI())
+
+	// save in archive
+	eOrdersResource.Save()
+	require.True(t, eOrdersResource.GetErrors().Empty(), diagnosticError(eOrdersResource.GetErrors()))
+	r, err := resourceSet.GetURIConverter().CreateReader(eOrdersResource.GetURI())
+	require.NoError(t, err)
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `href="shop.xml#//@products.0"`)
+}
@@ -11,11 +11,18 @@ package ecore
 
 import "sync"
 
+// EMapKeyHasher returns the value indexing key in a map : keys are equal if their hashes are equal.
+// It allows keys which are not comparable, such as slices, or keys compared by their contents.
+type EMapKeyHasher func(key any) any
+
 type BasicEMap struct {
 	EList
-	interfaces any
-	mapData    map[any]any
-	mapMutex   sync.Mutex
+	interfaces    any
+	mapData       map[any]any
+	entryData     map[any]EMapEntry
+	hasDuplicates bool
+	keyHasher     EMapKeyHasher
+	mapMutex      sync.Mutex
 }
 
 type eMapEntryFactory interface {
@@ -68,21 +75,34 @@ func (m *BasicEMap) asEMapEntryFactory() eMapEntryFactory {
 	return m.interfaces.(eMapEntryFactory)
 }
 
-func (m *BasicEMap) getEntryForKey(key any) EMapEntry {
-	for anyEntry := range m.All() {
-		entry := anyEntry.(EMapEntry)
-		if entry.GetKey() == key {
-			return entry
-		}
+// SetKeyHasher sets the hasher of the keys of the map.
+// By default keys are indexed by themselves and must be comparable.
+func (m *BasicEMap) SetKeyHasher(keyHasher EMapKeyHasher) {
+	m.mapMutex.Lock()
+	m.keyHasher = keyHasher
+	m.resetDataMap()
+	m.mapMutex.Unlock()
+}
+
+func (m *BasicEMap) hashKey(key any) any {
+	if m.keyHasher != nil {
+		return m.keyHasher(key)
 	}
-	return nil
+	return key
+}
+
+func (m *BasicEMap) getEntryForKey(key any) EMapEntry {
+	m.mapMutex.Lock()
+	defer m.mapMutex.Unlock()
+	m.initDataMap()
+	return m.entryData[m.hashKey(key)]
 }
 
 func (m *BasicEMap) GetValue(key any) any {
 	m.mapMutex.Lock()
 	defer m.mapMutex.Unlock()
 	m.initDataMap()
-	return m.mapData[key]
+	return m.mapData[m.hashKey(key)]
 }
 
 func (m *BasicEMap) Put(key any, value any) {
@@ -90,11 +110,17 @@ func (m *BasicEMap) Put(key any, value any) {
 		e.SetValue(value)
 		m.mapMutex.Lock()
 		if m.mapData != nil {
-			m.mapData[key] = value
+			m.mapData[m.hashKey(key)] = value
 		}
 		m.mapMutex.Unlock()
 	} else {
-		m.Add(m.asEMapEntryFactory().newEntry(key, value))
+		entry := m.asEMapEntryFactory().newEntry(key, value)
+		// a new entry is not in the list, its uniqueness doesn't have to be checked
+		if l, _ := m.EList.(abstractEList); l != nil {
+			l.doAdd(entry)
+		} else {
+			m.Add(entry)
+		}
 	}
 }
 
@@ -145,10 +171,11 @@ func (m *BasicEMap) ContainsKey(key any) bool {
 	m.mapMutex.Lock()
 	defer m.mapMutex.Unlock()
 	m.initDataMap()
-	_, ok := m.mapData[key]
+	_, ok := m.mapData[m.hashKey(key)]
 	return ok
 }
 
+// ToMap returns the values of the map indexed by their keys, or by their hashed keys if a key hasher is set
 func (m *BasicEMap) ToMap() map[any]any {
 	m.mapMutex.Lock()
 	defer m.mapMutex.Unlock()
@@ -156,32 +183,59 @@ func (m *BasicEMap) ToMap() map[any]any {
 	return m.mapData
 }
 
+// initDataMap builds the values and the entries of the map indexed by their hashed keys
 func (m *BasicEMap) initDataMap() {
 	if m.mapData == nil {
 		m.mapData = make(map[any]any, m.Size())
-		for itEntry := m.Iterator(); itEntry.HasNext(); {
-			entry := itEntry.Next().(EMapEntry)
-			m.mapData[entry.GetKey()] = entry.GetValue()
+		m.entryData = make(map[any]EMapEntry, m.Size())
+		for anyEntry := range m.All() {
+			m.indexEntry(anyEntry.(EMapEntry))
 		}
 	}
 }
 
+// indexEntry indexes e by its hashed key. Entries with the same key, added through the list, are
+// indexed by the last one
+func (m *BasicEMap) indexEntry(e EMapEntry) {
+	key := m.hashKey(e.GetKey())
+	if other := m.entryData[key]; other != nil && other != e {
+		m.hasDuplicates = true
+	}
+	m.mapData[key] = e.GetValue()
+	m.entryData[key] = e
+}
+
 func (m *BasicEMap) doAdd(e EMapEntry) {
 	m.mapMutex.Lock()
 	if m.mapData != nil {
-		m.mapData[e.GetKey()] = e.GetValue()
+		m.indexEntry(e)
 	}
 	m.mapMutex.Unlock()
 }
 
 func (m *BasicEMap) doRemove(e EMapEntry) {
 	m.mapMutex.Lock()
-	delete(m.mapData, e.GetKey())
+	key := m.hashKey(e.GetKey())
+	if m.entryData[key] == e {
+		if m.hasDuplicates {
+			// another entry of the list may have the same key : index is rebuilt when needed
+			m.resetDataMap()
+		} else {
+			delete(m.mapData, key)
+			delete(m.entryData, key)
+		}
+	}
 	m.mapMutex.Unlock()
 }
 
 func (m *BasicEMap) doClear() {
 	m.mapMutex.Lock()
-	m.mapData = nil
+	m.resetDataMap()
 	m.mapMutex.Unlock()
 }
+
+func (m *BasicEMap) resetDataMap() {
+	m.mapData = nil
+	m.entryData = nil
+	m.hasDuplicates = false
+}
@@ -0,0 +1,30 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import "testing"
+
+func BenchmarkBasicEMap_Put(b *testing.B) {
+	m := NewBasicEMap()
+	for i := 0; i < b.N; i++ {
+		m.Put(i, i)
+	}
+}
+
+func BenchmarkBasicEMap_RemoveKey(b *testing.B) {
+	m := NewBasicEMap()
+	for i := 0; i < b.N; i++ {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.RemoveKey(i)
+	}
+}
@@ -10,6 +10,7 @@
 package ecore
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -144,7 +145,7 @@ func TestBasicEMap_Clear(t *testing.T) {
 
 func TestBasicEMap_UpdateEntry(t *testing.T) {
 	m := NewBasicEMap()
-	m.Put(2, "2")
+	m.Add(m.newEntry(2, "2"))
 	e := m.Get(0).(EMapEntry)
 	e.SetKey(3)
 	e.SetValue("3")
@@ -153,3 +154,108 @@ func TestBasicEMap_UpdateEntry(t *testing.T) {
 	e.SetValue("2")
 	assert.Equal(t, map[any]any{3: "3"}, m.ToMap())
 }
+
+func TestBasicEMap_PutMany(t *testing.T) {
+	m := NewBasicEMap()
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	for i := 0; i < 1000; i++ {
+		m.Put(i, 2*i)
+	}
+	assert.Equal(t, 1000, m.Size())
+	assert.Equal(t, 20, m.GetValue(10))
+	assert.Equal(t, 20, m.Get(10).(EMapEntry).GetValue())
+}
+
+func TestBasicEMap_PutAfterListUpdates(t *testing.T) {
+	m := NewBasicEMap()
+	m.Put(1, "1")
+	m.Put(2, "2")
+
+	// entry set through the list is indexed
+	m.Set(0, m.newEntry(3, "3"))
+	assert.False(t, m.ContainsKey(1))
+	m.Put(3, "4")
+	assert.Equal(t, 2, m.Size())
+	assert.Equal(t, "4", m.Get(0).(EMapEntry).GetValue())
+
+	// entry removed through the list is not indexed
+	m.RemoveAt(0)
+	assert.Nil(t, m.RemoveKey(3))
+	m.Put(3, "3")
+	assert.Equal(t, 2, m.Size())
+
+	// index is rebuilt after clear
+	m.Clear()
+	m.Put(2, "2")
+	assert.Equal(t, 1, m.Size())
+	assert.Equal(t, "2", m.GetValue(2))
+}
+
+func TestBasicEMap_DuplicateKeys(t *testing.T) {
+	m := NewBasicEMap()
+	m.Put(1, "1")
+	m.Put(2, "2")
+	// entries with the same key added through the list
+	m.Add(m.newEntry(1, "3"))
+	assert.Equal(t, "3", m.GetValue(1))
+
+	// removing the indexed entry uses the other one
+	m.RemoveAt(2)
+	assert.True(t, m.ContainsKey(1))
+	assert.Equal(t, "1", m.GetValue(1))
+	assert.Equal(t, map[any]any{1: "1", 2: "2"}, m.ToMap())
+
+	// removing the last one removes the key
+	assert.Equal(t, "1", m.RemoveKey(1))
+	assert.False(t, m.ContainsKey(1))
+	assert.Equal(t, map[any]any{2: "2"}, m.ToMap())
+}
+
+func TestBasicEMap_EObjectKeys(t *testing.T) {
+	m := NewBasicEMap()
+	mockObject := NewMockEObject(t)
+	mockOther := NewMockEObject(t)
+	m.Put(mockObject, 1)
+	m.Put(mockOther, 2)
+	m.Put(mockObject, 3)
+	assert.Equal(t, 2, m.Size())
+	assert.Equal(t, 3, m.GetValue(mockObject))
+	assert.Equal(t, 2, m.RemoveKey(mockOther))
+	assert.False(t, m.ContainsKey(mockOther))
+}
+
+func TestBasicEMap_KeyHasher(t *testing.T) {
+	m := NewBasicEMap()
+	m.SetKeyHasher(func(key any) any {
+		return string(key.([]byte))
+	})
+	m.Put([]byte("a"), 1)
+	m.Put([]byte("b"), 2)
+	m.Put([]byte("a"), 3)
+	assert.Equal(t, 2, m.Size())
+	assert.Equal(t, 3, m.GetValue([]byte("a")))
+	assert.True(t, m.ContainsKey([]byte("b")))
+	assert.Equal(t, map[any]any{"a": 3, "b": 2}, m.ToMap())
+
+	assert.Equal(t, 3, m.RemoveKey([]byte("a")))
+	assert.False(t, m.ContainsKey([]byte("a")))
+	assert.Equal(t, 1, m.Size())
+}
+
+func TestBasicEMap_SetKeyHasher(t *testing.T) {
+	m := NewBasicEMap()
+	m.Put("a", 1)
+	m.Put("B", 2)
+	assert.Nil(t, m.GetValue("b"))
+
+	// index is rebuilt with the new hasher
+	m.SetKeyHasher(func(key any) any {
+		return strings.ToLower(key.(string))
+	})
+	assert.Equal(t, 2, m.GetValue("b"))
+	m.Put("A", 3)
+	assert.Equal(t, 2, m.Size())
+	assert.Equal(t, 3, m.GetValue("a"))
+}
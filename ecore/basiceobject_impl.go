@@ -86,7 +86,7 @@ func (o *BasicEObjectImpl) EContents() EList {
 	properties := o.getObjectProperties()
 	if properties.contents == nil {
 		eObject := o.AsEObject()
-		properties.contents = newEContentsList(eObject, eObject.EClass().GetEAllContainments(), true)
+		properties.contents = newEContentsList(eObject, getContentsFeatures(eObject.EClass(), eObject.EClass().GetEAllContainments()), true)
 	}
 	return properties.contents
 }
@@ -96,7 +96,7 @@ func (o *BasicEObjectImpl) ECrossReferences() EList {
 	properties := o.getObjectProperties()
 	if properties.crossReferences == nil {
 		eObject := o.AsEObject()
-		properties.crossReferences = newEContentsList(eObject, eObject.EClass().GetEAllCrossReferences(), true)
+		properties.crossReferences = newECrossReferencesList(eObject, getContentsFeatures(eObject.EClass(), eObject.EClass().GetEAllCrossReferences()), true)
 	}
 	return properties.crossReferences
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBasicEObjectMap_Constructor(t *testing.T) {
@@ -105,13 +106,13 @@ func TestBasicEObjectMap_Put(t *testing.T) {
 	mockFactory.EXPECT().Create(mockClass).Once().Return(mockEntry)
 	mockEntry.EXPECT().SetKey(2).Once()
 	mockEntry.EXPECT().SetValue("2").Once()
+	mockEntry.EXPECT().GetKey().Once().Return(2)
+	mockEntry.EXPECT().GetValue().Once().Return("2")
 	mockOwner.EXPECT().EDeliver().Return(false).Once()
 	m.Put(2, "2")
 	mock.AssertExpectationsForObjects(t, mockClass, mockPackage, mockFactory, mockEntry, mockOwner)
 
 	// check
-	mockEntry.EXPECT().GetKey().Once().Return(2)
-	mockEntry.EXPECT().GetValue().Once().Return("2")
 	assert.Equal(t, "2", m.GetValue(2))
 	mock.AssertExpectationsForObjects(t, mockClass, mockPackage, mockFactory, mockEntry, mockOwner)
 }
@@ -130,6 +131,8 @@ func TestBasicEObjectMap_Put_WithNotification(t *testing.T) {
 	mockFactory.EXPECT().Create(mockClass).Once().Return(mockEntry)
 	mockEntry.EXPECT().SetKey(2).Once()
 	mockEntry.EXPECT().SetValue("2").Once()
+	mockEntry.EXPECT().GetKey().Once().Return(2)
+	mockEntry.EXPECT().GetValue().Once().Return("2")
 	mockOwner.EXPECT().EDeliver().Return(true).Once()
 	mockOwner.EXPECT().EAdapters().Return(NewImmutableEList([]any{mockAdapter})).Once()
 	mockOwner.EXPECT().ENotify(mock.MatchedBy(func(n ENotification) bool {
@@ -139,6 +142,31 @@ func TestBasicEObjectMap_Put_WithNotification(t *testing.T) {
 	mock.AssertExpectationsForObjects(t, mockClass, mockPackage, mockFactory, mockEntry, mockOwner)
 }
 
+func TestBasicEObjectMap_Put_Containment(t *testing.T) {
+	eAnnotation := GetFactory().CreateEAnnotation()
+	mockAdapter := NewMockEAdapter(t)
+	mockAdapter.EXPECT().SetTarget(eAnnotation).Once()
+	eAnnotation.EAdapters().Add(mockAdapter)
+
+	m := eAnnotation.GetDetails()
+	mockAdapter.EXPECT().NotifyChanged(mock.MatchedBy(func(n ENotification) bool {
+		return n.GetNotifier() == eAnnotation && n.GetFeatureID() == EANNOTATION__DETAILS && n.GetEventType() == ADD
+	})).Once()
+	m.Put("key", "value")
+	mock.AssertExpectationsForObjects(t, mockAdapter)
+
+	require.Equal(t, 1, m.Size())
+	eEntry, _ := m.Get(0).(EObject)
+	require.NotNil(t, eEntry)
+	assert.Equal(t, eAnnotation, eEntry.EContainer())
+	assert.Equal(t, "value", m.GetValue("key"))
+
+	// existing entry is updated
+	m.Put("key", "other")
+	assert.Equal(t, 1, m.Size())
+	assert.Equal(t, "other", m.Get(0).(EMapEntry).GetValue())
+}
+
 func TestBasicEObjectMap_DidSet(t *testing.T) {
 	mockClass := NewMockEClass(t)
 	mockOwner := NewMockEObjectInternal(t)
@@ -2,6 +2,7 @@ package ecore
 
 import (
 	"bytes"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -465,3 +466,34 @@ func TestBinaryCodec_EncodeDecodeObject_WithInternalReferences(t *testing.T) {
 	assert.Equal(t, author, bookAuthor)
 
 }
+
+func TestBinaryCodec_ContainmentProxy(t *testing.T) {
+	m := loadComponentsModel(t)
+	dir := t.TempDir()
+	_, resources := m.newSystem(NewEResourceSetImpl(), dir, "bin")
+	for _, resource := range resources {
+		resource.Save()
+		require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	}
+
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetPackageRegistry().RegisterPackage(m.ePackage)
+	eResource := resourceSet.GetResource(NewURI(filepath.ToSlash(filepath.Join(dir, "components.system.bin"))), true)
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+	assert.Equal(t, 1, resourceSet.GetResources().Size())
+
+	system := eResource.GetContents().Get(0).(EObject)
+	components := system.EGet(m.components).(EList)
+	require.Equal(t, 2, components.Size())
+	c2 := components.Get(1).(EObject)
+	assert.False(t, c2.EIsProxy())
+	assert.Equal(t, "c2", c2.EGet(m.componentName))
+	assert.Equal(t, system, c2.EContainer())
+	assert.Equal(t, 1, c2.EGet(m.parts).(EList).Size())
+	assert.Equal(t, resources[1].GetURI(), c2.EResource().GetURI())
+	main := system.EGet(m.main).(EObject)
+	assert.Equal(t, "main", main.EGet(m.componentName))
+	assert.Equal(t, system, main.EContainer())
+	assert.Equal(t, resources[2].GetURI(), main.EResource().GetURI())
+}
@@ -182,9 +182,24 @@ func (d *BinaryDecoder) decodeVersion() error {
 	if err != nil {
 		return err
 	}
-	if version != binaryVersion {
+	switch version {
+	case binaryVersionInitial:
+		return nil
+	case binaryVersionHighWaterMark:
+		return d.decodeHighWaterMark()
+	default:
 		return errors.New("invalid version for binary emf serialization")
 	}
+}
+
+func (d *BinaryDecoder) decodeHighWaterMark() error {
+	highWaterMark, err := d.decodeInt64()
+	if err != nil {
+		return err
+	}
+	if idManager, _ := d.resource.GetObjectIDManager().(EObjectIDHighWaterMark); highWaterMark >= 0 && idManager != nil {
+		idManager.SetHighWaterMark(highWaterMark)
+	}
 	return nil
 }
 
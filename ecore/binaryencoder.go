@@ -26,7 +26,13 @@ const (
 	checkContainer
 )
 
-var binaryVersion int
+// binary serialization versions
+const (
+	binaryVersionInitial       = 0 // header is signature and version
+	binaryVersionHighWaterMark = 1 // header is followed by the id high-water mark
+)
+
+var binaryVersion = binaryVersionHighWaterMark
 
 var binarySignature = []byte{'\211', 'e', 'm', 'f', '\n', '\r', '\032', '\n'}
 
@@ -181,8 +187,23 @@ func (e *BinaryEncoder) encodeSignature() error {
 	return e.encodeBytes(binarySignature)
 }
 
+// encodeVersion encodes the version of the serialization, which is the initial one if there is no high-water mark to encode
 func (e *BinaryEncoder) encodeVersion() error {
-	return e.encodeInt(e.version)
+	version := binaryVersionInitial
+	var highWaterMark int64
+	if e.isIDAttributeEncoded {
+		var isSequential bool
+		if highWaterMark, isSequential = getIDHighWaterMark(e.resource.GetObjectIDManager()); isSequential {
+			version = e.version
+		}
+	}
+	if err := e.encodeInt(version); err != nil {
+		return err
+	}
+	if version >= binaryVersionHighWaterMark {
+		return e.encodeInt64(highWaterMark)
+	}
+	return nil
 }
 
 func (e *BinaryEncoder) encodeObjects(objects EList, check checkType) error {
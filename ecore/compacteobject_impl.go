@@ -221,7 +221,7 @@ func (o *CompactEObjectImpl) ECrossReferences() EList {
 	crossReferences := o.getField(cross_flag)
 	if crossReferences == nil {
 		eObject := o.AsEObject()
-		crossReferences = newEContentsList(eObject, eObject.EClass().GetECrossReferenceFeatures(), true)
+		crossReferences = newECrossReferencesList(eObject, eObject.EClass().GetECrossReferenceFeatures(), true)
 		o.setField(cross_flag, crossReferences)
 	}
 	return crossReferences.(EList)
@@ -0,0 +1,142 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"compress/gzip"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// uriCompression describes a compression selected by the last extension of an uri.
+// Codecs are selected with the extension before it: 'lib.xml.gz' is a gzip compressed xml resource
+type uriCompression struct {
+	extension string
+	newReader func(r io.Reader) (io.ReadCloser, error)
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+var uriCompressions = []*uriCompression{
+	{
+		extension: "gz",
+		newReader: func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		},
+	},
+	{
+		extension: "zst",
+		newReader: func(r io.Reader) (io.ReadCloser, error) {
+			d, err := zstd.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			return d.IOReadCloser(), nil
+		},
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w)
+		},
+	},
+}
+
+func getPathCompression(path string) *uriCompression {
+	for _, compression := range uriCompressions {
+		if strings.HasSuffix(path, "."+compression.extension) {
+			return compression
+		}
+	}
+	return nil
+}
+
+// trimCompressionExtension removes compression extension of path if any
+func trimCompressionExtension(path string) string {
+	if compression := getPathCompression(path); compression != nil {
+		return path[:len(path)-len(compression.extension)-1]
+	}
+	return path
+}
+
+type compressedReader struct {
+	io.ReadCloser
+	rc io.ReadCloser
+}
+
+func (r *compressedReader) Close() error {
+	err := r.ReadCloser.Close()
+	if rcErr := r.rc.Close(); err == nil {
+		err = rcErr
+	}
+	return err
+}
+
+type compressedWriter struct {
+	io.WriteCloser
+	wc io.WriteCloser
+}
+
+func (w *compressedWriter) Close() error {
+	err := w.WriteCloser.Close()
+	if wcErr := w.wc.Close(); err == nil {
+		err = wcErr
+	}
+	return err
+}
+
+// compressedAbortWriter is a compressedWriter over a writer able to discard its content
+type compressedAbortWriter struct {
+	compressedWriter
+}
+
+func (w *compressedAbortWriter) Abort() error {
+	// pending compressed data is discarded and compressor resources are released
+	if resetWriter, _ := w.WriteCloser.(interface{ Reset(io.Writer) }); resetWriter != nil {
+		resetWriter.Reset(io.Discard)
+	}
+	w.WriteCloser.Close()
+	return w.wc.(EAbortWriter).Abort()
+}
+
+func newCompressedReader(uri *URI, rc io.ReadCloser) (io.ReadCloser, error) {
+	compression := getPathCompression(uri.Path())
+	if compression == nil || rc == nil {
+		return rc, nil
+	}
+	r, err := compression.newReader(rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return &compressedReader{ReadCloser: r, rc: rc}, nil
+}
+
+func newCompressedWriter(uri *URI, wc io.WriteCloser) (io.WriteCloser, error) {
+	compression := getPathCompression(uri.Path())
+	if compression == nil || wc == nil {
+		return wc, nil
+	}
+	w, err := compression.newWriter(wc)
+	if err != nil {
+		// closing an abortable writer would commit its empty content
+		if abortWriter, _ := wc.(EAbortWriter); abortWriter != nil {
+			abortWriter.Abort()
+		} else {
+			wc.Close()
+		}
+		return nil, err
+	}
+	if _, isAbortWriter := wc.(EAbortWriter); isAbortWriter {
+		return &compressedAbortWriter{compressedWriter{WriteCloser: w, wc: wc}}, nil
+	}
+	return &compressedWriter{WriteCloser: w, wc: wc}, nil
+}
@@ -0,0 +1,120 @@
+package ecore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimCompressionExtension(t *testing.T) {
+	assert.Equal(t, "lib.xml", trimCompressionExtension("lib.xml.gz"))
+	assert.Equal(t, "lib.bin", trimCompressionExtension("lib.bin.zst"))
+	assert.Equal(t, "lib.xml", trimCompressionExtension("lib.xml"))
+}
+
+func TestCodecRegistry_CompressedURI(t *testing.T) {
+	codecs := GetCodecRegistry()
+	assert.IsType(t, &XMLCodec{}, codecs.GetCodec(NewURI("lib.xml.gz")))
+	assert.IsType(t, &BinaryCodec{}, codecs.GetCodec(NewURI("lib.bin.zst")))
+}
+
+func TestCompression_SaveLoadResource(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.complex.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	for extension, magic := range map[string][]byte{
+		"xml.gz":  {0x1f, 0x8b},
+		"bin.zst": {0x28, 0xb5, 0x2f, 0xfd},
+	} {
+		path := filepath.Join(t.TempDir(), "library.complex."+extension)
+		eResource.SetURI(CreateFileURI(path))
+		xmlProcessor.Save(eResource)
+		require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+		// file is compressed
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.True(t, len(content) > len(magic))
+		assert.Equal(t, magic, content[:len(magic)], extension)
+
+		// load it back
+		eNewResource := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage})).Load(CreateFileURI(path))
+		require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+		require.Equal(t, 1, eNewResource.GetContents().Size())
+		eDocumentRootClass, _ := ePackage.GetEClassifier("DocumentRoot").(EClass)
+		require.NotNil(t, eDocumentRootClass)
+		eLibraryFeature := eDocumentRootClass.GetEStructuralFeatureFromName("library")
+		eLibrary := eResource.GetContents().Get(0).(EObject).EGet(eLibraryFeature).(EObject)
+		eNewLibrary := eNewResource.GetContents().Get(0).(EObject).EGet(eLibraryFeature).(EObject)
+		assert.True(t, Equals(eLibrary, eNewLibrary), extension)
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func TestCompression_AbortWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.xml.zst")
+	require.NoError(t, os.WriteFile(path, []byte("previous"), 0644))
+
+	w, err := NewEURIConverterImpl().CreateWriter(CreateFileURI(path))
+	require.NoError(t, err)
+	abortWriter, _ := w.(EAbortWriter)
+	require.NotNil(t, abortWriter)
+	_, err = w.Write([]byte("<library>"))
+	require.NoError(t, err)
+	require.NoError(t, abortWriter.Abort())
+
+	// previous content is kept
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+
+	// writers unable to discard their content are not abortable once compressed
+	w, err = newCompressedWriter(NewURI("library.xml.zst"), nopWriteCloser{io.Discard})
+	require.NoError(t, err)
+	_, isAbortWriter := w.(EAbortWriter)
+	assert.False(t, isAbortWriter)
+}
+
+func TestCompression_WriterError(t *testing.T) {
+	compressions := uriCompressions
+	uriCompressions = []*uriCompression{{
+		extension: "err",
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return nil, errors.New("compressor error")
+		},
+	}}
+	defer func() { uriCompressions = compressions }()
+
+	path := filepath.Join(t.TempDir(), "library.xml.err")
+	require.NoError(t, os.WriteFile(path, []byte("previous"), 0644))
+	_, err := NewEURIConverterImpl().CreateWriter(CreateFileURI(path))
+	assert.Error(t, err)
+
+	// previous content is kept
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+}
+
+func TestCompression_InvalidContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.xml.gz")
+	require.NoError(t, os.WriteFile(path, []byte("not compressed"), 0644))
+	_, err := NewEURIConverterImpl().CreateReader(CreateFileURI(path))
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader fails reading as soon as its context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// contextWriter fails writing as soon as its context is done
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+func newContextReader(ctx context.Context, r io.Reader) io.Reader {
+	if ctx.Done() == nil {
+		return r
+	}
+	return &contextReader{ctx: ctx, r: r}
+}
+
+func newContextWriter(ctx context.Context, w io.Writer) io.Writer {
+	if ctx.Done() == nil {
+		return w
+	}
+	return &contextWriter{ctx: ctx, w: w}
+}
+
+// createReaderContext creates a reader with c, binding it to ctx if c supports it
+func createReaderContext(ctx context.Context, c interface {
+	CreateReader(uri *URI) (io.ReadCloser, error)
+}, uri *URI) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if contextHandler, _ := c.(EContextURIHandler); contextHandler != nil {
+		return contextHandler.CreateReaderContext(ctx, uri)
+	}
+	return c.CreateReader(uri)
+}
+
+// createWriterContext creates a writer with c, binding it to ctx if c supports it
+func createWriterContext(ctx context.Context, c interface {
+	CreateWriter(uri *URI) (io.WriteCloser, error)
+}, uri *URI) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if contextHandler, _ := c.(EContextURIHandler); contextHandler != nil {
+		return contextHandler.CreateWriterContext(ctx, uri)
+	}
+	return c.CreateWriter(uri)
+}
+
+// contextDecoder decodes resources with the context of the loading
+type contextDecoder struct {
+	EDecoder
+	ctx context.Context
+}
+
+func (d *contextDecoder) DecodeResource() {
+	if d.ctx.Err() != nil {
+		return
+	}
+	if contextDecoder, _ := d.EDecoder.(EContextDecoder); contextDecoder != nil {
+		contextDecoder.DecodeResourceContext(d.ctx)
+	} else {
+		d.EDecoder.DecodeResource()
+	}
+}
+
+// contextEncoder encodes resources with the context of the saving
+type contextEncoder struct {
+	EEncoder
+	ctx context.Context
+}
+
+func (e *contextEncoder) EncodeResource() {
+	if e.ctx.Err() != nil {
+		return
+	}
+	if contextEncoder, _ := e.EEncoder.(EContextEncoder); contextEncoder != nil {
+		contextEncoder.EncodeResourceContext(e.ctx)
+	} else {
+		e.EEncoder.EncodeResource()
+	}
+}
+
+func newContextDecoder(ctx context.Context, decoder EDecoder) EDecoder {
+	if ctx.Done() == nil {
+		return decoder
+	}
+	return &contextDecoder{EDecoder: decoder, ctx: ctx}
+}
+
+func newContextEncoder(ctx context.Context, encoder EEncoder) EEncoder {
+	if ctx.Done() == nil {
+		return encoder
+	}
+	return &contextEncoder{EEncoder: encoder, ctx: ctx}
+}
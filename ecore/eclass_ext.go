@@ -242,19 +242,24 @@ func (eClass *EClassExt) initFeaturesSubSet() {
 		containments := []any{}
 		crossReferences := []any{}
 		for itFeature := eClass.GetEStructuralFeatures().Iterator(); itFeature.HasNext(); {
-			ref, isRef := itFeature.Next().(EReference)
-			if isRef {
-				if ref.IsContainment() {
-					if !ref.IsDerived() {
-						containments = append(containments, ref)
+			switch feature := itFeature.Next().(type) {
+			case EReference:
+				if feature.IsContainment() {
+					if !feature.IsDerived() {
+						containments = append(containments, feature)
 					}
-				} else if !ref.IsContainer() {
-					if !ref.IsDerived() {
-						crossReferences = append(crossReferences, ref)
+				} else if !feature.IsContainer() {
+					if !feature.IsDerived() {
+						crossReferences = append(crossReferences, feature)
 					}
 				}
+			case EAttribute:
+				// feature map entries may be containments or cross references
+				if IsFeatureMap(feature) && !feature.IsDerived() {
+					containments = append(containments, feature)
+					crossReferences = append(crossReferences, feature)
+				}
 			}
-
 		}
 		eClass.eContainmentFeatures = NewImmutableEList(containments)
 		eClass.eCrossReferenceFeatures = NewImmutableEList(crossReferences)
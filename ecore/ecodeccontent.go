@@ -0,0 +1,91 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+)
+
+// codecContentPeekSize is the initial number of bytes of a content used to detect its codec.
+// It is doubled until the codec is detected or codecContentMaxPeekSize is reached
+const codecContentPeekSize = 4096
+
+const codecContentMaxPeekSize = 1 << 20
+
+var sqliteSignature = []byte("SQLite format 3\x00")
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// binary signature is encoded as msgpack bin 8 bytes
+var binaryContentSignature = append([]byte{0xc4, byte(len(binarySignature))}, binarySignature...)
+
+// getContentExtension returns the extension of the codec able to decode a content
+// starting with content or an empty string if it is unknown
+func getContentExtension(content []byte) string {
+	if bytes.HasPrefix(content, binaryContentSignature) {
+		return "bin"
+	}
+	if bytes.HasPrefix(content, sqliteSignature) {
+		return "sqlite"
+	}
+	// text may be encoded in UTF-16
+	r, _ := newXMLUnicodeReader(bytes.NewReader(content))
+	text, _ := io.ReadAll(r)
+	text = bytes.TrimLeft(text, " \t\r\n")
+	if len(text) == 0 {
+		return ""
+	}
+	switch text[0] {
+	case '{':
+		return "json"
+	case '<':
+		return getXMLContentExtension(content)
+	}
+	return ""
+}
+
+// getXMLContentExtension returns 'ecore' if root element of content has a xmi:version attribute and 'xml' otherwise
+func getXMLContentExtension(content []byte) string {
+	decoder := newXMLTokenDecoder(bytes.NewReader(content))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// root element is truncated or invalid
+			return ""
+		}
+		if startElement, isStartElement := token.(xml.StartElement); isStartElement {
+			for _, attr := range startElement.Attr {
+				if attr.Name.Space == xmiURI && attr.Name.Local == versionAttrib {
+					return "ecore"
+				}
+			}
+			return "xml"
+		}
+	}
+}
+
+// getContentCodec detects the codec of the content of r reading a growing prefix of it.
+// It returns the codec if any and a reader of the whole content
+func getContentCodec(codecs ECodecRegistry, r io.Reader) (ECodec, io.Reader) {
+	var content []byte
+	var codec ECodec
+	for size := codecContentPeekSize; size <= codecContentMaxPeekSize; size *= 2 {
+		chunk := make([]byte, size-len(content))
+		n, err := io.ReadFull(r, chunk)
+		content = append(content, chunk[:n]...)
+		if codec = codecs.GetCodecFromContent(content); codec != nil || err != nil {
+			break
+		}
+	}
+	return codec, io.MultiReader(bytes.NewReader(content), r)
+}
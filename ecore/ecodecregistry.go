@@ -9,20 +9,24 @@
 
 package ecore
 
+import "sync"
+
 const (
 	DEFAULT_EXTENSION = "*"
 )
 
 type ECodecRegistry interface {
 	GetCodec(uri *URI) ECodec
+	GetCodecFromContent(content []byte) ECodec
 	GetProtocolToCodecMap() map[string]ECodec
 	GetExtensionToCodecMap() map[string]ECodec
 }
 
 var resourceCodecRegistryInstance ECodecRegistry
+var resourceCodecRegistryOnce sync.Once
 
 func GetCodecRegistry() ECodecRegistry {
-	if resourceCodecRegistryInstance == nil {
+	resourceCodecRegistryOnce.Do(func() {
 		resourceCodecRegistryInstance = NewECodecRegistryImpl()
 		// initialize with default codecs
 		extensionToCodecs := resourceCodecRegistryInstance.GetExtensionToCodecMap()
@@ -30,8 +34,11 @@ func GetCodecRegistry() ECodecRegistry {
 		extensionToCodecs["xml"] = &XMLCodec{}
 		extensionToCodecs["bin"] = &BinaryCodec{}
 		extensionToCodecs["sqlite"] = &SQLCodec{}
+		extensionToCodecs["pb"] = &ProtoCodec{}
+		extensionToCodecs["yaml"] = &YAMLCodec{}
+		extensionToCodecs["yml"] = &YAMLCodec{}
 		protocolToCodecs := resourceCodecRegistryInstance.GetProtocolToCodecMap()
 		protocolToCodecs["memory"] = &NoCodec{}
-	}
+	})
 	return resourceCodecRegistryInstance
 }
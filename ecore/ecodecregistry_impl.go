@@ -36,7 +36,8 @@ func (r *ECodecRegistryImpl) GetCodec(uri *URI) ECodec {
 	if factory, ok := r.protocolToCodec[uri.scheme]; ok {
 		return factory
 	}
-	p := uri.Path()
+	// codec is selected with the extension before the compression one
+	p := trimCompressionExtension(uri.Path())
 	ndx := strings.LastIndex(p, ".")
 	if ndx != -1 {
 		extension := p[ndx+1:]
@@ -53,6 +54,20 @@ func (r *ECodecRegistryImpl) GetCodec(uri *URI) ECodec {
 	return nil
 }
 
+// GetCodecFromContent returns the codec able to decode a content starting with content.
+// Codec is selected with the extension corresponding to the detected format: binary, sqlite, ecore ( xmi ), xml or json
+func (r *ECodecRegistryImpl) GetCodecFromContent(content []byte) ECodec {
+	if extension := getContentExtension(content); len(extension) > 0 {
+		if factory, ok := r.extensionToCodec[extension]; ok {
+			return factory
+		}
+	}
+	if r.delegate != nil {
+		return r.delegate.GetCodecFromContent(content)
+	}
+	return nil
+}
+
 func (r *ECodecRegistryImpl) GetProtocolToCodecMap() map[string]ECodec {
 	return r.protocolToCodec
 }
@@ -10,9 +10,14 @@
 package ecore
 
 import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestECodecRegistryGetCodecProtocol(t *testing.T) {
@@ -44,3 +49,46 @@ func TestECodecRegistryGetCodecDefault(t *testing.T) {
 	assert.Equal(t, mockCodec, rfr.GetCodec(NewURI("test:///file.test")))
 	assert.Equal(t, mockCodec, rfr.GetCodec(NewURI("file:///file.t")))
 }
+
+func TestECodecRegistryGetCodecFromContent(t *testing.T) {
+	rfr := NewECodecRegistryImplWithDelegate(GetCodecRegistry())
+	mockCodec := new(MockECodec)
+	rfr.GetExtensionToCodecMap()["json"] = mockCodec
+	for fileName, expected := range map[string]ECodec{
+		"library.complex.bin":       &BinaryCodec{},
+		"library.complex.sqlite":    &SQLCodec{},
+		"library.complex.ecore":     &XMICodec{},
+		"library.complex.xml":       &XMLCodec{},
+		"library.complex.json":      mockCodec,
+		"library.simple.utf16.xml":  &XMLCodec{},
+		"library.simple.latin1.xml": &XMLCodec{},
+	} {
+		content, err := os.ReadFile(filepath.Join("testdata", fileName))
+		require.NoError(t, err)
+		if len(content) > codecContentPeekSize {
+			content = content[:codecContentPeekSize]
+		}
+		assert.Equal(t, expected, rfr.GetCodecFromContent(content), fileName)
+	}
+	assert.Equal(t, &XMLCodec{}, rfr.GetCodecFromContent([]byte("\xEF\xBB\xBF<?xml version=\"1.0\"?>\n<root/>")))
+	assert.Nil(t, rfr.GetCodecFromContent([]byte("<?xml version=\"1.0\"?>\n<root attr=")))
+	assert.Nil(t, rfr.GetCodecFromContent([]byte("unknown")))
+	assert.Nil(t, rfr.GetCodecFromContent(nil))
+}
+
+func TestECodecRegistryGetContentCodec(t *testing.T) {
+	rfr := NewECodecRegistryImplWithDelegate(GetCodecRegistry())
+	// root element is after the initial peek size
+	content := "<?xml version=\"1.0\"?>\n<!--" + strings.Repeat(" ", 2*codecContentPeekSize) + "-->\n<xmi:XMI xmi:version=\"2.0\" xmlns:xmi=\"http://www.omg.org/XMI\"/>"
+	codec, r := getContentCodec(rfr, strings.NewReader(content))
+	assert.Equal(t, &XMICodec{}, codec)
+	read, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(read))
+
+	codec, r = getContentCodec(rfr, strings.NewReader("unknown"))
+	assert.Nil(t, codec)
+	read, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "unknown", string(read))
+}
@@ -65,6 +65,45 @@ func (_c *MockECodecRegistry_GetCodec_Call) Return(_a0 ECodec) *MockECodecRegist
 	return _c
 }
 
+// GetCodecFromContent provides a mock function with given fields: content
+func (_m *MockECodecRegistry) GetCodecFromContent(content []byte) ECodec {
+	ret := _m.Called(content)
+
+	var r0 ECodec
+	if rf, ok := ret.Get(0).(func([]byte) ECodec); ok {
+		r0 = rf(content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ECodec)
+		}
+	}
+
+	return r0
+}
+
+// MockECodecRegistry_GetCodecFromContent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCodecFromContent'
+type MockECodecRegistry_GetCodecFromContent_Call struct {
+	*mock.Call
+}
+
+// GetCodecFromContent is a helper method to define mock.On call
+//   - content []byte
+func (_e *MockECodecRegistry_Expecter) GetCodecFromContent(content interface{}) *MockECodecRegistry_GetCodecFromContent_Call {
+	return &MockECodecRegistry_GetCodecFromContent_Call{Call: _e.mock.On("GetCodecFromContent", content)}
+}
+
+func (_c *MockECodecRegistry_GetCodecFromContent_Call) Run(run func(content []byte)) *MockECodecRegistry_GetCodecFromContent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]byte))
+	})
+	return _c
+}
+
+func (_c *MockECodecRegistry_GetCodecFromContent_Call) Return(_a0 ECodec) *MockECodecRegistry_GetCodecFromContent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // GetExtensionToCodecMap provides a mock function with given fields:
 func (_m *MockECodecRegistry) GetExtensionToCodecMap() map[string]ECodec {
 	ret := _m.Called()
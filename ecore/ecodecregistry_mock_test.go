@@ -27,6 +27,19 @@ func TestMockECodecRegistry_GetCodec(t *testing.T) {
 	assert.Equal(t, c, r.GetCodec(uri))
 }
 
+func TestMockECodecRegistry_GetCodecFromContent(t *testing.T) {
+	r := NewMockECodecRegistry(t)
+	c := NewMockECodec(t)
+	content := []byte("content")
+	m := NewMockRun(t, content)
+	r.EXPECT().GetCodecFromContent(content).Return(c).Run(func(content []byte) { m.Run(content) }).Once()
+	r.EXPECT().GetCodecFromContent(content).Call.Return(func([]byte) ECodec {
+		return c
+	}).Once()
+	assert.Equal(t, c, r.GetCodecFromContent(content))
+	assert.Equal(t, c, r.GetCodecFromContent(content))
+}
+
 func TestMockECodecRegistryGetProtocolToCodecMap(t *testing.T) {
 	r := NewMockECodecRegistry(t)
 	m := make(map[string]ECodec)
@@ -4,9 +4,10 @@ import "iter"
 
 type eContentsList struct {
 	emptyImmutableEList
-	o        EObject
-	features EList
-	resolve  bool
+	o               EObject
+	features        EList
+	resolve         bool
+	crossReferences bool
 }
 
 type eContentsListIterator struct {
@@ -44,10 +45,9 @@ func (it *eContentsListIterator) HasNext() bool {
 					value := o.EGetResolve(feature, resolve)
 					if feature.IsMany() {
 						// list of values
-						values := value.(EList)
-						// get unresolved list if object list and not resolved iterator
-						if objectList, _ := value.(EObjectList); objectList != nil && !resolve {
-							values = objectList.GetUnResolvedList()
+						values := it.l.getListValues(value, resolve)
+						if values == nil {
+							continue
 						}
 						if itValues := values.Iterator(); itValues.HasNext() {
 							// we have a value
@@ -85,6 +85,52 @@ func newEContentsList(o EObject, features EList, resolve bool) *eContentsList {
 	}
 }
 
+func newECrossReferencesList(o EObject, features EList, resolve bool) *eContentsList {
+	return &eContentsList{
+		o:               o,
+		features:        features,
+		resolve:         resolve,
+		crossReferences: true,
+	}
+}
+
+// getContentsFeatures returns references with the feature maps of eClass
+func getContentsFeatures(eClass EClass, references EList) EList {
+	var features []any
+	for it := eClass.GetEAllAttributes().Iterator(); it.HasNext(); {
+		if eAttribute := it.Next().(EAttribute); IsFeatureMap(eAttribute) && !eAttribute.IsDerived() {
+			features = append(features, eAttribute)
+		}
+	}
+	if len(features) == 0 {
+		return references
+	}
+	return NewImmutableEList(append(features, references.ToArray()...))
+}
+
+// getListValues returns the values of a many feature which are part of the list or nil if there is none
+func (l *eContentsList) getListValues(value any, resolve bool) EList {
+	switch values := value.(type) {
+	case *eFeatureMapView:
+		// values are those of the feature map
+		return nil
+	case EFeatureMap:
+		entryValues := []any{}
+		for i := 0; i < values.Size(); i++ {
+			if reference, _ := values.GetEStructuralFeature(i).(EReference); reference != nil && reference.IsContainment() != l.crossReferences && !reference.IsContainer() {
+				entryValues = append(entryValues, values.GetValue(i))
+			}
+		}
+		return NewImmutableEList(entryValues)
+	case EObjectList:
+		// get unresolved list if object list and not resolved iterator
+		if !resolve {
+			return values.GetUnResolvedList()
+		}
+	}
+	return value.(EList)
+}
+
 // Get an element of the array
 func (l *eContentsList) Get(index int) any {
 	it := l.Iterator()
@@ -102,8 +148,9 @@ func (l *eContentsList) Size() int {
 		if l.o.EIsSet(eFeature) {
 			value := l.o.EGetResolve(eFeature, false)
 			if eFeature.IsMany() {
-				list := value.(EList)
-				size += list.Size()
+				if list := l.getListValues(value, false); list != nil {
+					size += list.Size()
+				}
 			} else if value != nil {
 				size++
 			}
@@ -119,8 +166,7 @@ func (l *eContentsList) Empty() bool {
 		if l.o.EIsSet(eFeature) {
 			value := l.o.EGetResolve(eFeature, false)
 			if eFeature.IsMany() {
-				list := value.(EList)
-				if !list.Empty() {
+				if list := l.getListValues(value, false); list != nil && !list.Empty() {
 					return false
 				}
 			} else if value != nil {
@@ -180,7 +226,7 @@ func (l *eContentsList) ToArray() []any {
 
 func (l *eContentsList) GetUnResolvedList() EList {
 	if l.resolve {
-		return newEContentsList(l.o, l.features, false)
+		return &eContentsList{o: l.o, features: l.features, crossReferences: l.crossReferences}
 	}
 	return l
 }
@@ -9,7 +9,15 @@
 
 package ecore
 
+import "context"
+
 type EDecoder interface {
 	DecodeResource()
 	DecodeObject() (EObject, error)
 }
+
+// EContextDecoder is implemented by decoders which are able to stop decoding
+// when their context is done
+type EContextDecoder interface {
+	DecodeResourceContext(ctx context.Context)
+}
@@ -9,7 +9,15 @@
 
 package ecore
 
+import "context"
+
 type EEncoder interface {
 	EncodeResource()
 	EncodeObject(object EObject) error
 }
+
+// EContextEncoder is implemented by encoders which are able to stop encoding
+// when their context is done
+type EContextEncoder interface {
+	EncodeResourceContext(ctx context.Context)
+}
@@ -0,0 +1,333 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import "strconv"
+
+// EFeatureMapEntry is an entry of a feature map : a value of a feature
+type EFeatureMapEntry interface {
+	GetEStructuralFeature() EStructuralFeature
+
+	GetValue() any
+}
+
+// EFeatureMap is a list of feature map entries which keeps the order
+// of the values of several features
+type EFeatureMap interface {
+	EList
+
+	// AddValue adds an entry for feature and value at the end of the map
+	AddValue(feature EStructuralFeature, value any) bool
+
+	// InsertValue inserts an entry for feature and value at index
+	InsertValue(index int, feature EStructuralFeature, value any) bool
+
+	// GetEStructuralFeature returns the feature of the entry at index
+	GetEStructuralFeature(index int) EStructuralFeature
+
+	// GetValue returns the value of the entry at index
+	GetValue(index int) any
+
+	// SetValue replaces the value of the entry at index and returns the old one
+	SetValue(index int, value any) any
+
+	// GetList returns a live list of the values of feature
+	GetList(feature EStructuralFeature) EList
+}
+
+type eFeatureMapEntry struct {
+	feature EStructuralFeature
+	value   any
+}
+
+// NewEFeatureMapEntry returns a new feature map entry
+func NewEFeatureMapEntry(feature EStructuralFeature, value any) EFeatureMapEntry {
+	return &eFeatureMapEntry{feature: feature, value: value}
+}
+
+func (e *eFeatureMapEntry) GetEStructuralFeature() EStructuralFeature {
+	return e.feature
+}
+
+func (e *eFeatureMapEntry) GetValue() any {
+	return e.value
+}
+
+type basicEFeatureMap struct {
+	BasicENotifyingList
+	owner     EObjectInternal
+	featureID int
+}
+
+// NewBasicEFeatureMap returns a feature map which is the value of the feature featureID of owner
+func NewBasicEFeatureMap(owner EObjectInternal, featureID int) *basicEFeatureMap {
+	m := new(basicEFeatureMap)
+	m.interfaces = m
+	m.data = []any{}
+	m.owner = owner
+	m.featureID = featureID
+	m.isUnique = false
+	return m
+}
+
+// GetNotifier ...
+func (m *basicEFeatureMap) GetNotifier() ENotifier {
+	return m.owner
+}
+
+// GetFeature ...
+func (m *basicEFeatureMap) GetFeature() EStructuralFeature {
+	if m.owner != nil {
+		return m.owner.EClass().GetEStructuralFeature(m.featureID)
+	}
+	return nil
+}
+
+// GetFeatureID ...
+func (m *basicEFeatureMap) GetFeatureID() int {
+	return m.featureID
+}
+
+func (m *basicEFeatureMap) AddValue(feature EStructuralFeature, value any) bool {
+	return m.Add(NewEFeatureMapEntry(feature, value))
+}
+
+func (m *basicEFeatureMap) InsertValue(index int, feature EStructuralFeature, value any) bool {
+	return m.Insert(index, NewEFeatureMapEntry(feature, value))
+}
+
+func (m *basicEFeatureMap) GetEStructuralFeature(index int) EStructuralFeature {
+	return m.Get(index).(EFeatureMapEntry).GetEStructuralFeature()
+}
+
+func (m *basicEFeatureMap) GetValue(index int) any {
+	return m.Get(index).(EFeatureMapEntry).GetValue()
+}
+
+func (m *basicEFeatureMap) SetValue(index int, value any) any {
+	entry := m.Get(index).(EFeatureMapEntry)
+	m.Set(index, NewEFeatureMapEntry(entry.GetEStructuralFeature(), value))
+	return entry.GetValue()
+}
+
+func (m *basicEFeatureMap) GetList(feature EStructuralFeature) EList {
+	return newEFeatureMapView(m, feature)
+}
+
+// RemoveWithNotification removes an entry or the entry of a value
+func (m *basicEFeatureMap) RemoveWithNotification(object any, notifications ENotificationChain) ENotificationChain {
+	if _, isEntry := object.(EFeatureMapEntry); !isEntry {
+		for _, e := range m.data {
+			if entry := e.(EFeatureMapEntry); entry.GetValue() == object {
+				object = entry
+				break
+			}
+		}
+	}
+	return m.BasicENotifyingList.RemoveWithNotification(object, notifications)
+}
+
+// getEntryFeatureID returns the feature id used as the container feature id of entry value
+func (m *basicEFeatureMap) getEntryFeatureID(entry EFeatureMapEntry) int {
+	if featureID := m.owner.EClass().GetFeatureID(entry.GetEStructuralFeature()); featureID >= 0 {
+		return featureID
+	}
+	return m.featureID
+}
+
+func (m *basicEFeatureMap) inverseAdd(object any, notifications ENotificationChain) ENotificationChain {
+	entry := object.(EFeatureMapEntry)
+	if reference, _ := entry.GetEStructuralFeature().(EReference); reference != nil {
+		if internal, _ := entry.GetValue().(EObjectInternal); internal != nil {
+			if opposite := reference.GetEOpposite(); opposite != nil {
+				return internal.EInverseAdd(m.owner, opposite.GetFeatureID(), notifications)
+			} else if reference.IsContainment() {
+				return internal.EInverseAdd(m.owner, EOPPOSITE_FEATURE_BASE-m.getEntryFeatureID(entry), notifications)
+			}
+		}
+	}
+	return notifications
+}
+
+func (m *basicEFeatureMap) inverseRemove(object any, notifications ENotificationChain) ENotificationChain {
+	entry := object.(EFeatureMapEntry)
+	if reference, _ := entry.GetEStructuralFeature().(EReference); reference != nil {
+		if internal, _ := entry.GetValue().(EObjectInternal); internal != nil {
+			if opposite := reference.GetEOpposite(); opposite != nil {
+				return internal.EInverseRemove(m.owner, opposite.GetFeatureID(), notifications)
+			} else if reference.IsContainment() {
+				return internal.EInverseRemove(m.owner, EOPPOSITE_FEATURE_BASE-m.getEntryFeatureID(entry), notifications)
+			}
+		}
+	}
+	return notifications
+}
+
+// eFeatureMapView is the list of the values of a feature stored in a feature map.
+// Values of features affiliated to this feature by a substitution group belong to the view.
+type eFeatureMapView struct {
+	AbstractEList
+	featureMap *basicEFeatureMap
+	feature    EStructuralFeature
+	emd        *ExtendedMetaData
+}
+
+func newEFeatureMapView(featureMap *basicEFeatureMap, feature EStructuralFeature) *eFeatureMapView {
+	v := &eFeatureMapView{
+		featureMap: featureMap,
+		feature:    feature,
+		emd:        defaultExtendedMetaData,
+	}
+	v.interfaces = v
+	return v
+}
+
+func (v *eFeatureMapView) isMatching(entryFeature EStructuralFeature) bool {
+	if entryFeature == v.feature {
+		return true
+	}
+	if owner := v.featureMap.owner; owner != nil {
+		return v.emd.GetClassAffiliation(owner.EClass(), entryFeature) == v.feature
+	}
+	return false
+}
+
+// entryIndex returns the index in the feature map of the value at index in the view
+// or the size of the map if there is no such value
+func (v *eFeatureMapView) entryIndex(index int) int {
+	count := 0
+	for i, e := range v.featureMap.data {
+		if v.isMatching(e.(EFeatureMapEntry).GetEStructuralFeature()) {
+			if count == index {
+				return i
+			}
+			count++
+		}
+	}
+	return len(v.featureMap.data)
+}
+
+func (v *eFeatureMapView) newEntry(value any) EFeatureMapEntry {
+	return NewEFeatureMapEntry(v.feature, value)
+}
+
+func (v *eFeatureMapView) doGet(index int) any {
+	return v.featureMap.GetValue(v.entryIndex(index))
+}
+
+func (v *eFeatureMapView) doSet(index int, elem any) any {
+	return v.featureMap.SetValue(v.entryIndex(index), elem)
+}
+
+func (v *eFeatureMapView) doAdd(elem any) {
+	v.featureMap.Add(v.newEntry(elem))
+}
+
+func (v *eFeatureMapView) doAddAll(c Collection) bool {
+	for it := c.Iterator(); it.HasNext(); {
+		v.doAdd(it.Next())
+	}
+	return !c.Empty()
+}
+
+func (v *eFeatureMapView) doInsert(index int, elem any) {
+	v.featureMap.Insert(v.entryIndex(index), v.newEntry(elem))
+}
+
+func (v *eFeatureMapView) doInsertAll(index int, c Collection) bool {
+	for it := c.Iterator(); it.HasNext(); index++ {
+		v.doInsert(index, it.Next())
+	}
+	return !c.Empty()
+}
+
+func (v *eFeatureMapView) doClear() []any {
+	var values []any
+	for i := len(v.featureMap.data) - 1; i >= 0; i-- {
+		if entry := v.featureMap.data[i].(EFeatureMapEntry); v.isMatching(entry.GetEStructuralFeature()) {
+			v.featureMap.RemoveAt(i)
+			values = append([]any{entry.GetValue()}, values...)
+		}
+	}
+	return values
+}
+
+func (v *eFeatureMapView) doMove(oldIndex, newIndex int) any {
+	entry := v.featureMap.Move(v.entryIndex(oldIndex), v.entryIndex(newIndex))
+	return entry.(EFeatureMapEntry).GetValue()
+}
+
+func (v *eFeatureMapView) doRemove(index int) any {
+	return v.featureMap.RemoveAt(v.entryIndex(index)).(EFeatureMapEntry).GetValue()
+}
+
+func (v *eFeatureMapView) doRemoveRange(fromIndex, toIndex int) []any {
+	values := make([]any, 0, toIndex-fromIndex)
+	for i := fromIndex; i < toIndex; i++ {
+		values = append(values, v.doRemove(fromIndex))
+	}
+	return values
+}
+
+func (v *eFeatureMapView) Size() int {
+	size := 0
+	for _, e := range v.featureMap.data {
+		if v.isMatching(e.(EFeatureMapEntry).GetEStructuralFeature()) {
+			size++
+		}
+	}
+	return size
+}
+
+func (v *eFeatureMapView) ToArray() []any {
+	values := []any{}
+	for _, e := range v.featureMap.data {
+		if entry := e.(EFeatureMapEntry); v.isMatching(entry.GetEStructuralFeature()) {
+			values = append(values, entry.GetValue())
+		}
+	}
+	return values
+}
+
+// GetNotifier ...
+func (v *eFeatureMapView) GetNotifier() ENotifier {
+	return v.featureMap.GetNotifier()
+}
+
+// GetFeature ...
+func (v *eFeatureMapView) GetFeature() EStructuralFeature {
+	return v.feature
+}
+
+// GetFeatureID ...
+func (v *eFeatureMapView) GetFeatureID() int {
+	if owner := v.featureMap.owner; owner != nil {
+		return owner.EClass().GetFeatureID(v.feature)
+	}
+	return -1
+}
+
+// AddWithNotification ...
+func (v *eFeatureMapView) AddWithNotification(object any, notifications ENotificationChain) ENotificationChain {
+	return v.featureMap.AddWithNotification(v.newEntry(object), notifications)
+}
+
+// RemoveWithNotification ...
+func (v *eFeatureMapView) RemoveWithNotification(object any, notifications ENotificationChain) ENotificationChain {
+	return v.featureMap.RemoveWithNotification(object, notifications)
+}
+
+// SetWithNotification ...
+func (v *eFeatureMapView) SetWithNotification(index int, object any, notifications ENotificationChain) ENotificationChain {
+	if size := v.Size(); index < 0 || index >= size {
+		panic("Index out of bounds: index=" + strconv.Itoa(index) + " size=" + strconv.Itoa(size))
+	}
+	return v.featureMap.SetWithNotification(v.entryIndex(index), v.newEntry(object), notifications)
+}
@@ -0,0 +1,276 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockEFeatureMap is an autogenerated mock type for the EFeatureMap type
+type MockEFeatureMap struct {
+	MockEList
+}
+
+type MockEFeatureMap_Expecter struct {
+	MockEList_Expecter
+}
+
+func (_m *MockEFeatureMap) EXPECT() *MockEFeatureMap_Expecter {
+	e := &MockEFeatureMap_Expecter{}
+	e.Mock = &_m.Mock
+	return e
+}
+
+// AddValue provides a mock function with given fields: feature, value
+func (_m *MockEFeatureMap) AddValue(feature EStructuralFeature, value any) bool {
+	ret := _m.Called(feature, value)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(EStructuralFeature, any) bool); ok {
+		r0 = rf(feature, value)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockEFeatureMap_AddValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddValue'
+type MockEFeatureMap_AddValue_Call struct {
+	*mock.Call
+}
+
+// AddValue is a helper method to define mock.On call
+//   - feature EStructuralFeature
+//   - value any
+func (_e *MockEFeatureMap_Expecter) AddValue(feature any, value any) *MockEFeatureMap_AddValue_Call {
+	return &MockEFeatureMap_AddValue_Call{Call: _e.Mock.On("AddValue", feature, value)}
+}
+
+func (_c *MockEFeatureMap_AddValue_Call) Run(run func(feature EStructuralFeature, value any)) *MockEFeatureMap_AddValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(EStructuralFeature), args[1])
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMap_AddValue_Call) Return(_a0 bool) *MockEFeatureMap_AddValue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// GetEStructuralFeature provides a mock function with given fields: index
+func (_m *MockEFeatureMap) GetEStructuralFeature(index int) EStructuralFeature {
+	ret := _m.Called(index)
+
+	var r0 EStructuralFeature
+	if rf, ok := ret.Get(0).(func(int) EStructuralFeature); ok {
+		r0 = rf(index)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(EStructuralFeature)
+		}
+	}
+
+	return r0
+}
+
+// MockEFeatureMap_GetEStructuralFeature_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEStructuralFeature'
+type MockEFeatureMap_GetEStructuralFeature_Call struct {
+	*mock.Call
+}
+
+// GetEStructuralFeature is a helper method to define mock.On call
+//   - index int
+func (_e *MockEFeatureMap_Expecter) GetEStructuralFeature(index any) *MockEFeatureMap_GetEStructuralFeature_Call {
+	return &MockEFeatureMap_GetEStructuralFeature_Call{Call: _e.Mock.On("GetEStructuralFeature", index)}
+}
+
+func (_c *MockEFeatureMap_GetEStructuralFeature_Call) Run(run func(index int)) *MockEFeatureMap_GetEStructuralFeature_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMap_GetEStructuralFeature_Call) Return(_a0 EStructuralFeature) *MockEFeatureMap_GetEStructuralFeature_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// GetList provides a mock function with given fields: feature
+func (_m *MockEFeatureMap) GetList(feature EStructuralFeature) EList {
+	ret := _m.Called(feature)
+
+	var r0 EList
+	if rf, ok := ret.Get(0).(func(EStructuralFeature) EList); ok {
+		r0 = rf(feature)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(EList)
+		}
+	}
+
+	return r0
+}
+
+// MockEFeatureMap_GetList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetList'
+type MockEFeatureMap_GetList_Call struct {
+	*mock.Call
+}
+
+// GetList is a helper method to define mock.On call
+//   - feature EStructuralFeature
+func (_e *MockEFeatureMap_Expecter) GetList(feature any) *MockEFeatureMap_GetList_Call {
+	return &MockEFeatureMap_GetList_Call{Call: _e.Mock.On("GetList", feature)}
+}
+
+func (_c *MockEFeatureMap_GetList_Call) Run(run func(feature EStructuralFeature)) *MockEFeatureMap_GetList_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(EStructuralFeature))
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMap_GetList_Call) Return(_a0 EList) *MockEFeatureMap_GetList_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// GetValue provides a mock function with given fields: index
+func (_m *MockEFeatureMap) GetValue(index int) any {
+	ret := _m.Called(index)
+
+	var r0 any
+	if rf, ok := ret.Get(0).(func(int) any); ok {
+		r0 = rf(index)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0)
+		}
+	}
+
+	return r0
+}
+
+// MockEFeatureMap_GetValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetValue'
+type MockEFeatureMap_GetValue_Call struct {
+	*mock.Call
+}
+
+// GetValue is a helper method to define mock.On call
+//   - index int
+func (_e *MockEFeatureMap_Expecter) GetValue(index any) *MockEFeatureMap_GetValue_Call {
+	return &MockEFeatureMap_GetValue_Call{Call: _e.Mock.On("GetValue", index)}
+}
+
+func (_c *MockEFeatureMap_GetValue_Call) Run(run func(index int)) *MockEFeatureMap_GetValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int))
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMap_GetValue_Call) Return(_a0 any) *MockEFeatureMap_GetValue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// InsertValue provides a mock function with given fields: index, feature, value
+func (_m *MockEFeatureMap) InsertValue(index int, feature EStructuralFeature, value any) bool {
+	ret := _m.Called(index, feature, value)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(int, EStructuralFeature, any) bool); ok {
+		r0 = rf(index, feature, value)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockEFeatureMap_InsertValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertValue'
+type MockEFeatureMap_InsertValue_Call struct {
+	*mock.Call
+}
+
+// InsertValue is a helper method to define mock.On call
+//   - index int
+//   - feature EStructuralFeature
+//   - value any
+func (_e *MockEFeatureMap_Expecter) InsertValue(index any, feature any, value any) *MockEFeatureMap_InsertValue_Call {
+	return &MockEFeatureMap_InsertValue_Call{Call: _e.Mock.On("InsertValue", index, feature, value)}
+}
+
+func (_c *MockEFeatureMap_InsertValue_Call) Run(run func(index int, feature EStructuralFeature, value any)) *MockEFeatureMap_InsertValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].(EStructuralFeature), args[2])
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMap_InsertValue_Call) Return(_a0 bool) *MockEFeatureMap_InsertValue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// SetValue provides a mock function with given fields: index, value
+func (_m *MockEFeatureMap) SetValue(index int, value any) any {
+	ret := _m.Called(index, value)
+
+	var r0 any
+	if rf, ok := ret.Get(0).(func(int, any) any); ok {
+		r0 = rf(index, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0)
+		}
+	}
+
+	return r0
+}
+
+// MockEFeatureMap_SetValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetValue'
+type MockEFeatureMap_SetValue_Call struct {
+	*mock.Call
+}
+
+// SetValue is a helper method to define mock.On call
+//   - index int
+//   - value any
+func (_e *MockEFeatureMap_Expecter) SetValue(index any, value any) *MockEFeatureMap_SetValue_Call {
+	return &MockEFeatureMap_SetValue_Call{Call: _e.Mock.On("SetValue", index, value)}
+}
+
+func (_c *MockEFeatureMap_SetValue_Call) Run(run func(index int, value any)) *MockEFeatureMap_SetValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1])
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMap_SetValue_Call) Return(_a0 any) *MockEFeatureMap_SetValue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+type mockConstructorTestingTNewMockEFeatureMap interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockEFeatureMap creates a new instance of MockEFeatureMap. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockEFeatureMap(t mockConstructorTestingTNewMockEFeatureMap) *MockEFeatureMap {
+	mock := &MockEFeatureMap{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,87 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockEFeatureMap_AddValue(t *testing.T) {
+	l := NewMockEFeatureMap(t)
+	f := NewMockEStructuralFeature(t)
+	m := NewMockRun(t, f, 1)
+	l.EXPECT().AddValue(f, 1).Return(true).Run(func(feature EStructuralFeature, value any) { m.Run(feature, value) }).Once()
+	l.EXPECT().AddValue(f, 2).Call.Return(func(EStructuralFeature, any) bool {
+		return true
+	}).Once()
+	assert.True(t, l.AddValue(f, 1))
+	assert.True(t, l.AddValue(f, 2))
+}
+
+func TestMockEFeatureMap_InsertValue(t *testing.T) {
+	l := NewMockEFeatureMap(t)
+	f := NewMockEStructuralFeature(t)
+	m := NewMockRun(t, 0, f, 1)
+	l.EXPECT().InsertValue(0, f, 1).Return(true).Run(func(index int, feature EStructuralFeature, value any) { m.Run(index, feature, value) }).Once()
+	l.EXPECT().InsertValue(1, f, 2).Call.Return(func(int, EStructuralFeature, any) bool {
+		return true
+	}).Once()
+	assert.True(t, l.InsertValue(0, f, 1))
+	assert.True(t, l.InsertValue(1, f, 2))
+}
+
+func TestMockEFeatureMap_GetEStructuralFeature(t *testing.T) {
+	l := NewMockEFeatureMap(t)
+	f := NewMockEStructuralFeature(t)
+	m := NewMockRun(t, 0)
+	l.EXPECT().GetEStructuralFeature(0).Return(f).Run(func(index int) { m.Run(index) }).Once()
+	l.EXPECT().GetEStructuralFeature(1).Call.Return(func(int) EStructuralFeature {
+		return f
+	}).Once()
+	assert.Equal(t, f, l.GetEStructuralFeature(0))
+	assert.Equal(t, f, l.GetEStructuralFeature(1))
+}
+
+func TestMockEFeatureMap_GetValue(t *testing.T) {
+	l := NewMockEFeatureMap(t)
+	m := NewMockRun(t, 0)
+	l.EXPECT().GetValue(0).Return("0").Run(func(index int) { m.Run(index) }).Once()
+	l.EXPECT().GetValue(1).Call.Return(func(int) any {
+		return "1"
+	}).Once()
+	assert.Equal(t, "0", l.GetValue(0))
+	assert.Equal(t, "1", l.GetValue(1))
+}
+
+func TestMockEFeatureMap_SetValue(t *testing.T) {
+	l := NewMockEFeatureMap(t)
+	m := NewMockRun(t, 0, "a")
+	l.EXPECT().SetValue(0, "a").Return("0").Run(func(index int, value any) { m.Run(index, value) }).Once()
+	l.EXPECT().SetValue(1, "b").Call.Return(func(int, any) any {
+		return "1"
+	}).Once()
+	assert.Equal(t, "0", l.SetValue(0, "a"))
+	assert.Equal(t, "1", l.SetValue(1, "b"))
+}
+
+func TestMockEFeatureMap_GetList(t *testing.T) {
+	l := NewMockEFeatureMap(t)
+	f := NewMockEStructuralFeature(t)
+	r := NewMockEList(t)
+	m := NewMockRun(t, f)
+	l.EXPECT().GetList(f).Return(r).Run(func(feature EStructuralFeature) { m.Run(feature) }).Once()
+	l.EXPECT().GetList(f).Call.Return(func(EStructuralFeature) EList {
+		return r
+	}).Once()
+	assert.Equal(t, r, l.GetList(f))
+	assert.Equal(t, r, l.GetList(f))
+}
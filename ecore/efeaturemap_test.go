@@ -0,0 +1,200 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type featureMapModel struct {
+	ePackage         EPackage
+	documentClass    EClass
+	paragraphClass   EClass
+	circleClass      EClass
+	squareClass      EClass
+	circleElement    EStructuralFeature
+	squareElement    EStructuralFeature
+	shapeGroup       EStructuralFeature
+	shape            EStructuralFeature
+	paragraphs       EStructuralFeature
+	paragraphMixed   EStructuralFeature
+	paragraphBold    EStructuralFeature
+	paragraphItalic  EStructuralFeature
+	shapeName        EStructuralFeature
+	documentAny      EStructuralFeature
+	documentAnyAttrs EStructuralFeature
+}
+
+func loadFeatureMapModel(t *testing.T) *featureMapModel {
+	ePackage := loadPackage("featuremap.ecore")
+	require.NotNil(t, ePackage)
+	m := &featureMapModel{ePackage: ePackage}
+	m.documentClass = ePackage.GetEClassifier("Document").(EClass)
+	m.paragraphClass = ePackage.GetEClassifier("Paragraph").(EClass)
+	m.circleClass = ePackage.GetEClassifier("Circle").(EClass)
+	m.squareClass = ePackage.GetEClassifier("Square").(EClass)
+	documentRoot := ePackage.GetEClassifier("DocumentRoot").(EClass)
+	m.circleElement = documentRoot.GetEStructuralFeatureFromName("circle")
+	m.squareElement = documentRoot.GetEStructuralFeatureFromName("square")
+	m.shapeGroup = m.documentClass.GetEStructuralFeatureFromName("shapeGroup")
+	m.shape = m.documentClass.GetEStructuralFeatureFromName("shape")
+	m.paragraphs = m.documentClass.GetEStructuralFeatureFromName("paragraphs")
+	m.documentAny = m.documentClass.GetEStructuralFeatureFromName("any")
+	m.documentAnyAttrs = m.documentClass.GetEStructuralFeatureFromName("anyAttribute")
+	m.paragraphMixed = m.paragraphClass.GetEStructuralFeatureFromName("mixed")
+	m.paragraphBold = m.paragraphClass.GetEStructuralFeatureFromName("b")
+	m.paragraphItalic = m.paragraphClass.GetEStructuralFeatureFromName("i")
+	m.shapeName = ePackage.GetEClassifier("Shape").(EClass).GetEStructuralFeatureFromName("name")
+	return m
+}
+
+func (m *featureMapModel) newShape(eClass EClass, name string) EObject {
+	eObject := m.ePackage.GetEFactoryInstance().Create(eClass)
+	eObject.ESet(m.shapeName, name)
+	return eObject
+}
+
+func TestEFeatureMapEntry(t *testing.T) {
+	mockFeature := NewMockEStructuralFeature(t)
+	e := NewEFeatureMapEntry(mockFeature, "value")
+	assert.Equal(t, mockFeature, e.GetEStructuralFeature())
+	assert.Equal(t, "value", e.GetValue())
+}
+
+func TestEFeatureMap_Accessors(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	document := m.ePackage.GetEFactoryInstance().Create(m.documentClass)
+	featureMap, _ := document.EGet(m.shapeGroup).(EFeatureMap)
+	require.NotNil(t, featureMap)
+	assert.Equal(t, document, featureMap.(ENotifyingList).GetNotifier())
+	assert.Equal(t, m.shapeGroup, featureMap.(ENotifyingList).GetFeature())
+	assert.Equal(t, m.shapeGroup.GetFeatureID(), featureMap.(ENotifyingList).GetFeatureID())
+
+	circle := m.newShape(m.circleClass, "c")
+	square := m.newShape(m.squareClass, "s")
+	assert.True(t, featureMap.AddValue(m.circleElement, circle))
+	assert.True(t, featureMap.InsertValue(0, m.squareElement, square))
+	assert.Equal(t, 2, featureMap.Size())
+	assert.Equal(t, m.squareElement, featureMap.GetEStructuralFeature(0))
+	assert.Equal(t, square, featureMap.GetValue(0))
+	assert.Equal(t, m.circleElement, featureMap.GetEStructuralFeature(1))
+	assert.Equal(t, circle, featureMap.GetValue(1))
+
+	other := m.newShape(m.circleClass, "o")
+	assert.Equal(t, circle, featureMap.SetValue(1, other))
+	assert.Equal(t, m.circleElement, featureMap.GetEStructuralFeature(1))
+	assert.Equal(t, other, featureMap.GetValue(1))
+}
+
+func TestEFeatureMap_Containment(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	document := m.ePackage.GetEFactoryInstance().Create(m.documentClass)
+	featureMap := document.EGet(m.shapeGroup).(EFeatureMap)
+
+	circle := m.newShape(m.circleClass, "c")
+	featureMap.AddValue(m.circleElement, circle)
+	assert.Equal(t, document, circle.EContainer())
+	assert.Equal(t, m.shapeGroup, circle.EContainingFeature())
+	assert.Equal(t, m.circleElement, circle.EContainmentFeature())
+	assert.Equal(t, []any{circle}, document.EContents().ToArray())
+
+	// move to another container
+	other := m.ePackage.GetEFactoryInstance().Create(m.documentClass)
+	other.EGet(m.shapeGroup).(EFeatureMap).AddValue(m.circleElement, circle)
+	assert.Equal(t, other, circle.EContainer())
+	assert.True(t, featureMap.Empty())
+
+	// remove
+	other.EGet(m.shapeGroup).(EFeatureMap).RemoveAt(0)
+	assert.Nil(t, circle.EContainer())
+}
+
+func TestEFeatureMap_View(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	document := m.ePackage.GetEFactoryInstance().Create(m.documentClass)
+	featureMap := document.EGet(m.shapeGroup).(EFeatureMap)
+	shapes, _ := document.EGet(m.shape).(ENotifyingList)
+	require.NotNil(t, shapes)
+	assert.Equal(t, document, shapes.GetNotifier())
+	assert.Equal(t, m.shape, shapes.GetFeature())
+	assert.Equal(t, m.shape.GetFeatureID(), shapes.GetFeatureID())
+
+	// values of substitution group members
+	circle := m.newShape(m.circleClass, "c")
+	square := m.newShape(m.squareClass, "s")
+	featureMap.AddValue(m.circleElement, circle)
+	featureMap.AddValue(m.squareElement, square)
+	assert.Equal(t, 2, shapes.Size())
+	assert.Equal(t, []any{circle, square}, shapes.ToArray())
+	assert.Equal(t, square, shapes.Get(1))
+	assert.Equal(t, 1, shapes.IndexOf(square))
+
+	// add through the view
+	other := m.newShape(m.circleClass, "o")
+	shapes.Insert(1, other)
+	assert.Equal(t, []any{circle, other, square}, shapes.ToArray())
+	assert.Equal(t, m.shape, featureMap.GetEStructuralFeature(1))
+	assert.Equal(t, document, other.EContainer())
+	assert.Equal(t, m.shape, other.EContainmentFeature())
+
+	// move
+	shapes.Move(0, 2)
+	assert.Equal(t, []any{other, square, circle}, shapes.ToArray())
+	assert.Equal(t, []any{other, square, circle}, document.EContents().ToArray())
+
+	// remove
+	assert.True(t, shapes.Remove(square))
+	assert.Nil(t, square.EContainer())
+	assert.Equal(t, 2, featureMap.Size())
+
+	// set
+	assert.Equal(t, other, shapes.Set(0, square))
+	assert.Equal(t, document, square.EContainer())
+	assert.Nil(t, other.EContainer())
+
+	// clear
+	shapes.Clear()
+	assert.True(t, featureMap.Empty())
+	assert.Nil(t, circle.EContainer())
+}
+
+func TestEFeatureMap_Mixed(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	paragraph := m.ePackage.GetEFactoryInstance().Create(m.paragraphClass)
+	mixed := paragraph.EGet(m.paragraphMixed).(EFeatureMap)
+	mixed.AddValue(GetXMLTypeTextFeature(), "Hello ")
+	bold := paragraph.EGet(m.paragraphBold).(EList)
+	bold.Add("bold")
+	italic := paragraph.EGet(m.paragraphItalic).(EList)
+	italic.Add("italic")
+	bold.Add("again")
+	assert.Equal(t, 4, mixed.Size())
+	assert.Equal(t, []any{"bold", "again"}, bold.ToArray())
+	assert.Equal(t, []any{"italic"}, italic.ToArray())
+	assert.Equal(t, m.paragraphItalic, mixed.GetEStructuralFeature(2))
+	assert.Equal(t, []any{"bold", "again"}, mixed.GetList(m.paragraphBold).ToArray())
+}
+
+func TestEFeatureMap_URIFragment(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	document := m.ePackage.GetEFactoryInstance().Create(m.documentClass)
+	resource := NewEResourceImpl()
+	resource.GetContents().Add(document)
+	featureMap := document.EGet(m.shapeGroup).(EFeatureMap)
+	circle := m.newShape(m.circleClass, "c")
+	square := m.newShape(m.squareClass, "s")
+	featureMap.AddValue(m.circleElement, circle)
+	featureMap.AddValue(m.squareElement, square)
+	assert.Equal(t, "//@shapeGroup.1", resource.GetURIFragment(square))
+	assert.Equal(t, square, resource.GetEObject("//@shapeGroup.1"))
+}
@@ -0,0 +1,116 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockEFeatureMapEntry is an autogenerated mock type for the EFeatureMapEntry type
+type MockEFeatureMapEntry struct {
+	mock.Mock
+}
+
+type MockEFeatureMapEntry_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockEFeatureMapEntry) EXPECT() *MockEFeatureMapEntry_Expecter {
+	return &MockEFeatureMapEntry_Expecter{mock: &_m.Mock}
+}
+
+// GetEStructuralFeature provides a mock function with given fields:
+func (_m *MockEFeatureMapEntry) GetEStructuralFeature() EStructuralFeature {
+	ret := _m.Called()
+
+	var r0 EStructuralFeature
+	if rf, ok := ret.Get(0).(func() EStructuralFeature); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(EStructuralFeature)
+		}
+	}
+
+	return r0
+}
+
+// MockEFeatureMapEntry_GetEStructuralFeature_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEStructuralFeature'
+type MockEFeatureMapEntry_GetEStructuralFeature_Call struct {
+	*mock.Call
+}
+
+// GetEStructuralFeature is a helper method to define mock.On call
+func (_e *MockEFeatureMapEntry_Expecter) GetEStructuralFeature() *MockEFeatureMapEntry_GetEStructuralFeature_Call {
+	return &MockEFeatureMapEntry_GetEStructuralFeature_Call{Call: _e.mock.On("GetEStructuralFeature")}
+}
+
+func (_c *MockEFeatureMapEntry_GetEStructuralFeature_Call) Run(run func()) *MockEFeatureMapEntry_GetEStructuralFeature_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMapEntry_GetEStructuralFeature_Call) Return(_a0 EStructuralFeature) *MockEFeatureMapEntry_GetEStructuralFeature_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// GetValue provides a mock function with given fields:
+func (_m *MockEFeatureMapEntry) GetValue() any {
+	ret := _m.Called()
+
+	var r0 any
+	if rf, ok := ret.Get(0).(func() any); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0)
+		}
+	}
+
+	return r0
+}
+
+// MockEFeatureMapEntry_GetValue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetValue'
+type MockEFeatureMapEntry_GetValue_Call struct {
+	*mock.Call
+}
+
+// GetValue is a helper method to define mock.On call
+func (_e *MockEFeatureMapEntry_Expecter) GetValue() *MockEFeatureMapEntry_GetValue_Call {
+	return &MockEFeatureMapEntry_GetValue_Call{Call: _e.mock.On("GetValue")}
+}
+
+func (_c *MockEFeatureMapEntry_GetValue_Call) Run(run func()) *MockEFeatureMapEntry_GetValue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockEFeatureMapEntry_GetValue_Call) Return(_a0 any) *MockEFeatureMapEntry_GetValue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+type mockConstructorTestingTNewMockEFeatureMapEntry interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMockEFeatureMapEntry creates a new instance of MockEFeatureMapEntry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMockEFeatureMapEntry(t mockConstructorTestingTNewMockEFeatureMapEntry) *MockEFeatureMapEntry {
+	mock := &MockEFeatureMapEntry{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
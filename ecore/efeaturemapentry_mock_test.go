@@ -0,0 +1,39 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockEFeatureMapEntry_GetEStructuralFeature(t *testing.T) {
+	e := NewMockEFeatureMapEntry(t)
+	f := NewMockEStructuralFeature(t)
+	m := NewMockRun(t)
+	e.EXPECT().GetEStructuralFeature().Return(f).Run(func() { m.Run() }).Once()
+	e.EXPECT().GetEStructuralFeature().Call.Return(func() EStructuralFeature {
+		return f
+	}).Once()
+	assert.Equal(t, f, e.GetEStructuralFeature())
+	assert.Equal(t, f, e.GetEStructuralFeature())
+}
+
+func TestMockEFeatureMapEntry_GetValue(t *testing.T) {
+	e := NewMockEFeatureMapEntry(t)
+	m := NewMockRun(t)
+	e.EXPECT().GetValue().Return("1").Run(func() { m.Run() }).Once()
+	e.EXPECT().GetValue().Call.Return(func() any {
+		return "2"
+	}).Once()
+	assert.Equal(t, "1", e.GetValue())
+	assert.Equal(t, "2", e.GetValue())
+}
@@ -22,5 +22,7 @@ type EMap interface {
 
 	ContainsKey(key any) bool
 
+	// ToMap returns the values indexed by their keys.
+	// Keys of maps with a key hasher, which may not be comparable, are replaced by their hashes
 	ToMap() map[any]any
 }
@@ -22,3 +22,21 @@ type EObjectIDManager interface {
 
 	GetDetachedID(EObject) any
 }
+
+// EObjectIDHighWaterMark is implemented by managers which may generate sequential ids.
+// The high-water mark is the next generated id, codecs save it with the resource.
+// It is negative if ids are not sequential.
+type EObjectIDHighWaterMark interface {
+	GetHighWaterMark() int64
+	SetHighWaterMark(int64)
+}
+
+// getIDHighWaterMark returns the high-water mark of objectIDManager and true if it generates sequential ids
+func getIDHighWaterMark(objectIDManager EObjectIDManager) (int64, bool) {
+	if idManager, _ := objectIDManager.(EObjectIDHighWaterMark); idManager != nil {
+		if highWaterMark := idManager.GetHighWaterMark(); highWaterMark >= 0 {
+			return highWaterMark, true
+		}
+	}
+	return -1, false
+}
@@ -0,0 +1,98 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"sync"
+
+	"github.com/petermattis/goid"
+)
+
+// EPackageDescriptor describes a package which is created or loaded the first time it is needed
+type EPackageDescriptor interface {
+	GetEPackage() EPackage
+}
+
+// ePackageLoading is a call in progress of a descriptor loader by the goroutine owner
+type ePackageLoading struct {
+	done     chan struct{}
+	owner    int64
+	ePackage EPackage
+}
+
+type ePackageDescriptorImpl struct {
+	loader   func() EPackage
+	ePackage EPackage
+	loading  *ePackageLoading
+	mutex    sync.Mutex
+}
+
+// NewEPackageDescriptor returns a descriptor calling loader the first time the package is needed.
+// A nil package is not kept: loader is called again the next time the package is needed
+func NewEPackageDescriptor(loader func() EPackage) EPackageDescriptor {
+	return &ePackageDescriptorImpl{loader: loader}
+}
+
+// NewEPackageURIDescriptor returns a descriptor loading the package at uri through resourceSet.
+// If uri has no fragment, the package is the first root of the loaded resource.
+// A new resource set is used if resourceSet is nil.
+func NewEPackageURIDescriptor(uri *URI, resourceSet EResourceSet) EPackageDescriptor {
+	return NewEPackageDescriptor(func() EPackage {
+		return loadEPackage(uri, resourceSet)
+	})
+}
+
+// GetEPackage returns the package of the descriptor, waiting for a loading in progress in another goroutine.
+// It returns nil if the loading in progress depends on the current goroutine, as for packages referring to each other
+func (d *ePackageDescriptorImpl) GetEPackage() EPackage {
+	d.mutex.Lock()
+	if d.ePackage != nil {
+		defer d.mutex.Unlock()
+		return d.ePackage
+	}
+	if loading := d.loading; loading != nil {
+		d.mutex.Unlock()
+		id, canWait := loadingWaits.beginWait(loading.owner)
+		if !canWait {
+			return nil
+		}
+		defer loadingWaits.endWait(id)
+		<-loading.done
+		return loading.ePackage
+	}
+	loading := &ePackageLoading{done: make(chan struct{}), owner: goid.Get()}
+	d.loading = loading
+	d.mutex.Unlock()
+
+	ePackage := d.loader()
+
+	d.mutex.Lock()
+	d.ePackage = ePackage
+	d.loading = nil
+	loading.ePackage = ePackage
+	d.mutex.Unlock()
+	close(loading.done)
+	return ePackage
+}
+
+func loadEPackage(uri *URI, resourceSet EResourceSet) EPackage {
+	if resourceSet == nil {
+		resourceSet = NewEResourceSetImpl()
+	}
+	if len(uri.Fragment()) > 0 {
+		ePackage, _ := resourceSet.GetEObject(uri, true).(EPackage)
+		return ePackage
+	}
+	if resource := resourceSet.GetResource(uri, true); resource != nil && resource.IsLoaded() && !resource.GetContents().Empty() {
+		ePackage, _ := resource.GetContents().Get(0).(EPackage)
+		return ePackage
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEPackageDescriptor(t *testing.T) {
+	p := NewMockEPackage(t)
+	loads := 0
+	d := NewEPackageDescriptor(func() EPackage {
+		loads++
+		return p
+	})
+	assert.Equal(t, 0, loads)
+	assert.Equal(t, p, d.GetEPackage())
+	assert.Equal(t, p, d.GetEPackage())
+	assert.Equal(t, 1, loads)
+}
+
+func TestEPackageDescriptor_Nil(t *testing.T) {
+	p := NewMockEPackage(t)
+	loads := 0
+	d := NewEPackageDescriptor(func() EPackage {
+		loads++
+		if loads == 1 {
+			return nil
+		}
+		return p
+	})
+	assert.Nil(t, d.GetEPackage())
+	assert.Equal(t, p, d.GetEPackage())
+	assert.Equal(t, 2, loads)
+}
+
+func TestEPackageDescriptor_Reentrant(t *testing.T) {
+	p := NewMockEPackage(t)
+	var d EPackageDescriptor
+	var loading EPackage
+	d = NewEPackageDescriptor(func() EPackage {
+		loading = d.GetEPackage()
+		return p
+	})
+	requireDoneWithin(t, 5*time.Second, func() {
+		assert.Equal(t, p, d.GetEPackage())
+	})
+	assert.Nil(t, loading)
+}
+
+func TestEPackageDescriptor_Cycle(t *testing.T) {
+	pA := NewMockEPackage(t)
+	pB := NewMockEPackage(t)
+	// each loader requires the other package once both are started
+	var started sync.WaitGroup
+	started.Add(2)
+	var dA, dB EPackageDescriptor
+	dA = NewEPackageDescriptor(func() EPackage {
+		started.Done()
+		started.Wait()
+		dB.GetEPackage()
+		return pA
+	})
+	dB = NewEPackageDescriptor(func() EPackage {
+		started.Done()
+		started.Wait()
+		dA.GetEPackage()
+		return pB
+	})
+	var ePackageA, ePackageB EPackage
+	requireDoneWithin(t, 5*time.Second, func() {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ePackageA = dA.GetEPackage()
+		}()
+		go func() {
+			defer wg.Done()
+			ePackageB = dB.GetEPackage()
+		}()
+		wg.Wait()
+	})
+	assert.Equal(t, pA, ePackageA)
+	assert.Equal(t, pB, ePackageB)
+}
+
+func TestEPackageURIDescriptor(t *testing.T) {
+	d := NewEPackageURIDescriptor(NewURI("testdata/library.simple.ecore"), nil)
+	ePackage := d.GetEPackage()
+	require.NotNil(t, ePackage)
+	assert.Equal(t, "library", ePackage.GetName())
+	assert.Equal(t, ePackage, d.GetEPackage())
+}
+
+func TestEPackageURIDescriptor_Fragment(t *testing.T) {
+	resourceSet := NewEResourceSetImpl()
+	d := NewEPackageURIDescriptor(NewURI("testdata/library.simple.ecore#/"), resourceSet)
+	ePackage := d.GetEPackage()
+	require.NotNil(t, ePackage)
+	assert.Equal(t, "library", ePackage.GetName())
+	assert.Equal(t, resourceSet, ePackage.EResource().GetResourceSet())
+}
+
+func TestEPackageURIDescriptor_Invalid(t *testing.T) {
+	d := NewEPackageURIDescriptor(NewURI("testdata/unknown.ecore"), nil)
+	assert.Nil(t, d.GetEPackage())
+}
@@ -9,9 +9,12 @@
 
 package ecore
 
+import "sync"
+
 type EPackageRegistry interface {
 	PutPackage(nsURI string, pack EPackage)
 	PutSupplier(nsURI string, supplier func() EPackage)
+	PutDescriptor(nsURI string, descriptor EPackageDescriptor)
 	Remove(nsURI string)
 
 	RegisterPackage(pack EPackage)
@@ -22,11 +25,12 @@ type EPackageRegistry interface {
 }
 
 var packageRegistryInstance EPackageRegistry
+var packageRegistryOnce sync.Once
 
 func GetPackageRegistry() EPackageRegistry {
-	if packageRegistryInstance == nil {
+	packageRegistryOnce.Do(func() {
 		packageRegistryInstance = NewEPackageRegistryImpl()
 		packageRegistryInstance.RegisterPackage(GetPackage())
-	}
+	})
 	return packageRegistryInstance
 }
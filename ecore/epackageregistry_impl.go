@@ -9,9 +9,12 @@
 
 package ecore
 
+import "sync"
+
 type EPackageRegistryImpl struct {
 	packages map[string]any
 	delegate EPackageRegistry
+	mutex    sync.RWMutex
 }
 
 func NewEPackageRegistryImpl() *EPackageRegistryImpl {
@@ -30,31 +33,61 @@ func NewEPackageRegistryImplWithDelegate(delegate EPackageRegistry) *EPackageReg
 }
 
 func (r *EPackageRegistryImpl) RegisterPackage(pack EPackage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	r.packages[pack.GetNsURI()] = pack
 }
 
 func (r *EPackageRegistryImpl) UnregisterPackage(pack EPackage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	delete(r.packages, pack.GetNsURI())
 }
 
 func (r *EPackageRegistryImpl) PutPackage(nsURI string, pack EPackage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	r.packages[nsURI] = pack
 }
 
 func (r *EPackageRegistryImpl) PutSupplier(nsURI string, supplier func() EPackage) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	r.packages[nsURI] = supplier
 }
 
+func (r *EPackageRegistryImpl) PutDescriptor(nsURI string, descriptor EPackageDescriptor) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.packages[nsURI] = descriptor
+}
+
 func (r *EPackageRegistryImpl) Remove(nsURI string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	delete(r.packages, nsURI)
 }
 
 func (r *EPackageRegistryImpl) doGetPackage(nsURI string) EPackage {
-	if p := r.packages[nsURI]; p != nil {
+	r.mutex.RLock()
+	p := r.packages[nsURI]
+	r.mutex.RUnlock()
+	if p != nil {
 		if pack, _ := p.(EPackage); pack != nil {
 			return pack
 		} else if f, _ := p.(func() EPackage); f != nil {
 			return f()
+		} else if d, _ := p.(EPackageDescriptor); d != nil {
+			pack := d.GetEPackage()
+			if pack != nil {
+				// replace the descriptor by its package
+				r.mutex.Lock()
+				if r.packages[nsURI] == d {
+					r.packages[nsURI] = pack
+				}
+				r.mutex.Unlock()
+			}
+			return pack
 		}
 	}
 	return nil
@@ -108,3 +108,29 @@ func TestMockEPackageRegistryImpl_GetFactory(t *testing.T) {
 		mock.AssertExpectationsForObjects(t, p, delegate)
 	}
 }
+
+func TestMockEPackageRegistryImpl_PutDescriptor(t *testing.T) {
+	rp := NewEPackageRegistryImpl()
+	p := NewMockEPackage(t)
+	loads := 0
+	rp.PutDescriptor("nsURI", NewEPackageDescriptor(func() EPackage {
+		loads++
+		return p
+	}))
+	assert.Equal(t, p, rp.GetPackage("nsURI"))
+	assert.Equal(t, p, rp.GetPackage("nsURI"))
+	assert.Equal(t, 1, loads)
+
+	f := NewMockEFactory(t)
+	p.EXPECT().GetEFactoryInstance().Return(f).Once()
+	assert.Equal(t, f, rp.GetFactory("nsURI"))
+}
+
+func TestMockEPackageRegistryImpl_PutDescriptorNil(t *testing.T) {
+	rp := NewEPackageRegistryImpl()
+	rp.PutDescriptor("nsURI", NewEPackageDescriptor(func() EPackage {
+		return nil
+	}))
+	assert.Nil(t, rp.GetPackage("nsURI"))
+	assert.Nil(t, rp.GetFactory("nsURI"))
+}
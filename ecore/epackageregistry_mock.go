@@ -124,6 +124,35 @@ func (_c *MockEPackageRegistry_PutPackage_Call) Return() *MockEPackageRegistry_P
 	return _c
 }
 
+// PutDescriptor provides a mock function with given fields: nsURI, descriptor
+func (_m *MockEPackageRegistry) PutDescriptor(nsURI string, descriptor EPackageDescriptor) {
+	_m.Called(nsURI, descriptor)
+}
+
+// MockEPackageRegistry_PutDescriptor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutDescriptor'
+type MockEPackageRegistry_PutDescriptor_Call struct {
+	*mock.Call
+}
+
+// PutDescriptor is a helper method to define mock.On call
+//   - nsURI string
+//   - descriptor EPackageDescriptor
+func (_e *MockEPackageRegistry_Expecter) PutDescriptor(nsURI interface{}, descriptor interface{}) *MockEPackageRegistry_PutDescriptor_Call {
+	return &MockEPackageRegistry_PutDescriptor_Call{Call: _e.mock.On("PutDescriptor", nsURI, descriptor)}
+}
+
+func (_c *MockEPackageRegistry_PutDescriptor_Call) Run(run func(nsURI string, descriptor EPackageDescriptor)) *MockEPackageRegistry_PutDescriptor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(EPackageDescriptor))
+	})
+	return _c
+}
+
+func (_c *MockEPackageRegistry_PutDescriptor_Call) Return() *MockEPackageRegistry_PutDescriptor_Call {
+	_c.Call.Return()
+	return _c
+}
+
 // PutSupplier provides a mock function with given fields: nsURI, supplier
 func (_m *MockEPackageRegistry) PutSupplier(nsURI string, supplier func() EPackage) {
 	_m.Called(nsURI, supplier)
@@ -1,6 +1,7 @@
 package ecore
 
 import (
+	"context"
 	"io"
 )
 
@@ -11,6 +12,8 @@ const (
 
 	RESOURCE__CONTENTS = 2
 
+	RESOURCE__IS_MODIFIED = 3
+
 	RESOURCE__IS_LOADED = 4
 
 	RESOURCE__ERRORS = 5
@@ -18,6 +21,11 @@ const (
 	RESOURCE__WARNINGS = 6
 )
 
+const (
+	RESOURCE_OPTION_SAVE_ONLY_IF_MODIFIED = "SAVE_ONLY_IF_MODIFIED" // if true, resource tracking modification is saved only if it is modified
+	RESOURCE_OPTION_SAVE_ONLY_IF_CHANGED  = "SAVE_ONLY_IF_CHANGED"  // if true, resource is written only if its encoded bytes differ from the existing ones
+)
+
 // EResource ...
 type EResource interface {
 	ENotifier
@@ -37,15 +45,23 @@ type EResource interface {
 	IsLoading() bool
 	IsLoaded() bool
 
+	IsModified() bool
+	SetModified(isModified bool)
+
+	IsTrackingModification() bool
+	SetTrackingModification(isTrackingModification bool)
+
 	Load()
 	LoadWithOptions(options map[string]any)
 	LoadWithReader(r io.Reader, options map[string]any)
+	LoadContext(ctx context.Context, options map[string]any) error
 
 	Unload()
 
 	Save()
 	SaveWithOptions(options map[string]any)
 	SaveWithWriter(w io.Writer, options map[string]any)
+	SaveContext(ctx context.Context, options map[string]any) error
 
 	GetErrors() EList
 	GetWarnings() EList
@@ -53,5 +69,8 @@ type EResource interface {
 	SetObjectIDManager(EObjectIDManager)
 	GetObjectIDManager() EObjectIDManager
 
+	GetEObjectToExtensionMap() map[EObject]*XMLExtension
+
 	GetResourceListeners() EList
+	GetResourceLifecycleListeners() EList
 }
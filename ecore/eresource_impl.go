@@ -1,10 +1,14 @@
 package ecore
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type EResourceInternal interface {
@@ -157,18 +161,48 @@ func (rd *resourceDiagnostics) GetFeatureID() int {
 	return rd.featureID
 }
 
+// resourceModificationAdapter sets its resource as modified when its contents change
+type resourceModificationAdapter struct {
+	*EContentAdapter
+	resource *EResourceImpl
+}
+
+func newResourceModificationAdapter(resource *EResourceImpl) *resourceModificationAdapter {
+	adapter := &resourceModificationAdapter{EContentAdapter: NewEContentAdapter(), resource: resource}
+	adapter.SetInterfaces(adapter)
+	return adapter
+}
+
+func (adapter *resourceModificationAdapter) NotifyChanged(notification ENotification) {
+	adapter.EContentAdapter.NotifyChanged(notification)
+	switch notification.GetEventType() {
+	case RESOLVE, REMOVING_ADAPTER:
+		return
+	}
+	if _, isResource := notification.GetNotifier().(EResource); isResource && notification.GetFeatureID() != RESOURCE__CONTENTS {
+		return
+	}
+	adapter.resource.SetModified(true)
+}
+
 // EResource ...
 type EResourceImpl struct {
 	ENotifierImpl
-	resourceSet     EResourceSet
-	objectIDManager EObjectIDManager
-	uri             *URI
-	contents        EList
-	errors          EList
-	warnings        EList
-	isLoaded        bool
-	isLoading       bool
-	listeners       EList
+	resourceSet         EResourceSet
+	objectIDManager     EObjectIDManager
+	intrinsicIDs        *intrinsicIDIndex
+	intrinsicIDsMutex   sync.Mutex
+	extensions          map[EObject]*XMLExtension
+	uri                 *URI
+	contents            EList
+	errors              EList
+	warnings            EList
+	isLoaded            bool
+	isLoading           bool
+	listeners           EList
+	lifecycleListeners  EList
+	isModified          bool
+	modificationAdapter *resourceModificationAdapter
 }
 
 // NewBasicEObject is BasicEObject constructor
@@ -221,6 +255,82 @@ func (r *EResourceImpl) GetResourceListeners() EList {
 	return r.listeners
 }
 
+func (r *EResourceImpl) GetResourceLifecycleListeners() EList {
+	if r.lifecycleListeners == nil {
+		r.lifecycleListeners = NewEmptyBasicEList()
+	}
+	return r.lifecycleListeners
+}
+
+// notifyLifecycleListeners calls notify for lifecycle listeners of the resource and then of its resource set
+func (r *EResourceImpl) notifyLifecycleListeners(notify func(listener EResourceLifecycleListener)) {
+	listeners := []EList{r.lifecycleListeners}
+	if r.resourceSet != nil {
+		listeners = append(listeners, r.resourceSet.GetResourceLifecycleListeners())
+	}
+	for _, l := range listeners {
+		if l != nil {
+			for it := l.Iterator(); it.HasNext(); {
+				notify(it.Next().(EResourceLifecycleListener))
+			}
+		}
+	}
+}
+
+func (r *EResourceImpl) getErrorDiagnostics() []EDiagnostic {
+	diagnostics := []EDiagnostic{}
+	if r.errors != nil {
+		for it := r.errors.Iterator(); it.HasNext(); {
+			diagnostics = append(diagnostics, it.Next().(EDiagnostic))
+		}
+	}
+	return diagnostics
+}
+
+func (r *EResourceImpl) beforeLoad(options map[string]any) time.Time {
+	resource := r.AsEResource()
+	r.notifyLifecycleListeners(func(listener EResourceLifecycleListener) {
+		listener.BeforeLoad(resource, options)
+	})
+	return time.Now()
+}
+
+func (r *EResourceImpl) afterLoad(options map[string]any, start time.Time) {
+	resource := r.AsEResource()
+	if diagnostics := r.getErrorDiagnostics(); len(diagnostics) > 0 {
+		r.notifyLifecycleListeners(func(listener EResourceLifecycleListener) {
+			listener.LoadFailed(resource, options, diagnostics)
+		})
+	} else {
+		duration := time.Since(start)
+		r.notifyLifecycleListeners(func(listener EResourceLifecycleListener) {
+			listener.AfterLoad(resource, options, duration)
+		})
+	}
+}
+
+func (r *EResourceImpl) beforeSave(options map[string]any) time.Time {
+	resource := r.AsEResource()
+	r.notifyLifecycleListeners(func(listener EResourceLifecycleListener) {
+		listener.BeforeSave(resource, options)
+	})
+	return time.Now()
+}
+
+func (r *EResourceImpl) afterSave(options map[string]any, start time.Time) {
+	resource := r.AsEResource()
+	if diagnostics := r.getErrorDiagnostics(); len(diagnostics) > 0 {
+		r.notifyLifecycleListeners(func(listener EResourceLifecycleListener) {
+			listener.SaveFailed(resource, options, diagnostics)
+		})
+	} else {
+		duration := time.Since(start)
+		r.notifyLifecycleListeners(func(listener EResourceLifecycleListener) {
+			listener.AfterSave(resource, options, duration)
+		})
+	}
+}
+
 func (r *EResourceImpl) getAllContentsResolve(root any, resolve bool) EIterator {
 	return newTreeIterator(root, false, func(o any) EIterator {
 		if o == r.GetInterfaces() {
@@ -230,10 +340,39 @@ func (r *EResourceImpl) getAllContentsResolve(root any, resolve bool) EIterator
 		if !resolve {
 			contents = contents.(EObjectList).GetUnResolvedList()
 		}
-		return contents.Iterator()
+		return newProperContentsIterator(contents.Iterator())
 	})
 }
 
+// properContentsIterator skips the contained objects which are proxies or which
+// are directly in a resource : they are not part of the contents of the resource
+type properContentsIterator struct {
+	it   EIterator
+	next EObject
+}
+
+func newProperContentsIterator(it EIterator) *properContentsIterator {
+	return &properContentsIterator{it: it}
+}
+
+func (it *properContentsIterator) HasNext() bool {
+	for it.next == nil && it.it.HasNext() {
+		if o, _ := it.it.Next().(EObjectInternal); o != nil && !o.EIsProxy() && o.EInternalResource() == nil {
+			it.next = o
+		}
+	}
+	return it.next != nil
+}
+
+func (it *properContentsIterator) Next() any {
+	if !it.HasNext() {
+		panic("Not such an element")
+	}
+	next := it.next
+	it.next = nil
+	return next
+}
+
 func (r *EResourceImpl) GetEObject(uriFragment string) EObject {
 	id := uriFragment
 	size := len(uriFragment)
@@ -312,14 +451,48 @@ func (r *EResourceImpl) getObjectByID(id string) EObject {
 	if r.objectIDManager != nil {
 		return r.objectIDManager.GetEObject(id)
 	}
-	for it := r.getAllContentsResolve(r.GetInterfaces(), false); it.HasNext(); {
-		eObject := it.Next().(EObject)
-		objectID := GetEObjectID(eObject)
-		if id == objectID {
-			return eObject
+	var eObject EObject
+	r.withIntrinsicIDs(true, func(index *intrinsicIDIndex) {
+		eObject = index.getEObject(id)
+	})
+	return eObject
+}
+
+// withIntrinsicIDs calls f with the index of the objects by their intrinsic id if it exists
+// or if it must be built. Duplicate id warnings are updated once the index is unlocked
+func (r *EResourceImpl) withIntrinsicIDs(build bool, f func(index *intrinsicIDIndex)) {
+	r.intrinsicIDsMutex.Lock()
+	index := r.intrinsicIDs
+	if index == nil && build {
+		index = newIntrinsicIDIndex(r)
+		for it := r.getAllContentsResolve(r.GetInterfaces(), false); it.HasNext(); {
+			if eObject, _ := it.Next().(EObject); eObject != nil {
+				index.add(eObject)
+			}
+		}
+		r.intrinsicIDs = index
+	}
+	var added, removed []EDiagnostic
+	if index != nil {
+		f(index)
+		added, removed = index.takeWarnings()
+	}
+	r.intrinsicIDsMutex.Unlock()
+	if len(added) > 0 || len(removed) > 0 {
+		warnings := r.GetWarnings()
+		for _, diagnostic := range removed {
+			warnings.Remove(diagnostic)
+		}
+		for _, diagnostic := range added {
+			warnings.Add(diagnostic)
 		}
 	}
-	return nil
+}
+
+func (r *EResourceImpl) hasIntrinsicIDs() bool {
+	r.intrinsicIDsMutex.Lock()
+	defer r.intrinsicIDsMutex.Unlock()
+	return r.intrinsicIDs != nil
 }
 
 func (r *EResourceImpl) getObjectByPath(uriFragmentPath []string) EObject {
@@ -351,7 +524,7 @@ func (r *EResourceImpl) getObjectForRootSegment(rootSegment string) EObject {
 }
 
 func (r *EResourceImpl) IsAttachedDetachedRequired() bool {
-	return r.objectIDManager != nil || (r.listeners != nil && !r.listeners.Empty())
+	return r.objectIDManager != nil || r.hasIntrinsicIDs() || (r.listeners != nil && !r.listeners.Empty())
 }
 
 func (r *EResourceImpl) Attached(object EObject) {
@@ -369,6 +542,9 @@ func (r *EResourceImpl) DoAttached(object EObject) {
 	if r.objectIDManager != nil {
 		r.objectIDManager.Register(object)
 	}
+	r.withIntrinsicIDs(false, func(index *intrinsicIDIndex) {
+		index.add(object)
+	})
 	if r.listeners != nil {
 		for itListener := r.listeners.Iterator(); itListener.HasNext(); {
 			listener := itListener.Next().(EResourceListener)
@@ -392,6 +568,9 @@ func (r *EResourceImpl) DoDetached(object EObject) {
 	if r.objectIDManager != nil {
 		r.objectIDManager.UnRegister(object)
 	}
+	r.withIntrinsicIDs(false, func(index *intrinsicIDIndex) {
+		index.remove(object)
+	})
 	if r.listeners != nil {
 		for itListener := r.listeners.Iterator(); itListener.HasNext(); {
 			listener := itListener.Next().(EResourceListener)
@@ -421,68 +600,146 @@ func (r *EResourceImpl) Load() {
 }
 
 func (r *EResourceImpl) LoadWithOptions(options map[string]any) {
+	_ = r.LoadContext(context.Background(), options)
+}
+
+func (r *EResourceImpl) LoadWithReader(rd io.Reader, options map[string]any) {
 	if !r.isLoaded {
-		uriConverter := r.getURIConverter()
-		if uriConverter != nil && r.uri != nil {
-			rd, err := uriConverter.CreateReader(r.uri)
-			if err != nil {
-				errors := r.GetErrors()
-				errors.Clear()
-				errors.Add(NewEDiagnosticImpl("Unable to create reader for '"+r.uri.String()+"' :"+err.Error(), r.uri.String(), 0, 0))
-			} else if rd != nil {
-				r.LoadWithReader(rd, options)
-				rd.Close()
-			}
-		}
+		start := r.beforeLoad(options)
+		r.loadWithReaderContext(context.Background(), rd, options)
+		r.afterLoad(options, start)
 	}
 }
 
-func (r *EResourceImpl) LoadWithReader(rd io.Reader, options map[string]any) {
+// LoadContext loads the resource and returns its first error if any.
+// If ctx is done before the end of the loading, the resource is left unloaded and ctx error is returned
+func (r *EResourceImpl) LoadContext(ctx context.Context, options map[string]any) error {
 	if !r.isLoaded {
-		codecs := r.GetCodecRegistry()
-		if codec := codecs.GetCodec(r.uri); codec == nil {
-			errors := r.GetErrors()
-			errors.Clear()
-			errors.Add(NewEDiagnosticImpl("Unable to find codec for '"+r.uri.String()+"'", r.uri.String(), 0, 0))
-		} else if decoder := codec.NewDecoder(r.AsEResource(), rd, options); decoder == nil {
+		start := r.beforeLoad(options)
+		r.loadContext(ctx, options)
+		r.afterLoad(options, start)
+		return r.getContextError(ctx)
+	}
+	return nil
+}
+
+func (r *EResourceImpl) loadContext(ctx context.Context, options map[string]any) {
+	uriConverter := r.getURIConverter()
+	if uriConverter != nil && r.uri != nil {
+		rd, err := createReaderContext(ctx, uriConverter, r.uri)
+		if err != nil {
 			errors := r.GetErrors()
 			errors.Clear()
-			errors.Add(NewEDiagnosticImpl("Unable to create decoder for '"+r.uri.String()+"'", r.uri.String(), 0, 0))
-		} else {
-			r.isLoading = true
-			ri := r.AsEResourceInternal()
-			n := ri.BasicSetLoaded(true, nil)
-			if r.errors != nil {
-				r.errors.Clear()
-			}
-			if r.warnings != nil {
-				r.warnings.Clear()
-			}
-			ri.DoLoad(decoder)
-			if n != nil {
-				n.Dispatch()
-			}
-			r.isLoading = false
+			errors.Add(NewEDiagnosticImpl("Unable to create reader for '"+r.uri.String()+"' :"+err.Error(), r.uri.String(), 0, 0))
+		} else if rd != nil {
+			r.loadWithReaderContext(ctx, rd, options)
+			rd.Close()
 		}
 	}
 }
 
+func (r *EResourceImpl) loadWithReaderContext(ctx context.Context, rd io.Reader, options map[string]any) {
+	codecs := r.GetCodecRegistry()
+	codec := codecs.GetCodec(r.uri)
+	if codec == nil {
+		// detect codec from the beginning of the content
+		codec, rd = getContentCodec(codecs, rd)
+	}
+	if codec == nil {
+		errors := r.GetErrors()
+		errors.Clear()
+		errors.Add(NewEDiagnosticImpl("Unable to find codec for '"+r.uri.String()+"'", r.uri.String(), 0, 0))
+	} else if decoder := codec.NewDecoder(r.AsEResource(), newContextReader(ctx, rd), options); decoder == nil {
+		errors := r.GetErrors()
+		errors.Clear()
+		errors.Add(NewEDiagnosticImpl("Unable to create decoder for '"+r.uri.String()+"'", r.uri.String(), 0, 0))
+	} else {
+		r.isLoading = true
+		ri := r.AsEResourceInternal()
+		n := ri.BasicSetLoaded(true, nil)
+		if r.errors != nil {
+			r.errors.Clear()
+		}
+		if r.warnings != nil {
+			r.warnings.Clear()
+		}
+		ri.DoLoad(newContextDecoder(ctx, decoder))
+		if r.objectIDManager == nil {
+			// index intrinsic ids of loaded objects to detect duplicates
+			r.withIntrinsicIDs(true, func(*intrinsicIDIndex) {})
+		}
+		if n != nil {
+			n.Dispatch()
+		}
+		r.isLoading = false
+		r.SetModified(false)
+		if err := ctx.Err(); err != nil {
+			// partially decoded contents are discarded: listeners are only notified of the failed load
+			r.unload(false)
+			r.GetErrors().Add(NewEDiagnosticImpl("Loading of '"+r.uri.String()+"' interrupted :"+err.Error(), r.uri.String(), 0, 0))
+		}
+	}
+}
+
+// getContextError returns ctx error if any or the first error of the resource
+func (r *EResourceImpl) getContextError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.errors != nil && !r.errors.Empty() {
+		return r.errors.Get(0).(EDiagnostic)
+	}
+	return nil
+}
+
 func (r *EResourceImpl) DoLoad(decoder EDecoder) {
 	decoder.DecodeResource()
 }
 
 func (r *EResourceImpl) Unload() {
+	r.unload(true)
+}
+
+func (r *EResourceImpl) unload(notifyListeners bool) {
 	if r.isLoaded {
 		n := r.BasicSetLoaded(false, nil)
 		r.GetInterfaces().(EResourceInternal).DoUnload()
 		if n != nil {
 			n.Dispatch()
 		}
+		if notifyListeners {
+			resource := r.AsEResource()
+			r.notifyLifecycleListeners(func(listener EResourceLifecycleListener) {
+				listener.Unloaded(resource)
+			})
+		}
 	}
 }
 
 func (r *EResourceImpl) DoUnload() {
+	if r.contents != nil && r.uri != nil {
+		// unloaded objects become proxies to their location so that references
+		// to them are resolved again through the resource set
+		unloaded := []EObjectInternal{}
+		uris := []*URI{}
+		for it := r.getAllContentsResolve(r.GetInterfaces(), false); it.HasNext(); {
+			if eObject, _ := it.Next().(EObjectInternal); eObject != nil && !eObject.EIsProxy() {
+				unloaded = append(unloaded, eObject)
+				uris = append(uris, NewURIBuilder(r.uri).SetFragment(r.GetURIFragment(eObject)).URI())
+			}
+		}
+		for i, eObject := range unloaded {
+			eObject.ESetProxyURI(uris[i])
+			if notifier, _ := eObject.(ENotifierInternal); notifier != nil && notifier.EBasicHasAdapters() {
+				notifier.EBasicAdapters().Clear()
+			}
+		}
+	}
+	r.intrinsicIDsMutex.Lock()
+	r.intrinsicIDs = nil
+	r.intrinsicIDsMutex.Unlock()
 	r.contents = nil
+	r.extensions = nil
 	r.errors = nil
 	r.warnings = nil
 	if r.objectIDManager != nil {
@@ -502,32 +759,137 @@ func (r *EResourceImpl) SetLoading(isLoading bool) {
 	r.isLoading = isLoading
 }
 
+func (r *EResourceImpl) IsModified() bool {
+	return r.isModified
+}
+
+func (r *EResourceImpl) SetModified(isModified bool) {
+	oldModified := r.isModified
+	r.isModified = isModified
+	if oldModified != isModified && r.ENotificationRequired() {
+		r.ENotify(newResourceNotification(r.AsENotifier(), RESOURCE__IS_MODIFIED, SET, oldModified, isModified, -1))
+	}
+}
+
+func (r *EResourceImpl) IsTrackingModification() bool {
+	return r.modificationAdapter != nil
+}
+
+// SetTrackingModification installs or removes the content adapter maintaining modification state of the resource
+func (r *EResourceImpl) SetTrackingModification(isTrackingModification bool) {
+	if isTrackingModification && r.modificationAdapter == nil {
+		r.modificationAdapter = newResourceModificationAdapter(r)
+		r.EAdapters().Add(r.modificationAdapter)
+	} else if !isTrackingModification && r.modificationAdapter != nil {
+		r.EAdapters().Remove(r.modificationAdapter)
+		r.modificationAdapter = nil
+	}
+}
+
 func (r *EResourceImpl) Save() {
 	r.SaveWithOptions(nil)
 }
 
 func (r *EResourceImpl) SaveWithOptions(options map[string]any) {
+	_ = r.SaveContext(context.Background(), options)
+}
+
+func (r *EResourceImpl) SaveWithWriter(w io.Writer, options map[string]any) {
+	if r.isSaveRequired(options) {
+		start := r.beforeSave(options)
+		r.saveWithWriterContext(context.Background(), w, options)
+		r.saved(context.Background())
+		r.afterSave(options, start)
+	}
+}
+
+// SaveContext saves the resource and returns its first error if any.
+// If ctx is done before the end of the saving, writer is aborted when possible and ctx error is returned
+func (r *EResourceImpl) SaveContext(ctx context.Context, options map[string]any) error {
+	if r.isSaveRequired(options) {
+		start := r.beforeSave(options)
+		r.saveContext(ctx, options)
+		r.saved(ctx)
+		r.afterSave(options, start)
+		return r.getContextError(ctx)
+	}
+	return nil
+}
+
+// saved resets modification state of the resource if it has been saved successfully
+func (r *EResourceImpl) saved(ctx context.Context) {
+	if ctx.Err() == nil && (r.errors == nil || r.errors.Empty()) {
+		r.SetModified(false)
+	}
+}
+
+// isSaveRequired returns false if resource is saved only if modified and is not modified.
+// Modification state is only known when the resource is tracking modification
+func (r *EResourceImpl) isSaveRequired(options map[string]any) bool {
+	onlyIfModified, _ := options[RESOURCE_OPTION_SAVE_ONLY_IF_MODIFIED].(bool)
+	return !onlyIfModified || !r.IsTrackingModification() || r.isModified
+}
+
+func (r *EResourceImpl) saveContext(ctx context.Context, options map[string]any) {
 	uriConverter := r.getURIConverter()
-	if uriConverter != nil && r.uri != nil {
-		w, err := uriConverter.CreateWriter(r.uri)
+	if onlyIfChanged, _ := options[RESOURCE_OPTION_SAVE_ONLY_IF_CHANGED].(bool); onlyIfChanged && uriConverter != nil && r.uri != nil {
+		r.saveIfChangedContext(ctx, uriConverter, options)
+	} else if uriConverter != nil && r.uri != nil {
+		w, err := createWriterContext(ctx, uriConverter, r.uri)
 		if err != nil {
 			errors := r.GetErrors()
 			errors.Clear()
 			errors.Add(NewEDiagnosticImpl("Unable to create writer for '"+r.uri.String()+"' :"+err.Error(), r.uri.String(), 0, 0))
 		} else if w != nil {
-			r.SaveWithWriter(w, options)
-			w.Close()
+			r.saveWithWriterContext(ctx, w, options)
+			if abortWriter, _ := w.(EAbortWriter); abortWriter != nil && (!r.GetErrors().Empty() || ctx.Err() != nil) {
+				// previous version is kept when encoding failed
+				abortWriter.Abort()
+			} else if err := w.Close(); err != nil {
+				r.GetErrors().Add(NewEDiagnosticImpl("Unable to close writer for '"+r.uri.String()+"' :"+err.Error(), r.uri.String(), 0, 0))
+			}
 		}
 	}
 }
 
-func (r *EResourceImpl) SaveWithWriter(w io.Writer, options map[string]any) {
+// saveIfChangedContext encodes the resource in memory and writes it only if it differs from the existing one
+func (r *EResourceImpl) saveIfChangedContext(ctx context.Context, uriConverter EURIConverter, options map[string]any) {
+	var buffer bytes.Buffer
+	r.saveWithWriterContext(ctx, &buffer, options)
+	if !r.GetErrors().Empty() || ctx.Err() != nil {
+		return
+	}
+	if rd, err := createReaderContext(ctx, uriConverter, r.uri); err == nil {
+		previous, err := io.ReadAll(rd)
+		rd.Close()
+		if err == nil && bytes.Equal(previous, buffer.Bytes()) {
+			return
+		}
+	}
+	w, err := createWriterContext(ctx, uriConverter, r.uri)
+	if err != nil {
+		r.GetErrors().Add(NewEDiagnosticImpl("Unable to create writer for '"+r.uri.String()+"' :"+err.Error(), r.uri.String(), 0, 0))
+		return
+	}
+	if _, err := w.Write(buffer.Bytes()); err != nil {
+		r.GetErrors().Add(NewEDiagnosticImpl("Unable to write '"+r.uri.String()+"' :"+err.Error(), r.uri.String(), 0, 0))
+		if abortWriter, _ := w.(EAbortWriter); abortWriter != nil {
+			abortWriter.Abort()
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		r.GetErrors().Add(NewEDiagnosticImpl("Unable to close writer for '"+r.uri.String()+"' :"+err.Error(), r.uri.String(), 0, 0))
+	}
+}
+
+func (r *EResourceImpl) saveWithWriterContext(ctx context.Context, w io.Writer, options map[string]any) {
 	codecs := r.GetCodecRegistry()
 	if codec := codecs.GetCodec(r.uri); codec == nil {
 		errors := r.GetErrors()
 		errors.Clear()
 		errors.Add(NewEDiagnosticImpl("Unable to find codec for '"+r.uri.String()+"'", r.uri.String(), 0, 0))
-	} else if encoder := codec.NewEncoder(r.AsEResource(), w, options); encoder == nil {
+	} else if encoder := codec.NewEncoder(r.AsEResource(), newContextWriter(ctx, w), options); encoder == nil {
 		errors := r.GetErrors()
 		errors.Clear()
 		errors.Add(NewEDiagnosticImpl("Unable to create encoder for '"+r.uri.String()+"'", r.uri.String(), 0, 0))
@@ -538,7 +900,7 @@ func (r *EResourceImpl) SaveWithWriter(w io.Writer, options map[string]any) {
 		if r.warnings != nil {
 			r.warnings.Clear()
 		}
-		r.GetInterfaces().(EResourceInternal).DoSave(encoder)
+		r.GetInterfaces().(EResourceInternal).DoSave(newContextEncoder(ctx, encoder))
 	}
 }
 
@@ -592,8 +954,21 @@ func (r *EResourceImpl) BasicSetResourceSet(resourceSet EResourceSet, msgs ENoti
 
 func (r *EResourceImpl) SetObjectIDManager(objectIDManager EObjectIDManager) {
 	r.objectIDManager = objectIDManager
+	// ids are managed by the object id manager
+	r.withIntrinsicIDs(false, func(index *intrinsicIDIndex) {
+		index.clear()
+		r.intrinsicIDs = nil
+	})
 }
 
 func (r *EResourceImpl) GetObjectIDManager() EObjectIDManager {
 	return r.objectIDManager
 }
+
+// GetEObjectToExtensionMap returns the content of objects unknown to their class
+func (r *EResourceImpl) GetEObjectToExtensionMap() map[EObject]*XMLExtension {
+	if r.extensions == nil {
+		r.extensions = map[EObject]*XMLExtension{}
+	}
+	return r.extensions
+}
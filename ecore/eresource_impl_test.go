@@ -1,10 +1,16 @@
 package ecore
 
 import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResourceURI(t *testing.T) {
@@ -50,6 +56,300 @@ func TestResourceLoadInvalid(t *testing.T) {
 	assert.False(t, r.GetErrors().Empty())
 }
 
+func TestResourceLoadContext(t *testing.T) {
+	resourceSet := NewEResourceSetImpl()
+	loadTestPackage(t, resourceSet, NewURI("testdata/library.complex.ecore"))
+	r := resourceSet.CreateResource(NewURI("testdata/library.complex.xml"))
+	require.NoError(t, r.LoadContext(context.Background(), nil))
+	assert.True(t, r.IsLoaded())
+	assert.Equal(t, 1, r.GetContents().Size())
+}
+
+func TestResourceLoadContext_Invalid(t *testing.T) {
+	r := NewEResourceImpl()
+	r.SetURI(NewURI("testdata/invalid.xml"))
+	err := r.LoadContext(context.Background(), nil)
+	require.Error(t, err)
+	assert.Equal(t, r.GetErrors().Get(0), err)
+}
+
+func TestResourceLoadContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockCodec := NewMockECodec(t)
+	mockDecoder := NewMockEDecoder(t)
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetCodecRegistry().GetExtensionToCodecMap()["xml"] = mockCodec
+	resource := resourceSet.CreateResource(NewURI("testdata/library.complex.xml"))
+	listener := &testLifecycleListener{}
+	resource.GetResourceLifecycleListeners().Add(listener)
+
+	// decoding is canceled after first object
+	eObject := NewDynamicEObjectImpl()
+	eObject.SetEClass(GetFactory().CreateEClass())
+	mockCodec.EXPECT().NewDecoder(resource, mock.Anything, mock.Anything).Return(mockDecoder).Once()
+	mockDecoder.EXPECT().DecodeResource().Run(func() {
+		resource.GetContents().Add(eObject)
+		cancel()
+	}).Once()
+	err := resource.LoadContext(ctx, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, resource.IsLoaded())
+	assert.True(t, resource.GetContents().Empty())
+	assert.Equal(t, 1, resource.GetErrors().Size())
+	assert.True(t, eObject.EIsProxy())
+	assert.Equal(t, []string{"BeforeLoad", "LoadFailed"}, listener.events)
+
+	// already canceled
+	err = resource.LoadContext(ctx, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, resource.IsLoaded())
+}
+
+type testContextDecoder struct {
+	*MockEDecoder
+	ctx context.Context
+}
+
+func (d *testContextDecoder) DecodeResourceContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+func TestResourceLoadContext_ContextDecoder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockCodec := NewMockECodec(t)
+	decoder := &testContextDecoder{MockEDecoder: NewMockEDecoder(t)}
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetCodecRegistry().GetExtensionToCodecMap()["xml"] = mockCodec
+	resource := resourceSet.CreateResource(NewURI("testdata/library.complex.xml"))
+	mockCodec.EXPECT().NewDecoder(resource, mock.Anything, mock.Anything).Return(decoder).Once()
+	require.NoError(t, resource.LoadContext(ctx, nil))
+	assert.True(t, resource.IsLoaded())
+	assert.Equal(t, ctx, decoder.ctx)
+}
+
+func TestResourceSaveContext(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(fileName, []byte("previous"), 0644))
+
+	mockCodec := NewMockECodec(t)
+	mockEncoder := NewMockEEncoder(t)
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetCodecRegistry().GetExtensionToCodecMap()["txt"] = mockCodec
+	resource := resourceSet.CreateResource(CreateFileURI(fileName))
+
+	// encoding succeeds
+	mockCodec.EXPECT().NewEncoder(resource, mock.Anything, mock.Anything).Return(mockEncoder).Once()
+	mockEncoder.EXPECT().EncodeResource().Once()
+	require.NoError(t, resource.SaveContext(context.Background(), nil))
+
+	// encoding is canceled: previous version is kept
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, os.WriteFile(fileName, []byte("previous"), 0644))
+	mockCodec.EXPECT().NewEncoder(resource, mock.Anything, mock.Anything).Return(mockEncoder).Once()
+	mockEncoder.EXPECT().EncodeResource().Run(cancel).Once()
+	assert.ErrorIs(t, resource.SaveContext(ctx, nil), context.Canceled)
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+
+	// already canceled
+	assert.ErrorIs(t, resource.SaveContext(ctx, nil), context.Canceled)
+	assert.False(t, resource.GetErrors().Empty())
+}
+
+type testLifecycleListener struct {
+	AbstractEResourceLifecycleListener
+	events      []string
+	diagnostics []EDiagnostic
+	durations   []time.Duration
+}
+
+func (l *testLifecycleListener) BeforeLoad(resource EResource, options map[string]any) {
+	l.events = append(l.events, "BeforeLoad")
+}
+
+func (l *testLifecycleListener) AfterLoad(resource EResource, options map[string]any, duration time.Duration) {
+	l.events = append(l.events, "AfterLoad")
+	l.durations = append(l.durations, duration)
+}
+
+func (l *testLifecycleListener) LoadFailed(resource EResource, options map[string]any, diagnostics []EDiagnostic) {
+	l.events = append(l.events, "LoadFailed")
+	l.diagnostics = diagnostics
+}
+
+func (l *testLifecycleListener) BeforeSave(resource EResource, options map[string]any) {
+	l.events = append(l.events, "BeforeSave")
+}
+
+func (l *testLifecycleListener) AfterSave(resource EResource, options map[string]any, duration time.Duration) {
+	l.events = append(l.events, "AfterSave")
+	l.durations = append(l.durations, duration)
+}
+
+func (l *testLifecycleListener) Unloaded(resource EResource) {
+	l.events = append(l.events, "Unloaded")
+}
+
+func TestResourceLoadWithoutExtension(t *testing.T) {
+	for _, fileName := range []string{"library.complex.xml", "library.complex.bin", "library.complex.sqlite"} {
+		content, err := os.ReadFile(filepath.Join("testdata", fileName))
+		require.NoError(t, err)
+		modelPath := filepath.Join(t.TempDir(), "model.dat")
+		require.NoError(t, os.WriteFile(modelPath, content, 0644))
+
+		resourceSet := NewEResourceSetImpl()
+		loadTestPackage(t, resourceSet, NewURI("testdata/library.complex.ecore"))
+		r := resourceSet.CreateResource(CreateFileURI(modelPath))
+		require.NoError(t, r.LoadContext(context.Background(), nil), fileName)
+		assert.Equal(t, 1, r.GetContents().Size(), fileName)
+	}
+}
+
+func TestResourceLifecycleListeners(t *testing.T) {
+	resourceSet := NewEResourceSetImpl()
+	loadTestPackage(t, resourceSet, NewURI("testdata/library.complex.ecore"))
+	resourceSetListener := &testLifecycleListener{}
+	resourceSet.GetResourceLifecycleListeners().Add(resourceSetListener)
+
+	r := resourceSet.CreateResource(NewURI("testdata/library.complex.xml"))
+	resourceListener := &testLifecycleListener{}
+	r.GetResourceLifecycleListeners().Add(resourceListener)
+
+	r.Load()
+	r.SaveWithWriter(io.Discard, nil)
+	r.Unload()
+	expected := []string{"BeforeLoad", "AfterLoad", "BeforeSave", "AfterSave", "Unloaded"}
+	assert.Equal(t, expected, resourceListener.events)
+	assert.Equal(t, expected, resourceSetListener.events)
+	assert.Len(t, resourceListener.durations, 2)
+}
+
+func TestResourceLifecycleListeners_LoadFailed(t *testing.T) {
+	r := NewEResourceImpl()
+	r.SetURI(NewURI("testdata/invalid.xml"))
+	listener := &testLifecycleListener{}
+	r.GetResourceLifecycleListeners().Add(listener)
+	r.Load()
+	assert.Equal(t, []string{"BeforeLoad", "LoadFailed"}, listener.events)
+	require.Len(t, listener.diagnostics, r.GetErrors().Size())
+	assert.Equal(t, r.GetErrors().Get(0), listener.diagnostics[0])
+}
+
+func TestResourceModification(t *testing.T) {
+	resourceSet := NewEResourceSetImpl()
+	loadTestPackage(t, resourceSet, NewURI("testdata/library.complex.ecore"))
+	r := resourceSet.CreateResource(NewURI("testdata/library.complex.xml"))
+	r.SetTrackingModification(true)
+	assert.True(t, r.IsTrackingModification())
+	r.Load()
+	require.True(t, r.GetErrors().Empty(), diagnosticError(r.GetErrors()))
+	assert.False(t, r.IsModified())
+
+	// modification of an object is notified
+	mockAdapter := NewMockEAdapter(t)
+	mockAdapter.EXPECT().SetTarget(r).Once()
+	r.EAdapters().Add(mockAdapter)
+	mockAdapter.EXPECT().NotifyChanged(mock.MatchedBy(func(n ENotification) bool {
+		return n.GetFeatureID() == RESOURCE__IS_MODIFIED && n.GetNewValue() == true
+	})).Once()
+	mockAdapter.EXPECT().NotifyChanged(mock.MatchedBy(func(n ENotification) bool {
+		return n.GetFeatureID() == RESOURCE__IS_MODIFIED && n.GetNewValue() == false
+	})).Once()
+	mockAdapter.EXPECT().NotifyChanged(mock.Anything).Maybe()
+	eRoot := r.GetContents().Get(0).(EObject)
+	eLibrary := eRoot.EGet(eRoot.EClass().GetEStructuralFeatureFromName("library")).(EObject)
+	eLibrary.ESet(eLibrary.EClass().GetEStructuralFeatureFromName("name"), "Other Library")
+	assert.True(t, r.IsModified())
+
+	// save resets modification
+	r.SaveWithWriter(io.Discard, nil)
+	assert.False(t, r.IsModified())
+
+	// contents modification
+	r.GetContents().Clear()
+	assert.True(t, r.IsModified())
+
+	// no more tracking
+	r.SetModified(false)
+	r.SetTrackingModification(false)
+	assert.False(t, r.IsTrackingModification())
+	r.GetContents().Add(eRoot)
+	assert.False(t, r.IsModified())
+}
+
+func TestResourceSaveOnlyIfModified(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "file.txt")
+	mockCodec := NewMockECodec(t)
+	mockEncoder := NewMockEEncoder(t)
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetCodecRegistry().GetExtensionToCodecMap()["txt"] = mockCodec
+	resource := resourceSet.CreateResource(CreateFileURI(fileName))
+	options := map[string]any{RESOURCE_OPTION_SAVE_ONLY_IF_MODIFIED: true}
+
+	// not tracking modification: always saved
+	mockCodec.EXPECT().NewEncoder(resource, mock.Anything, mock.Anything).Return(mockEncoder).Once()
+	mockEncoder.EXPECT().EncodeResource().Once()
+	require.NoError(t, resource.SaveContext(context.Background(), options))
+	_, err := os.Stat(fileName)
+	assert.NoError(t, err)
+	require.NoError(t, os.Remove(fileName))
+
+	// not modified
+	resource.SetTrackingModification(true)
+	require.NoError(t, resource.SaveContext(context.Background(), options))
+	_, err = os.Stat(fileName)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// modified
+	resource.GetContents().Add(NewDynamicEObjectImpl())
+	assert.True(t, resource.IsModified())
+	mockCodec.EXPECT().NewEncoder(resource, mock.Anything, mock.Anything).Return(mockEncoder).Once()
+	mockEncoder.EXPECT().EncodeResource().Once()
+	require.NoError(t, resource.SaveContext(context.Background(), options))
+	_, err = os.Stat(fileName)
+	assert.NoError(t, err)
+	assert.False(t, resource.IsModified())
+}
+
+func TestResourceSaveOnlyIfChanged(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(fileName, []byte("content"), 0644))
+	modTime := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(fileName, modTime, modTime))
+
+	mockCodec := NewMockECodec(t)
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetCodecRegistry().GetExtensionToCodecMap()["txt"] = mockCodec
+	resource := resourceSet.CreateResource(CreateFileURI(fileName))
+	options := map[string]any{RESOURCE_OPTION_SAVE_ONLY_IF_CHANGED: true}
+	encode := func(content string) {
+		var writer io.Writer
+		mockEncoder := NewMockEEncoder(t)
+		mockCodec.EXPECT().NewEncoder(resource, mock.Anything, mock.Anything).Run(func(r EResource, w io.Writer, m map[string]any) { writer = w }).Return(mockEncoder).Once()
+		mockEncoder.EXPECT().EncodeResource().Run(func() { writer.Write([]byte(content)) }).Once()
+	}
+
+	// same content: file is not written
+	encode("content")
+	require.NoError(t, resource.SaveContext(context.Background(), options))
+	info, err := os.Stat(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, modTime.Unix(), info.ModTime().Unix())
+
+	// different content
+	encode("new content")
+	require.NoError(t, resource.SaveContext(context.Background(), options))
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "new content", string(content))
+}
+
 func TestResourceGetURIFragment(t *testing.T) {
 
 	// id attribute
@@ -196,3 +496,91 @@ func TestResourceListeners(t *testing.T) {
 	mockObject.EXPECT().EContents().Return(NewEmptyImmutableEList())
 	eResource.Detached(mockObject)
 }
+
+type componentsModel struct {
+	ePackage       EPackage
+	systemClass    EClass
+	componentClass EClass
+	systemName     EStructuralFeature
+	components     EStructuralFeature
+	main           EStructuralFeature
+	componentName  EStructuralFeature
+	parts          EStructuralFeature
+}
+
+func loadComponentsModel(t *testing.T) *componentsModel {
+	ePackage := loadPackage("components.ecore")
+	require.NotNil(t, ePackage)
+	m := &componentsModel{ePackage: ePackage}
+	m.systemClass = ePackage.GetEClassifier("System").(EClass)
+	m.componentClass = ePackage.GetEClassifier("Component").(EClass)
+	m.systemName = m.systemClass.GetEStructuralFeatureFromName("name")
+	m.components = m.systemClass.GetEStructuralFeatureFromName("components")
+	m.main = m.systemClass.GetEStructuralFeatureFromName("main")
+	m.componentName = m.componentClass.GetEStructuralFeatureFromName("name")
+	m.parts = m.componentClass.GetEStructuralFeatureFromName("parts")
+	return m
+}
+
+func (m *componentsModel) newComponent(name string) EObject {
+	eObject := m.ePackage.GetEFactoryInstance().Create(m.componentClass)
+	eObject.ESet(m.componentName, name)
+	return eObject
+}
+
+// newSystem creates in resourceSet a system with two components and a main component.
+// The second component and the main component are in their own resources
+func (m *componentsModel) newSystem(resourceSet EResourceSet, dir, ext string) (system EObject, resources []EResource) {
+	for _, name := range []string{"system", "component", "main"} {
+		uri := NewURI(filepath.ToSlash(filepath.Join(dir, "components."+name+"."+ext)))
+		resources = append(resources, resourceSet.CreateResource(uri))
+	}
+	system = m.ePackage.GetEFactoryInstance().Create(m.systemClass)
+	system.ESet(m.systemName, "system")
+	resources[0].GetContents().Add(system)
+	c1 := m.newComponent("c1")
+	c2 := m.newComponent("c2")
+	c2.EGet(m.parts).(EList).Add(m.newComponent("c2.1"))
+	main := m.newComponent("main")
+	system.EGet(m.components).(EList).AddAll(NewImmutableEList([]any{c1, c2}))
+	system.ESet(m.main, main)
+	resources[1].GetContents().Add(c2)
+	resources[2].GetContents().Add(main)
+	return
+}
+
+func TestEResourceImpl_ContainmentProxy(t *testing.T) {
+	m := loadComponentsModel(t)
+	resourceSet := NewEResourceSetImpl()
+	system, resources := m.newSystem(resourceSet, "", "xml")
+	components := system.EGet(m.components).(EList)
+	c1 := components.Get(0).(EObject)
+	c2 := components.Get(1).(EObject)
+	c21 := c2.EGet(m.parts).(EList).Get(0).(EObject)
+
+	// c2 is contained by system but is in its own resource
+	assert.Equal(t, system, c2.EContainer())
+	assert.Equal(t, m.components, c2.EContainmentFeature())
+	assert.Equal(t, resources[0], c1.EResource())
+	assert.Equal(t, resources[1], c2.EResource())
+	assert.Equal(t, resources[1], c21.EResource())
+	assert.Equal(t, "/", resources[1].GetURIFragment(c2))
+
+	// resource contents do not include objects of other resources
+	assert.Equal(t, []any{system, c1}, allContents(resources[0]))
+	assert.Equal(t, []any{c2, c21}, allContents(resources[1]))
+
+	// removing c2 from its resource brings it back in the resource of its container
+	resources[1].GetContents().Remove(c2)
+	assert.Equal(t, system, c2.EContainer())
+	assert.Equal(t, resources[0], c2.EResource())
+	assert.Equal(t, []any{system, c1, c2, c21}, allContents(resources[0]))
+}
+
+func allContents(resource EResource) []any {
+	contents := []any{}
+	for it := resource.GetAllContents(); it.HasNext(); {
+		contents = append(contents, it.Next())
+	}
+	return contents
+}
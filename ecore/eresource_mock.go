@@ -10,6 +10,7 @@
 package ecore
 
 import (
+	context "context"
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -188,6 +189,44 @@ func (_c *MockEResource_GetEObject_Call) Return(_a0 EObject) *MockEResource_GetE
 	return _c
 }
 
+// GetEObjectToExtensionMap provides a mock function with given fields:
+func (_m *MockEResource_Prototype_Methods) GetEObjectToExtensionMap() map[EObject]*XMLExtension {
+	ret := _m.mock.Called()
+
+	var r0 map[EObject]*XMLExtension
+	if rf, ok := ret.Get(0).(func() map[EObject]*XMLExtension); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[EObject]*XMLExtension)
+		}
+	}
+
+	return r0
+}
+
+// MockEResource_GetEObjectToExtensionMap_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEObjectToExtensionMap'
+type MockEResource_GetEObjectToExtensionMap_Call struct {
+	*mock.Call
+}
+
+// GetEObjectToExtensionMap is a helper method to define mock.On call
+func (_e *MockEResource_Expecter_Methods) GetEObjectToExtensionMap() *MockEResource_GetEObjectToExtensionMap_Call {
+	return &MockEResource_GetEObjectToExtensionMap_Call{Call: _e.mock.On("GetEObjectToExtensionMap")}
+}
+
+func (_c *MockEResource_GetEObjectToExtensionMap_Call) Run(run func()) *MockEResource_GetEObjectToExtensionMap_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockEResource_GetEObjectToExtensionMap_Call) Return(_a0 map[EObject]*XMLExtension) *MockEResource_GetEObjectToExtensionMap_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // GetErrors provides a mock function with given fields:
 func (_m *MockEResource_Prototype_Methods) GetErrors() EList {
 	ret := _m.mock.Called()
@@ -264,6 +303,44 @@ func (_c *MockEResource_GetObjectIDManager_Call) Return(_a0 EObjectIDManager) *M
 	return _c
 }
 
+// GetResourceLifecycleListeners provides a mock function with given fields:
+func (_m *MockEResource_Prototype_Methods) GetResourceLifecycleListeners() EList {
+	ret := _m.mock.Called()
+
+	var r0 EList
+	if rf, ok := ret.Get(0).(func() EList); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(EList)
+		}
+	}
+
+	return r0
+}
+
+// MockEResource_GetResourceLifecycleListeners_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResourceLifecycleListeners'
+type MockEResource_GetResourceLifecycleListeners_Call struct {
+	*mock.Call
+}
+
+// GetResourceLifecycleListeners is a helper method to define mock.On call
+func (_e *MockEResource_Expecter_Methods) GetResourceLifecycleListeners() *MockEResource_GetResourceLifecycleListeners_Call {
+	return &MockEResource_GetResourceLifecycleListeners_Call{Call: _e.mock.On("GetResourceLifecycleListeners")}
+}
+
+func (_c *MockEResource_GetResourceLifecycleListeners_Call) Run(run func()) *MockEResource_GetResourceLifecycleListeners_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockEResource_GetResourceLifecycleListeners_Call) Return(_a0 EList) *MockEResource_GetResourceLifecycleListeners_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // GetResourceListeners provides a mock function with given fields:
 func (_m *MockEResource_Prototype_Methods) GetResourceListeners() EList {
 	ret := _m.mock.Called()
@@ -525,6 +602,78 @@ func (_c *MockEResource_IsLoading_Call) Return(_a0 bool) *MockEResource_IsLoadin
 	return _c
 }
 
+// IsModified provides a mock function with given fields:
+func (_m *MockEResource_Prototype_Methods) IsModified() bool {
+	ret := _m.mock.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockEResource_IsModified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsModified'
+type MockEResource_IsModified_Call struct {
+	*mock.Call
+}
+
+// IsModified is a helper method to define mock.On call
+func (_e *MockEResource_Expecter_Methods) IsModified() *MockEResource_IsModified_Call {
+	return &MockEResource_IsModified_Call{Call: _e.mock.On("IsModified")}
+}
+
+func (_c *MockEResource_IsModified_Call) Run(run func()) *MockEResource_IsModified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockEResource_IsModified_Call) Return(_a0 bool) *MockEResource_IsModified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+// IsTrackingModification provides a mock function with given fields:
+func (_m *MockEResource_Prototype_Methods) IsTrackingModification() bool {
+	ret := _m.mock.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockEResource_IsTrackingModification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsTrackingModification'
+type MockEResource_IsTrackingModification_Call struct {
+	*mock.Call
+}
+
+// IsTrackingModification is a helper method to define mock.On call
+func (_e *MockEResource_Expecter_Methods) IsTrackingModification() *MockEResource_IsTrackingModification_Call {
+	return &MockEResource_IsTrackingModification_Call{Call: _e.mock.On("IsTrackingModification")}
+}
+
+func (_c *MockEResource_IsTrackingModification_Call) Run(run func()) *MockEResource_IsTrackingModification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockEResource_IsTrackingModification_Call) Return(_a0 bool) *MockEResource_IsTrackingModification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // Load provides a mock function with given fields:
 func (_m *MockEResource_Prototype_Methods) Load() {
 	_m.mock.Called()
@@ -552,6 +701,44 @@ func (_c *MockEResource_Load_Call) Return() *MockEResource_Load_Call {
 	return _c
 }
 
+// LoadContext provides a mock function with given fields: ctx, options
+func (_m *MockEResource_Prototype_Methods) LoadContext(ctx context.Context, options map[string]interface{}) error {
+	ret := _m.mock.Called(ctx, options)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) error); ok {
+		r0 = rf(ctx, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockEResource_LoadContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadContext'
+type MockEResource_LoadContext_Call struct {
+	*mock.Call
+}
+
+// LoadContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - options map[string]interface{}
+func (_e *MockEResource_Expecter_Methods) LoadContext(ctx interface{}, options interface{}) *MockEResource_LoadContext_Call {
+	return &MockEResource_LoadContext_Call{Call: _e.mock.On("LoadContext", ctx, options)}
+}
+
+func (_c *MockEResource_LoadContext_Call) Run(run func(ctx context.Context, options map[string]interface{})) *MockEResource_LoadContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(map[string]interface{}))
+	})
+	return _c
+}
+
+func (_c *MockEResource_LoadContext_Call) Return(_a0 error) *MockEResource_LoadContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // LoadWithOptions provides a mock function with given fields: options
 func (_m *MockEResource_Prototype_Methods) LoadWithOptions(options map[string]interface{}) {
 	_m.mock.Called(options)
@@ -636,6 +823,44 @@ func (_c *MockEResource_Save_Call) Return() *MockEResource_Save_Call {
 	return _c
 }
 
+// SaveContext provides a mock function with given fields: ctx, options
+func (_m *MockEResource_Prototype_Methods) SaveContext(ctx context.Context, options map[string]interface{}) error {
+	ret := _m.mock.Called(ctx, options)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}) error); ok {
+		r0 = rf(ctx, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockEResource_SaveContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveContext'
+type MockEResource_SaveContext_Call struct {
+	*mock.Call
+}
+
+// SaveContext is a helper method to define mock.On call
+//   - ctx context.Context
+//   - options map[string]interface{}
+func (_e *MockEResource_Expecter_Methods) SaveContext(ctx interface{}, options interface{}) *MockEResource_SaveContext_Call {
+	return &MockEResource_SaveContext_Call{Call: _e.mock.On("SaveContext", ctx, options)}
+}
+
+func (_c *MockEResource_SaveContext_Call) Run(run func(ctx context.Context, options map[string]interface{})) *MockEResource_SaveContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(map[string]interface{}))
+	})
+	return _c
+}
+
+func (_c *MockEResource_SaveContext_Call) Return(_a0 error) *MockEResource_SaveContext_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // SaveWithOptions provides a mock function with given fields: options
 func (_m *MockEResource_Prototype_Methods) SaveWithOptions(options map[string]interface{}) {
 	_m.mock.Called(options)
@@ -693,6 +918,34 @@ func (_c *MockEResource_SaveWithWriter_Call) Return() *MockEResource_SaveWithWri
 	return _c
 }
 
+// SetModified provides a mock function with given fields: isModified
+func (_m *MockEResource_Prototype_Methods) SetModified(isModified bool) {
+	_m.mock.Called(isModified)
+}
+
+// MockEResource_SetModified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetModified'
+type MockEResource_SetModified_Call struct {
+	*mock.Call
+}
+
+// SetModified is a helper method to define mock.On call
+//   - isModified bool
+func (_e *MockEResource_Expecter_Methods) SetModified(isModified interface{}) *MockEResource_SetModified_Call {
+	return &MockEResource_SetModified_Call{Call: _e.mock.On("SetModified", isModified)}
+}
+
+func (_c *MockEResource_SetModified_Call) Run(run func(isModified bool)) *MockEResource_SetModified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(bool))
+	})
+	return _c
+}
+
+func (_c *MockEResource_SetModified_Call) Return() *MockEResource_SetModified_Call {
+	_c.Call.Return()
+	return _c
+}
+
 // SetObjectIDManager provides a mock function with given fields: _a0
 func (_m *MockEResource_Prototype_Methods) SetObjectIDManager(_a0 EObjectIDManager) {
 	_m.mock.Called(_a0)
@@ -721,6 +974,34 @@ func (_c *MockEResource_SetObjectIDManager_Call) Return() *MockEResource_SetObje
 	return _c
 }
 
+// SetTrackingModification provides a mock function with given fields: isTrackingModification
+func (_m *MockEResource_Prototype_Methods) SetTrackingModification(isTrackingModification bool) {
+	_m.mock.Called(isTrackingModification)
+}
+
+// MockEResource_SetTrackingModification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTrackingModification'
+type MockEResource_SetTrackingModification_Call struct {
+	*mock.Call
+}
+
+// SetTrackingModification is a helper method to define mock.On call
+//   - isTrackingModification bool
+func (_e *MockEResource_Expecter_Methods) SetTrackingModification(isTrackingModification interface{}) *MockEResource_SetTrackingModification_Call {
+	return &MockEResource_SetTrackingModification_Call{Call: _e.mock.On("SetTrackingModification", isTrackingModification)}
+}
+
+func (_c *MockEResource_SetTrackingModification_Call) Run(run func(isTrackingModification bool)) *MockEResource_SetTrackingModification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(bool))
+	})
+	return _c
+}
+
+func (_c *MockEResource_SetTrackingModification_Call) Return() *MockEResource_SetTrackingModification_Call {
+	_c.Call.Return()
+	return _c
+}
+
 // SetURI provides a mock function with given fields: _a0
 func (_m *MockEResource_Prototype_Methods) SetURI(_a0 *URI) {
 	_m.mock.Called(_a0)
@@ -11,6 +11,7 @@ package ecore
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"strings"
 	"testing"
@@ -264,3 +265,89 @@ func TestMockEResourceGetResourceListeners(t *testing.T) {
 	assert.Equal(t, l, r.GetResourceListeners())
 	assert.Equal(t, l, r.GetResourceListeners())
 }
+
+func TestMockEResourceLoadContext(t *testing.T) {
+	r := NewMockEResource(t)
+	ctx := context.Background()
+	options := make(map[string]any)
+	m := NewMockRun(t, ctx, options)
+	r.EXPECT().LoadContext(ctx, options).Return(nil).Run(func(ctx context.Context, options map[string]interface{}) { m.Run(ctx, options) }).Once()
+	r.EXPECT().LoadContext(ctx, options).Call.Return(func(context.Context, map[string]interface{}) error {
+		return context.Canceled
+	}).Once()
+	assert.Nil(t, r.LoadContext(ctx, options))
+	assert.Equal(t, context.Canceled, r.LoadContext(ctx, options))
+}
+
+func TestMockEResourceSaveContext(t *testing.T) {
+	r := NewMockEResource(t)
+	ctx := context.Background()
+	options := make(map[string]any)
+	m := NewMockRun(t, ctx, options)
+	r.EXPECT().SaveContext(ctx, options).Return(nil).Run(func(ctx context.Context, options map[string]interface{}) { m.Run(ctx, options) }).Once()
+	r.EXPECT().SaveContext(ctx, options).Call.Return(func(context.Context, map[string]interface{}) error {
+		return context.Canceled
+	}).Once()
+	assert.Nil(t, r.SaveContext(ctx, options))
+	assert.Equal(t, context.Canceled, r.SaveContext(ctx, options))
+}
+
+func TestMockEResourceGetResourceLifecycleListeners(t *testing.T) {
+	r := NewMockEResource(t)
+	l := NewMockEList(t)
+	m := NewMockRun(t)
+	r.EXPECT().GetResourceLifecycleListeners().Return(l).Run(func() { m.Run() }).Once()
+	r.EXPECT().GetResourceLifecycleListeners().Call.Return(func() EList {
+		return l
+	}).Once()
+	assert.Equal(t, l, r.GetResourceLifecycleListeners())
+	assert.Equal(t, l, r.GetResourceLifecycleListeners())
+}
+
+func TestMockEResourceIsModified(t *testing.T) {
+	r := NewMockEResource(t)
+	m := NewMockRun(t)
+	r.EXPECT().IsModified().Return(true).Run(func() { m.Run() }).Once()
+	r.EXPECT().IsModified().Call.Return(func() bool {
+		return false
+	}).Once()
+	assert.True(t, r.IsModified())
+	assert.False(t, r.IsModified())
+}
+
+func TestMockEResourceSetModified(t *testing.T) {
+	r := NewMockEResource(t)
+	m := NewMockRun(t, true)
+	r.EXPECT().SetModified(true).Return().Run(func(isModified bool) { m.Run(isModified) }).Once()
+	r.SetModified(true)
+}
+
+func TestMockEResourceIsTrackingModification(t *testing.T) {
+	r := NewMockEResource(t)
+	m := NewMockRun(t)
+	r.EXPECT().IsTrackingModification().Return(true).Run(func() { m.Run() }).Once()
+	r.EXPECT().IsTrackingModification().Call.Return(func() bool {
+		return false
+	}).Once()
+	assert.True(t, r.IsTrackingModification())
+	assert.False(t, r.IsTrackingModification())
+}
+
+func TestMockEResourceSetTrackingModification(t *testing.T) {
+	r := NewMockEResource(t)
+	m := NewMockRun(t, true)
+	r.EXPECT().SetTrackingModification(true).Return().Run(func(isTrackingModification bool) { m.Run(isTrackingModification) }).Once()
+	r.SetTrackingModification(true)
+}
+
+func TestMockEResourceGetEObjectToExtensionMap(t *testing.T) {
+	r := NewMockEResource(t)
+	e := map[EObject]*XMLExtension{}
+	m := NewMockRun(t)
+	r.EXPECT().GetEObjectToExtensionMap().Return(e).Run(func() { m.Run() }).Once()
+	r.EXPECT().GetEObjectToExtensionMap().Call.Return(func() map[EObject]*XMLExtension {
+		return e
+	}).Once()
+	assert.Equal(t, e, r.GetEObjectToExtensionMap())
+	assert.Equal(t, e, r.GetEObjectToExtensionMap())
+}
@@ -0,0 +1,199 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type resourceCacheOption interface {
+	apply(*EResourceCache)
+}
+
+type funcResourceCacheOption struct {
+	f func(*EResourceCache)
+}
+
+func (fco *funcResourceCacheOption) apply(c *EResourceCache) {
+	fco.f(c)
+}
+
+func newFuncResourceCacheOption(f func(*EResourceCache)) *funcResourceCacheOption {
+	return &funcResourceCacheOption{
+		f: f,
+	}
+}
+
+// ResourceCacheMaxResources sets the maximum number of loaded resources
+func ResourceCacheMaxResources(maxResources int) resourceCacheOption {
+	return newFuncResourceCacheOption(func(c *EResourceCache) {
+		c.maxResources = maxResources
+	})
+}
+
+// ResourceCacheMaxSize sets the maximum estimated size of loaded resources
+func ResourceCacheMaxSize(maxSize int64) resourceCacheOption {
+	return newFuncResourceCacheOption(func(c *EResourceCache) {
+		c.maxSize = maxSize
+	})
+}
+
+// ResourceCacheSizeEstimator sets the function estimating the size of a loaded resource.
+// Default estimator returns the number of objects of the resource
+func ResourceCacheSizeEstimator(estimator func(EResource) int64) resourceCacheOption {
+	return newFuncResourceCacheOption(func(c *EResourceCache) {
+		c.estimator = estimator
+	})
+}
+
+type resourceCacheEntry struct {
+	resource EResource
+	size     int64
+}
+
+// EResourceCache bounds the loaded resources of a resource set.
+// When a limit is exceeded after a loading, least recently accessed resources are unloaded:
+// references to their objects become proxies and they are loaded again when these proxies are resolved.
+// Pinned, loading and modified resources are never evicted
+type EResourceCache struct {
+	AbstractEResourceLifecycleListener
+	maxResources int
+	maxSize      int64
+	estimator    func(EResource) int64
+	mutex        sync.Mutex
+	lru          *list.List
+	entries      map[EResource]*list.Element
+	pinned       map[EResource]struct{}
+	size         int64
+}
+
+func NewEResourceCache(options ...resourceCacheOption) *EResourceCache {
+	c := &EResourceCache{
+		estimator: estimateResourceSize,
+		lru:       list.New(),
+		entries:   map[EResource]*list.Element{},
+		pinned:    map[EResource]struct{}{},
+	}
+	for _, opt := range options {
+		opt.apply(c)
+	}
+	return c
+}
+
+// estimateResourceSize returns the number of objects of resource
+func estimateResourceSize(resource EResource) int64 {
+	size := int64(0)
+	for it := resource.GetAllContents(); it.HasNext(); it.Next() {
+		size++
+	}
+	return size
+}
+
+// Pin prevents resource from being evicted
+func (c *EResourceCache) Pin(resource EResource) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pinned[resource] = struct{}{}
+}
+
+// Unpin allows resource to be evicted again
+func (c *EResourceCache) Unpin(resource EResource) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.pinned, resource)
+}
+
+func (c *EResourceCache) IsPinned(resource EResource) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, isPinned := c.pinned[resource]
+	return isPinned
+}
+
+// Len returns the number of loaded resources tracked by the cache
+func (c *EResourceCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lru.Len()
+}
+
+// Size returns the estimated size of loaded resources tracked by the cache
+func (c *EResourceCache) Size() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.size
+}
+
+func (c *EResourceCache) AfterLoad(resource EResource, options map[string]any, duration time.Duration) {
+	size := c.estimator(resource)
+	c.mutex.Lock()
+	if element := c.entries[resource]; element != nil {
+		c.removeElement(element)
+	}
+	c.entries[resource] = c.lru.PushFront(&resourceCacheEntry{resource: resource, size: size})
+	c.size += size
+	evicted := c.getEvicted(resource)
+	c.mutex.Unlock()
+
+	for _, resource := range evicted {
+		resource.Unload()
+	}
+}
+
+func (c *EResourceCache) Unloaded(resource EResource) {
+	c.remove(resource)
+}
+
+// touch marks resource as the most recently accessed one
+func (c *EResourceCache) touch(resource EResource) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element := c.entries[resource]; element != nil {
+		c.lru.MoveToFront(element)
+	}
+}
+
+// remove stops tracking resource
+func (c *EResourceCache) remove(resource EResource) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element := c.entries[resource]; element != nil {
+		c.removeElement(element)
+	}
+	delete(c.pinned, resource)
+}
+
+func (c *EResourceCache) removeElement(element *list.Element) {
+	entry := c.lru.Remove(element).(*resourceCacheEntry)
+	delete(c.entries, entry.resource)
+	c.size -= entry.size
+}
+
+func (c *EResourceCache) isExceeded() bool {
+	return (c.maxResources > 0 && c.lru.Len() > c.maxResources) || (c.maxSize > 0 && c.size > c.maxSize)
+}
+
+// getEvicted removes least recently accessed resources until limits are satisfied and returns them.
+// Cache must be locked
+func (c *EResourceCache) getEvicted(loaded EResource) []EResource {
+	evicted := []EResource{}
+	for element := c.lru.Back(); element != nil && c.isExceeded(); {
+		previous := element.Prev()
+		entry := element.Value.(*resourceCacheEntry)
+		if _, isPinned := c.pinned[entry.resource]; !isPinned && entry.resource != loaded && !entry.resource.IsLoading() && !entry.resource.IsModified() {
+			c.removeElement(element)
+			evicted = append(evicted, entry.resource)
+		}
+		element = previous
+	}
+	return evicted
+}
@@ -0,0 +1,168 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEResourceCache_Pin(t *testing.T) {
+	c := NewEResourceCache()
+	r := NewEResourceImpl()
+	assert.False(t, c.IsPinned(r))
+	c.Pin(r)
+	assert.True(t, c.IsPinned(r))
+	c.Unpin(r)
+	assert.False(t, c.IsPinned(r))
+}
+
+func TestEResourceSet_ResourceCache(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	assert.Nil(t, rs.GetResourceCache())
+
+	c := NewEResourceCache()
+	rs.SetResourceCache(c)
+	assert.Equal(t, c, rs.GetResourceCache())
+	assert.True(t, rs.GetResourceLifecycleListeners().Contains(c))
+
+	rs.SetResourceCache(nil)
+	assert.Nil(t, rs.GetResourceCache())
+	assert.False(t, rs.GetResourceLifecycleListeners().Contains(c))
+}
+
+func TestEResourceCache_MaxResources(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	loadTestPackage(t, rs, NewURI("testdata/library.complex.ecore"))
+
+	c := NewEResourceCache(ResourceCacheMaxResources(2))
+	rs.SetResourceCache(c)
+
+	xmlURI := NewURI("testdata/library.complex.xml")
+	binURI := NewURI("testdata/library.complex.bin")
+	sqliteURI := NewURI("testdata/library.complex.sqlite")
+	xmlResource := rs.GetResource(xmlURI, true)
+	binResource := rs.GetResource(binURI, true)
+	assert.Equal(t, 2, c.Len())
+
+	// xml is the most recently accessed
+	assert.Equal(t, xmlResource, rs.GetResource(xmlURI, true))
+	sqliteResource := rs.GetResource(sqliteURI, true)
+	assert.Equal(t, 2, c.Len())
+	assert.True(t, xmlResource.IsLoaded())
+	assert.False(t, binResource.IsLoaded())
+	assert.True(t, sqliteResource.IsLoaded())
+
+	// evicted resources are reloaded on demand
+	assert.Equal(t, binResource, rs.GetResource(binURI, true))
+	assert.True(t, binResource.IsLoaded())
+	assert.True(t, binResource.GetErrors().Empty(), diagnosticError(binResource.GetErrors()))
+	assert.False(t, xmlResource.IsLoaded())
+	assert.True(t, sqliteResource.IsLoaded())
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestEResourceCache_MaxSize(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	loadTestPackage(t, rs, NewURI("testdata/library.complex.ecore"))
+
+	c := NewEResourceCache(ResourceCacheMaxSize(15), ResourceCacheSizeEstimator(func(EResource) int64 { return 10 }))
+	rs.SetResourceCache(c)
+
+	xmlResource := rs.GetResource(NewURI("testdata/library.complex.xml"), true)
+	assert.Equal(t, int64(10), c.Size())
+	binResource := rs.GetResource(NewURI("testdata/library.complex.bin"), true)
+	assert.Equal(t, int64(10), c.Size())
+	assert.False(t, xmlResource.IsLoaded())
+	assert.True(t, binResource.IsLoaded())
+
+	// removed resources are not tracked anymore
+	rs.GetResources().Remove(binResource)
+	assert.Equal(t, int64(0), c.Size())
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestEResourceCache_Pinned(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	loadTestPackage(t, rs, NewURI("testdata/library.complex.ecore"))
+
+	c := NewEResourceCache(ResourceCacheMaxResources(1))
+	rs.SetResourceCache(c)
+
+	xmlResource := rs.GetResource(NewURI("testdata/library.complex.xml"), true)
+	c.Pin(xmlResource)
+	binResource := rs.GetResource(NewURI("testdata/library.complex.bin"), true)
+	assert.True(t, xmlResource.IsLoaded())
+	assert.True(t, binResource.IsLoaded())
+	assert.Equal(t, 2, c.Len())
+
+	// unpinned resource is evicted by next loading
+	c.Unpin(xmlResource)
+	sqliteResource := rs.GetResource(NewURI("testdata/library.complex.sqlite"), true)
+	assert.False(t, xmlResource.IsLoaded())
+	assert.False(t, binResource.IsLoaded())
+	assert.True(t, sqliteResource.IsLoaded())
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestEResourceCache_ProxyResolution(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	_, eShopPackage := loadTestPackage(t, rs, NewURI("testdata/shop.ecore"))
+	_, eOrdersPackage := loadTestPackage(t, rs, NewURI("testdata/orders.ecore"))
+	loadTestPackage(t, rs, NewURI("testdata/library.complex.ecore"))
+
+	c := NewEResourceCache(ResourceCacheMaxResources(1))
+	rs.SetResourceCache(c)
+
+	eOrdersResource := rs.GetResource(NewURI("testdata/orders.xml"), true)
+	require.NotNil(t, eOrdersResource)
+	c.Pin(eOrdersResource)
+
+	eOrdersClass, _ := eOrdersPackage.GetEClassifier("Orders").(EClass)
+	require.NotNil(t, eOrdersClass)
+	eOrderReference, _ := eOrdersClass.GetEStructuralFeatureFromName("order").(EReference)
+	require.NotNil(t, eOrderReference)
+	eOrderClass, _ := eOrdersPackage.GetEClassifier("Order").(EClass)
+	require.NotNil(t, eOrderClass)
+	eProductReference, _ := eOrderClass.GetEStructuralFeatureFromName("product").(EReference)
+	require.NotNil(t, eProductReference)
+	eProductClass, _ := eShopPackage.GetEClassifier("Product").(EClass)
+	require.NotNil(t, eProductClass)
+	eProductNameAttribute, _ := eProductClass.GetEStructuralFeatureFromName("name").(EAttribute)
+	require.NotNil(t, eProductNameAttribute)
+
+	eOrders := eOrdersResource.GetContents().Get(0).(EObject)
+	eOrder := eOrders.EGet(eOrderReference).(EList).Get(0).(EObject)
+
+	// resolving product loads shop
+	eProduct, _ := eOrder.EGet(eProductReference).(EObject)
+	require.NotNil(t, eProduct)
+	require.False(t, eProduct.EIsProxy())
+	eShopResource := eProduct.EResource()
+	require.NotNil(t, eShopResource)
+	name := eProduct.EGet(eProductNameAttribute)
+
+	// loading another resource evicts shop
+	rs.GetResource(NewURI("testdata/library.complex.xml"), true)
+	assert.False(t, eShopResource.IsLoaded())
+	assert.True(t, eProduct.EIsProxy())
+	assert.Equal(t, "testdata/shop.xml#//@products.0", eProduct.(EObjectInternal).EProxyURI().String())
+
+	// proxy is resolved by reloading shop
+	eReloaded, _ := eOrder.EGet(eProductReference).(EObject)
+	require.NotNil(t, eReloaded)
+	assert.False(t, eReloaded.EIsProxy())
+	assert.NotEqual(t, eProduct, eReloaded)
+	assert.Equal(t, eShopResource, eReloaded.EResource())
+	assert.True(t, eShopResource.IsLoaded())
+	assert.Equal(t, name, eReloaded.EGet(eProductNameAttribute))
+}
@@ -0,0 +1,145 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+// intrinsicIDIndex indexes the objects of a resource by their intrinsic id : the value of their id attribute.
+// It is kept up to date by the resource when objects are attached or detached and
+// it listens to the indexed objects to follow the changes of their id attribute.
+// Index is guarded by the mutex of its resource.
+type intrinsicIDIndex struct {
+	AbstractEAdapter
+	resource        *EResourceImpl
+	idToObjects     map[string][]EObject
+	objectToID      map[EObject]string
+	duplicates      map[string]EDiagnostic
+	addedWarnings   []EDiagnostic
+	removedWarnings []EDiagnostic
+}
+
+func newIntrinsicIDIndex(resource *EResourceImpl) *intrinsicIDIndex {
+	return &intrinsicIDIndex{
+		resource:    resource,
+		idToObjects: map[string][]EObject{},
+		objectToID:  map[EObject]string{},
+		duplicates:  map[string]EDiagnostic{},
+	}
+}
+
+// getEObject returns the first object indexed with id
+func (index *intrinsicIDIndex) getEObject(id string) EObject {
+	if objects := index.idToObjects[id]; len(objects) > 0 {
+		return objects[0]
+	}
+	return nil
+}
+
+func (index *intrinsicIDIndex) add(eObject EObject) {
+	if eObject.EClass().GetEIDAttribute() == nil {
+		return
+	}
+	if _, isIndexed := index.objectToID[eObject]; isIndexed {
+		return
+	}
+	eObject.EAdapters().Add(index)
+	index.register(eObject, GetEObjectID(eObject))
+}
+
+func (index *intrinsicIDIndex) remove(eObject EObject) {
+	if _, isIndexed := index.objectToID[eObject]; !isIndexed {
+		return
+	}
+	index.unregister(eObject)
+	delete(index.objectToID, eObject)
+	eObject.EAdapters().Remove(index)
+}
+
+func (index *intrinsicIDIndex) clear() {
+	for eObject := range index.objectToID {
+		eObject.EAdapters().Remove(index)
+	}
+	for _, diagnostic := range index.duplicates {
+		index.removedWarnings = append(index.removedWarnings, diagnostic)
+	}
+	index.idToObjects = map[string][]EObject{}
+	index.objectToID = map[EObject]string{}
+	index.duplicates = map[string]EDiagnostic{}
+}
+
+// takeWarnings returns the duplicate id warnings added and removed since its last call
+func (index *intrinsicIDIndex) takeWarnings() (added []EDiagnostic, removed []EDiagnostic) {
+	added, removed = index.addedWarnings, index.removedWarnings
+	index.addedWarnings, index.removedWarnings = nil, nil
+	return
+}
+
+func (index *intrinsicIDIndex) register(eObject EObject, id string) {
+	index.objectToID[eObject] = id
+	if len(id) == 0 {
+		return
+	}
+	objects := append(index.idToObjects[id], eObject)
+	index.idToObjects[id] = objects
+	if len(objects) == 2 {
+		location := ""
+		if uri := index.resource.GetURI(); uri != nil {
+			location = uri.String()
+		}
+		diagnostic := NewEDiagnosticImpl("Duplicate ID '"+id+"'", location, 0, 0)
+		index.duplicates[id] = diagnostic
+		index.addedWarnings = append(index.addedWarnings, diagnostic)
+	}
+}
+
+func (index *intrinsicIDIndex) unregister(eObject EObject) {
+	id := index.objectToID[eObject]
+	if len(id) == 0 {
+		return
+	}
+	objects := index.idToObjects[id]
+	for i, o := range objects {
+		if o == eObject {
+			objects = append(objects[:i], objects[i+1:]...)
+			break
+		}
+	}
+	if len(objects) == 0 {
+		delete(index.idToObjects, id)
+	} else {
+		index.idToObjects[id] = objects
+	}
+	if diagnostic := index.duplicates[id]; diagnostic != nil && len(objects) < 2 {
+		// duplicate is resolved
+		delete(index.duplicates, id)
+		index.removedWarnings = append(index.removedWarnings, diagnostic)
+	}
+}
+
+// NotifyChanged updates the index when the id attribute of an indexed object changes
+func (index *intrinsicIDIndex) NotifyChanged(notification ENotification) {
+	switch notification.GetEventType() {
+	case SET, UNSET:
+		eObject, _ := notification.GetNotifier().(EObject)
+		if eObject == nil || notification.GetFeature() != eObject.EClass().GetEIDAttribute() {
+			return
+		}
+		index.resource.withIntrinsicIDs(false, func(current *intrinsicIDIndex) {
+			// index may have been replaced since the notification
+			if current != index {
+				return
+			}
+			if oldID, isIndexed := index.objectToID[eObject]; isIndexed {
+				if id := GetEObjectID(eObject); id != oldID {
+					index.unregister(eObject)
+					index.register(eObject, id)
+				}
+			}
+		})
+	}
+}
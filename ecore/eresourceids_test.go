@@ -0,0 +1,180 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type intrinsicIDsModel struct {
+	ePackage       EPackage
+	containerClass EClass
+	itemClass      EClass
+	items          EReference
+	id             EAttribute
+	ref            EReference
+}
+
+func newIntrinsicIDsModel() *intrinsicIDsModel {
+	m := &intrinsicIDsModel{}
+	f := GetFactory()
+	m.ePackage = f.CreateEPackage()
+	m.ePackage.SetName("items")
+	m.ePackage.SetNsPrefix("items")
+	m.ePackage.SetNsURI("http://www.masagroup.com/items")
+
+	m.itemClass = f.CreateEClass()
+	m.itemClass.SetName("Item")
+	m.id = f.CreateEAttribute()
+	m.id.SetName("id")
+	m.id.SetEType(GetPackage().GetEString())
+	m.id.SetID(true)
+	m.ref = f.CreateEReference()
+	m.ref.SetName("ref")
+	m.ref.SetEType(m.itemClass)
+	m.itemClass.GetEStructuralFeatures().AddAll(NewImmutableEList([]any{m.id, m.ref}))
+
+	m.containerClass = f.CreateEClass()
+	m.containerClass.SetName("Container")
+	m.items = f.CreateEReference()
+	m.items.SetName("items")
+	m.items.SetEType(m.itemClass)
+	m.items.SetContainment(true)
+	m.items.SetUpperBound(UNBOUNDED_MULTIPLICITY)
+	m.containerClass.GetEStructuralFeatures().Add(m.items)
+
+	m.ePackage.GetEClassifiers().AddAll(NewImmutableEList([]any{m.containerClass, m.itemClass}))
+	return m
+}
+
+func (m *intrinsicIDsModel) newItem(id string) EObject {
+	eItem := m.ePackage.GetEFactoryInstance().Create(m.itemClass)
+	eItem.ESet(m.id, id)
+	return eItem
+}
+
+func TestEResourceImpl_IntrinsicIDs(t *testing.T) {
+	m := newIntrinsicIDsModel()
+	r := NewEResourceImpl()
+	eContainer := m.ePackage.GetEFactoryInstance().Create(m.containerClass)
+	r.GetContents().Add(eContainer)
+	eItems := eContainer.EGet(m.items).(EList)
+	a := m.newItem("a")
+	b := m.newItem("b")
+	eItems.AddAll(NewImmutableEList([]any{a, b}))
+
+	assert.Equal(t, a, r.GetEObject("a"))
+	assert.Equal(t, b, r.GetEObject("b"))
+	assert.Nil(t, r.GetEObject("c"))
+	// objects are listened to by the index
+	assert.Equal(t, 1, a.EAdapters().Size())
+	assert.True(t, eContainer.EAdapters().Empty())
+
+	// attached objects are indexed
+	c := m.newItem("c")
+	eItems.Add(c)
+	assert.Equal(t, c, r.GetEObject("c"))
+
+	// detached objects are not
+	eItems.Remove(b)
+	assert.Nil(t, r.GetEObject("b"))
+	assert.True(t, b.EAdapters().Empty())
+
+	// id changes
+	a.ESet(m.id, "d")
+	assert.Nil(t, r.GetEObject("a"))
+	assert.Equal(t, a, r.GetEObject("d"))
+	a.EUnset(m.id)
+	assert.Nil(t, r.GetEObject("d"))
+
+	// id set once attached
+	f := m.ePackage.GetEFactoryInstance().Create(m.itemClass)
+	eItems.Add(f)
+	assert.Nil(t, r.GetEObject("f"))
+	f.ESet(m.id, "f")
+	assert.Equal(t, f, r.GetEObject("f"))
+
+	// detached objects are not followed anymore
+	b.ESet(m.id, "e")
+	assert.Nil(t, r.GetEObject("e"))
+	assert.True(t, r.GetWarnings().Empty())
+
+	// unload
+	r.Unload()
+	assert.Nil(t, r.intrinsicIDs)
+	assert.Nil(t, r.GetEObject("c"))
+}
+
+func TestEResourceImpl_IntrinsicIDsDuplicate(t *testing.T) {
+	m := newIntrinsicIDsModel()
+	r := NewEResourceImpl()
+	eContainer := m.ePackage.GetEFactoryInstance().Create(m.containerClass)
+	r.GetContents().Add(eContainer)
+	eItems := eContainer.EGet(m.items).(EList)
+	a1 := m.newItem("a")
+	a2 := m.newItem("a")
+	eItems.AddAll(NewImmutableEList([]any{a1, a2}))
+
+	assert.Equal(t, a1, r.GetEObject("a"))
+	require.Equal(t, 1, r.GetWarnings().Size())
+	assert.Equal(t, "Duplicate ID 'a'", r.GetWarnings().Get(0).(EDiagnostic).GetMessage())
+
+	// first one removed, second one is found and duplicate is resolved
+	eItems.Remove(a1)
+	assert.Equal(t, a2, r.GetEObject("a"))
+	assert.True(t, r.GetWarnings().Empty())
+
+	// duplicate is resolved as soon as its id is changed
+	eItems.Add(a1)
+	require.Equal(t, 1, r.GetWarnings().Size())
+	a1.ESet(m.id, "b")
+	assert.True(t, r.GetWarnings().Empty())
+	assert.Equal(t, a1, r.GetEObject("b"))
+
+	// duplicate is detected as soon as an id is changed
+	a2.ESet(m.id, "b")
+	require.Equal(t, 1, r.GetWarnings().Size())
+	assert.Equal(t, "Duplicate ID 'b'", r.GetWarnings().Get(0).(EDiagnostic).GetMessage())
+	assert.Nil(t, r.GetEObject("a"))
+}
+
+func TestEResourceImpl_IntrinsicIDsLoad(t *testing.T) {
+	m := newIntrinsicIDsModel()
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetPackageRegistry().RegisterPackage(m.ePackage)
+	r := resourceSet.CreateResource(NewURI("items.xml"))
+	r.LoadWithReader(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<items:Container xmlns:items="http://www.masagroup.com/items">
+  <items id="a" ref="c"/>
+  <items id="b" ref="a"/>
+  <items id="c" ref="b"/>
+  <items id="a"/>
+</items:Container>`), nil)
+	require.True(t, r.GetErrors().Empty(), diagnosticError(r.GetErrors()))
+
+	// references are resolved by id
+	eItems := r.GetContents().Get(0).(EObject).EGet(m.items).(EList)
+	require.Equal(t, 4, eItems.Size())
+	a := eItems.Get(0).(EObject)
+	b := eItems.Get(1).(EObject)
+	c := eItems.Get(2).(EObject)
+	assert.Equal(t, c, a.EGet(m.ref))
+	assert.Equal(t, a, b.EGet(m.ref))
+	assert.Equal(t, b, c.EGet(m.ref))
+
+	// duplicates are reported as warnings
+	require.Equal(t, 1, r.GetWarnings().Size())
+	assert.Equal(t, "Duplicate ID 'a'", r.GetWarnings().Get(0).(EDiagnostic).GetMessage())
+	assert.Equal(t, "items.xml", r.GetWarnings().Get(0).(EDiagnostic).GetLocation())
+}
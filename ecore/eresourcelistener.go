@@ -9,6 +9,8 @@
 
 package ecore
 
+import "time"
+
 // EResourceListener defines callbacks when object is attached/detached from the resource
 type EResourceListener interface {
 	// Attached is called when an new object is attached to the resource
@@ -16,3 +18,48 @@ type EResourceListener interface {
 	// Detached is called when an new object is detached from the resource
 	Detached(object EObject)
 }
+
+// EResourceLifecycleListener defines callbacks around loading, saving and unloading of resources.
+// Listeners are registered on a resource or on its resource set to observe all of its resources
+type EResourceLifecycleListener interface {
+	// BeforeLoad is called before the resource is loaded
+	BeforeLoad(resource EResource, options map[string]any)
+	// AfterLoad is called when the resource has been loaded without errors
+	AfterLoad(resource EResource, options map[string]any, duration time.Duration)
+	// LoadFailed is called when the loading of the resource failed with diagnostics
+	LoadFailed(resource EResource, options map[string]any, diagnostics []EDiagnostic)
+	// BeforeSave is called before the resource is saved
+	BeforeSave(resource EResource, options map[string]any)
+	// AfterSave is called when the resource has been saved without errors
+	AfterSave(resource EResource, options map[string]any, duration time.Duration)
+	// SaveFailed is called when the saving of the resource failed with diagnostics
+	SaveFailed(resource EResource, options map[string]any, diagnostics []EDiagnostic)
+	// Unloaded is called when the resource has been unloaded
+	Unloaded(resource EResource)
+}
+
+// AbstractEResourceLifecycleListener is a lifecycle listener ignoring all callbacks.
+// It is intended to be embedded by listeners interested in a few of them
+type AbstractEResourceLifecycleListener struct {
+}
+
+func (l *AbstractEResourceLifecycleListener) BeforeLoad(resource EResource, options map[string]any) {
+}
+
+func (l *AbstractEResourceLifecycleListener) AfterLoad(resource EResource, options map[string]any, duration time.Duration) {
+}
+
+func (l *AbstractEResourceLifecycleListener) LoadFailed(resource EResource, options map[string]any, diagnostics []EDiagnostic) {
+}
+
+func (l *AbstractEResourceLifecycleListener) BeforeSave(resource EResource, options map[string]any) {
+}
+
+func (l *AbstractEResourceLifecycleListener) AfterSave(resource EResource, options map[string]any, duration time.Duration) {
+}
+
+func (l *AbstractEResourceLifecycleListener) SaveFailed(resource EResource, options map[string]any, diagnostics []EDiagnostic) {
+}
+
+func (l *AbstractEResourceLifecycleListener) Unloaded(resource EResource) {
+}
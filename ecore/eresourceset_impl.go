@@ -1,5 +1,15 @@
 package ecore
 
+import (
+	"context"
+	"errors"
+	"maps"
+	"runtime"
+	"sync"
+
+	"github.com/petermattis/goid"
+)
+
 type resourcesList struct {
 	BasicENotifyingList
 	resourceSet *EResourceSetImpl
@@ -31,12 +41,24 @@ func (l *resourcesList) inverseAdd(object any, notifications ENotificationChain)
 
 func (l *resourcesList) inverseRemove(object any, notifications ENotificationChain) ENotificationChain {
 	if eResource, _ := object.(EResourceInternal); eResource != nil {
+		if cache := l.resourceSet.GetResourceCache(); cache != nil {
+			cache.remove(eResource)
+		}
 		return eResource.BasicSetResourceSet(nil, notifications)
 	}
 	return notifications
 }
 
-// EResourceSetImpl ...
+// resourceLoading is a loading in progress of a resource by the goroutine owner
+type resourceLoading struct {
+	done     chan struct{}
+	owner    int64
+	resource EResource
+	err      error
+}
+
+// EResourceSetImpl is safe for concurrent retrieval and loading of its resources.
+// Its resources list must not be modified directly while other goroutines use the resource set
 type EResourceSetImpl struct {
 	ENotifierImpl
 	resources             EList
@@ -44,6 +66,11 @@ type EResourceSetImpl struct {
 	uriResourceMap        map[*URI]EResource
 	resourceCodecRegistry ECodecRegistry
 	packageRegistry       EPackageRegistry
+	lifecycleListeners    EList
+	cache                 *EResourceCache
+	loadings              map[string]*resourceLoading
+	mutex                 sync.Mutex
+	registriesMutex       sync.Mutex
 }
 
 func NewEResourceSetImpl() *EResourceSetImpl {
@@ -56,6 +83,7 @@ func NewEResourceSetImpl() *EResourceSetImpl {
 func (r *EResourceSetImpl) Initialize() {
 	r.ENotifierImpl.Initialize()
 	r.resources = newResourcesList(r)
+	r.loadings = map[string]*resourceLoading{}
 }
 
 func (r *EResourceSetImpl) AsEResourceSet() EResourceSet {
@@ -67,13 +95,102 @@ func (r *EResourceSetImpl) GetResources() EList {
 }
 
 func (r *EResourceSetImpl) GetResource(uri *URI, loadOnDemand bool) EResource {
+	resource, _ := r.getResource(context.Background(), uri, loadOnDemand, func(resource EResource) error {
+		resource.Load()
+		return nil
+	})
+	return resource
+}
+
+// LoadAll loads resources of uris in parallel and returns them in the same order.
+// Concurrent requests for the same normalized uri are loaded only once.
+// Returned error joins loading errors of all resources
+func (r *EResourceSetImpl) LoadAll(ctx context.Context, uris []*URI) ([]EResource, error) {
+	resources := make([]EResource, len(uris))
+	errs := make([]error, len(uris))
+	semaphore := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, uri := range uris {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			resources[i], errs[i] = r.getResource(ctx, uri, true, func(resource EResource) error {
+				return resource.LoadContext(ctx, nil)
+			})
+		}()
+	}
+	wg.Wait()
+	return resources, errors.Join(errs...)
+}
+
+// getResource returns the resource of uri, loading it with load if required.
+// Only one goroutine loads a resource, others wait for the end of its loading or for ctx to be done.
+// A loading which depends on the current goroutine is not waited for: its resource is returned while being loaded
+func (r *EResourceSetImpl) getResource(ctx context.Context, uri *URI, loadOnDemand bool, load func(EResource) error) (EResource, error) {
+	r.mutex.Lock()
+	key := r.GetURIConverter().Normalize(uri).String()
+	// wait for loading in progress
+	if loading := r.loadings[key]; loading != nil {
+		resource := loading.resource
+		r.mutex.Unlock()
+		id, canWait := loadingWaits.beginWait(loading.owner)
+		if !canWait {
+			return resource, nil
+		}
+		defer loadingWaits.endWait(id)
+		select {
+		case <-loading.done:
+			return loading.resource, loading.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	resource, isPackage := r.findResource(uri)
+	if !loadOnDemand || isPackage || (resource != nil && resource.IsLoaded()) {
+		r.mutex.Unlock()
+		if cache := r.GetResourceCache(); cache != nil && resource != nil {
+			cache.touch(resource)
+		}
+		return resource, nil
+	}
+	loading := &resourceLoading{done: make(chan struct{}), owner: goid.Get(), resource: resource}
+	r.loadings[key] = loading
+	r.mutex.Unlock()
+
+	// load resource
+	if resource == nil {
+		resource = r.AsEResourceSet().CreateResource(uri)
+		r.mutex.Lock()
+		loading.resource = resource
+		r.mutex.Unlock()
+	}
+	var err error
+	if resource != nil {
+		err = load(resource)
+	}
+
+	r.mutex.Lock()
+	if r.uriResourceMap != nil && resource != nil {
+		r.uriResourceMap[uri] = resource
+	}
+	delete(r.loadings, key)
+	loading.err = err
+	r.mutex.Unlock()
+
+	close(loading.done)
+	return resource, err
+}
+
+// findResource returns the resource of uri without loading it and if it is the resource of a registered package.
+// Resource set must be locked
+func (r *EResourceSetImpl) findResource(uri *URI) (EResource, bool) {
 	if r.uriResourceMap != nil {
-		resource := r.uriResourceMap[uri]
-		if resource != nil {
-			if loadOnDemand && !resource.IsLoaded() {
-				resource.Load()
-			}
-			return resource
+		if resource := r.uriResourceMap[uri]; resource != nil {
+			return resource, false
 		}
 	}
 
@@ -84,7 +201,7 @@ func (r *EResourceSetImpl) GetResource(uri *URI, loadOnDemand bool) EResource {
 			if r.uriResourceMap != nil {
 				r.uriResourceMap[uri] = resource
 			}
-			return resource
+			return resource, true
 		}
 	}
 
@@ -93,37 +210,29 @@ func (r *EResourceSetImpl) GetResource(uri *URI, loadOnDemand bool) EResource {
 		resource := it.Next().(EResource)
 		resourceURI := r.GetURIConverter().Normalize(resource.GetURI())
 		if resourceURI.Equals(normalizedURI) {
-			if loadOnDemand && !resource.IsLoaded() {
-				resource.Load()
-			}
 			if r.uriResourceMap != nil {
 				r.uriResourceMap[uri] = resource
 			}
-			return resource
+			return resource, false
 		}
 	}
-
-	if loadOnDemand {
-		resource := r.AsEResourceSet().CreateResource(uri)
-		if resource != nil {
-			resource.Load()
-			if r.uriResourceMap != nil {
-				r.uriResourceMap[uri] = resource
-			}
-		}
-		return resource
-	}
-
-	return nil
+	return nil, false
 }
 
 func (r *EResourceSetImpl) CreateResource(uri *URI) EResource {
 	resource := NewEResourceImpl()
 	resource.SetURI(uri)
-	r.resources.Add(resource)
+	r.addResource(resource)
 	return resource
 }
 
+// addResource adds resource to the resources of the resource set
+func (r *EResourceSetImpl) addResource(resource EResource) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.resources.Add(resource)
+}
+
 func (r *EResourceSetImpl) GetEObject(uri *URI, loadOnDemand bool) EObject {
 	trim := uri.TrimFragment()
 	resource := r.GetResource(trim, loadOnDemand)
@@ -134,6 +243,8 @@ func (r *EResourceSetImpl) GetEObject(uri *URI, loadOnDemand bool) EObject {
 }
 
 func (r *EResourceSetImpl) GetURIConverter() EURIConverter {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
 	if r.uriConverter == nil {
 		r.uriConverter = NewEURIConverterImpl()
 	}
@@ -141,10 +252,14 @@ func (r *EResourceSetImpl) GetURIConverter() EURIConverter {
 }
 
 func (r *EResourceSetImpl) SetURIConverter(uriConverter EURIConverter) {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
 	r.uriConverter = uriConverter
 }
 
 func (r *EResourceSetImpl) GetPackageRegistry() EPackageRegistry {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
 	if r.packageRegistry == nil {
 		r.packageRegistry = NewEPackageRegistryImplWithDelegate(GetPackageRegistry())
 	}
@@ -152,10 +267,14 @@ func (r *EResourceSetImpl) GetPackageRegistry() EPackageRegistry {
 }
 
 func (r *EResourceSetImpl) SetPackageRegistry(packageRegistry EPackageRegistry) {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
 	r.packageRegistry = packageRegistry
 }
 
 func (r *EResourceSetImpl) GetCodecRegistry() ECodecRegistry {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
 	if r.resourceCodecRegistry == nil {
 		r.resourceCodecRegistry = NewECodecRegistryImplWithDelegate(GetCodecRegistry())
 	}
@@ -163,13 +282,50 @@ func (r *EResourceSetImpl) GetCodecRegistry() ECodecRegistry {
 }
 
 func (r *EResourceSetImpl) SetCodecRegistry(resourceCodecRegistry ECodecRegistry) {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
 	r.resourceCodecRegistry = resourceCodecRegistry
 }
 
 func (r *EResourceSetImpl) SetURIResourceMap(uriResourceMap map[*URI]EResource) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	r.uriResourceMap = uriResourceMap
 }
 
+// GetURIResourceMap returns a copy of the uri resource map, which is modified by concurrent loadings
 func (r *EResourceSetImpl) GetURIResourceMap() map[*URI]EResource {
-	return r.uriResourceMap
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return maps.Clone(r.uriResourceMap)
+}
+
+func (r *EResourceSetImpl) GetResourceLifecycleListeners() EList {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
+	if r.lifecycleListeners == nil {
+		r.lifecycleListeners = NewEmptyBasicEList()
+	}
+	return r.lifecycleListeners
+}
+
+// GetResourceCache returns the cache bounding loaded resources or nil if they are not bounded
+func (r *EResourceSetImpl) GetResourceCache() *EResourceCache {
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
+	return r.cache
+}
+
+// SetResourceCache bounds loaded resources with cache. A nil cache removes any bound
+func (r *EResourceSetImpl) SetResourceCache(cache *EResourceCache) {
+	listeners := r.GetResourceLifecycleListeners()
+	r.registriesMutex.Lock()
+	defer r.registriesMutex.Unlock()
+	if r.cache != nil {
+		listeners.Remove(r.cache)
+	}
+	r.cache = cache
+	if r.cache != nil {
+		listeners.Add(r.cache)
+	}
 }
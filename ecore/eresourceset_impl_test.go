@@ -10,7 +10,10 @@
 package ecore
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -103,3 +106,152 @@ func TestEResourceSet_GetURIResourceMap(t *testing.T) {
 	rs.SetURIResourceMap(mockURIResourceMap)
 	assert.Equal(t, mockURIResourceMap, rs.GetURIResourceMap())
 }
+
+func TestEResourceSet_LoadAll(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	loadTestPackage(t, rs, NewURI("testdata/library.complex.ecore"))
+
+	uris := []*URI{
+		NewURI("testdata/library.complex.xml"),
+		NewURI("testdata/library.complex.bin"),
+		NewURI("testdata/library.complex.sqlite"),
+		NewURI("testdata/library.complex.xml"),
+	}
+	resources, err := rs.LoadAll(context.Background(), uris)
+	require.NoError(t, err)
+	require.Len(t, resources, len(uris))
+	for i, resource := range resources {
+		require.NotNil(t, resource)
+		assert.True(t, resource.GetURI().Equals(uris[i]))
+		assert.True(t, resource.IsLoaded())
+		assert.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	}
+	// same uri is loaded once
+	assert.Equal(t, resources[0], resources[3])
+	// package resource and loaded resources
+	assert.Equal(t, 4, rs.GetResources().Size())
+}
+
+func TestEResourceSet_LoadAll_Invalid(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	resources, err := rs.LoadAll(context.Background(), []*URI{NewURI("testdata/library.complex.ecore"), NewURI("testdata/invalid.bin")})
+	require.Error(t, err)
+	require.Len(t, resources, 2)
+	assert.True(t, resources[0].GetErrors().Empty(), diagnosticError(resources[0].GetErrors()))
+	assert.False(t, resources[1].GetErrors().Empty())
+}
+
+func TestEResourceSet_LoadAll_Canceled(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resources, err := rs.LoadAll(ctx, []*URI{NewURI("testdata/library.complex.ecore")})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, resources, 1)
+	require.NotNil(t, resources[0])
+	assert.False(t, resources[0].IsLoaded())
+}
+
+func TestEResourceSet_GetResourceConcurrent(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	uri := NewURI("testdata/library.complex.ecore")
+	resources := make([]EResource, 8)
+	var wg sync.WaitGroup
+	for i := range resources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resources[i] = rs.GetResource(uri, true)
+		}()
+	}
+	wg.Wait()
+	for _, resource := range resources {
+		require.NotNil(t, resource)
+		assert.Equal(t, resources[0], resource)
+		assert.True(t, resource.IsLoaded())
+	}
+	assert.Equal(t, 1, rs.GetResources().Size())
+}
+
+// requireDoneWithin fails if f does not return before timeout
+func requireDoneWithin(t *testing.T, timeout time.Duration, f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		require.FailNow(t, "blocked loading")
+	}
+}
+
+func TestEResourceSet_GetResourceReentrant(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	uri := NewURI("a.xml")
+	var resource, loading EResource
+	requireDoneWithin(t, 5*time.Second, func() {
+		resource, _ = rs.getResource(context.Background(), uri, true, func(resource EResource) error {
+			loading, _ = rs.getResource(context.Background(), uri, true, func(EResource) error {
+				t.Error("resource loaded twice")
+				return nil
+			})
+			return nil
+		})
+	})
+	require.NotNil(t, resource)
+	assert.Equal(t, resource, loading)
+}
+
+func TestEResourceSet_GetResourceCycle(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	uriA := NewURI("a.xml")
+	uriB := NewURI("b.xml")
+	// each loading requires the other one once both are started
+	var started sync.WaitGroup
+	started.Add(2)
+	loadWith := func(other *URI, dependency *EResource) func(EResource) error {
+		return func(EResource) error {
+			started.Done()
+			started.Wait()
+			*dependency, _ = rs.getResource(context.Background(), other, true, func(EResource) error {
+				t.Error("resource loaded twice")
+				return nil
+			})
+			return nil
+		}
+	}
+	var resourceA, resourceB, dependencyA, dependencyB EResource
+	requireDoneWithin(t, 5*time.Second, func() {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			resourceA, _ = rs.getResource(context.Background(), uriA, true, loadWith(uriB, &dependencyA))
+		}()
+		go func() {
+			defer wg.Done()
+			resourceB, _ = rs.getResource(context.Background(), uriB, true, loadWith(uriA, &dependencyB))
+		}()
+		wg.Wait()
+	})
+	require.NotNil(t, resourceA)
+	require.NotNil(t, resourceB)
+	assert.Equal(t, resourceB, dependencyA)
+	assert.Equal(t, resourceA, dependencyB)
+}
+
+func TestEResourceSet_GetURIResourceMapCopy(t *testing.T) {
+	rs := NewEResourceSetImpl()
+	rs.SetURIResourceMap(map[*URI]EResource{})
+	uri := NewURI("testdata/library.complex.ecore")
+	resource := rs.GetResource(uri, true)
+	require.NotNil(t, resource)
+	uriResourceMap := rs.GetURIResourceMap()
+	assert.Equal(t, map[*URI]EResource{uri: resource}, uriResourceMap)
+
+	// returned map is not the map of the resource set
+	delete(uriResourceMap, uri)
+	assert.Equal(t, map[*URI]EResource{uri: resource}, rs.GetURIResourceMap())
+}
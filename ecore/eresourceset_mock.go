@@ -9,7 +9,11 @@
 
 package ecore
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 type MockEResourceSet struct {
 	MockEResourceSet_Prototype
@@ -294,6 +298,44 @@ func (_c *MockEResourceSet_GetResources_Call) Return(_a0 EList) *MockEResourceSe
 	return _c
 }
 
+// GetResourceLifecycleListeners provides a mock function with given fields:
+func (_m *MockEResourceSet_Prototype_Methods) GetResourceLifecycleListeners() EList {
+	ret := _m.mock.Called()
+
+	var r0 EList
+	if rf, ok := ret.Get(0).(func() EList); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(EList)
+		}
+	}
+
+	return r0
+}
+
+// MockEResourceSet_GetResourceLifecycleListeners_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetResourceLifecycleListeners'
+type MockEResourceSet_GetResourceLifecycleListeners_Call struct {
+	*mock.Call
+}
+
+// GetResourceLifecycleListeners is a helper method to define mock.On call
+func (_e *MockEResourceSet_Expecter_Methods) GetResourceLifecycleListeners() *MockEResourceSet_GetResourceLifecycleListeners_Call {
+	return &MockEResourceSet_GetResourceLifecycleListeners_Call{Call: _e.mock.On("GetResourceLifecycleListeners")}
+}
+
+func (_c *MockEResourceSet_GetResourceLifecycleListeners_Call) Run(run func()) *MockEResourceSet_GetResourceLifecycleListeners_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockEResourceSet_GetResourceLifecycleListeners_Call) Return(_a0 EList) *MockEResourceSet_GetResourceLifecycleListeners_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // GetURIConverter provides a mock function with given fields:
 func (_m *MockEResourceSet_Prototype_Methods) GetURIConverter() EURIConverter {
 	ret := _m.mock.Called()
@@ -370,6 +412,53 @@ func (_c *MockEResourceSet_GetURIResourceMap_Call) Return(_a0 map[*URI]EResource
 	return _c
 }
 
+// LoadAll provides a mock function with given fields: ctx, uris
+func (_m *MockEResourceSet_Prototype_Methods) LoadAll(ctx context.Context, uris []*URI) ([]EResource, error) {
+	ret := _m.mock.Called(ctx, uris)
+
+	var r0 []EResource
+	if rf, ok := ret.Get(0).(func(context.Context, []*URI) []EResource); ok {
+		r0 = rf(ctx, uris)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]EResource)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []*URI) error); ok {
+		r1 = rf(ctx, uris)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEResourceSet_LoadAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LoadAll'
+type MockEResourceSet_LoadAll_Call struct {
+	*mock.Call
+}
+
+// LoadAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - uris []*URI
+func (_e *MockEResourceSet_Expecter_Methods) LoadAll(ctx interface{}, uris interface{}) *MockEResourceSet_LoadAll_Call {
+	return &MockEResourceSet_LoadAll_Call{Call: _e.mock.On("LoadAll", ctx, uris)}
+}
+
+func (_c *MockEResourceSet_LoadAll_Call) Run(run func(ctx context.Context, uris []*URI)) *MockEResourceSet_LoadAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*URI))
+	})
+	return _c
+}
+
+func (_c *MockEResourceSet_LoadAll_Call) Return(_a0 []EResource, _a1 error) *MockEResourceSet_LoadAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
 // SetPackageRegistry provides a mock function with given fields: packageregistry
 func (_m *MockEResourceSet_Prototype_Methods) SetPackageRegistry(packageregistry EPackageRegistry) {
 	_m.mock.Called(packageregistry)
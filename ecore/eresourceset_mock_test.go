@@ -10,6 +10,7 @@
 package ecore
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -148,3 +149,35 @@ func TestMockEResourceSetSetURIResourceMap(t *testing.T) {
 	rs.SetURIResourceMap(pr)
 	mock.AssertExpectationsForObjects(t, rs)
 }
+
+func TestMockEResourceSetGetResourceLifecycleListeners(t *testing.T) {
+	rs := NewMockEResourceSet(t)
+	l := NewMockEList(t)
+	m := NewMockRun(t)
+	rs.EXPECT().GetResourceLifecycleListeners().Return(l).Run(func() { m.Run() }).Once()
+	rs.EXPECT().GetResourceLifecycleListeners().Call.Return(func() EList {
+		return l
+	}).Once()
+	assert.Equal(t, l, rs.GetResourceLifecycleListeners())
+	assert.Equal(t, l, rs.GetResourceLifecycleListeners())
+}
+
+func TestMockEResourceSetLoadAll(t *testing.T) {
+	rs := NewMockEResourceSet(t)
+	ctx := context.Background()
+	uris := []*URI{NewURI("file.xml")}
+	resources := []EResource{NewMockEResource(t)}
+	m := NewMockRun(t, ctx, uris)
+	rs.EXPECT().LoadAll(ctx, uris).Return(resources, nil).Run(func(ctx context.Context, uris []*URI) { m.Run(ctx, uris) }).Once()
+	rs.EXPECT().LoadAll(ctx, uris).Call.Return(func(context.Context, []*URI) []EResource {
+		return nil
+	}, func(context.Context, []*URI) error {
+		return context.Canceled
+	}).Once()
+	result, err := rs.LoadAll(ctx, uris)
+	assert.Equal(t, resources, result)
+	assert.Nil(t, err)
+	result, err = rs.LoadAll(ctx, uris)
+	assert.Nil(t, result)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -9,6 +9,8 @@
 
 package ecore
 
+import "context"
+
 const (
 	RESOURCE_SET__RESOURCES = 0
 )
@@ -34,6 +36,10 @@ type EResourceSet interface {
 
 	SetURIResourceMap(uriMap map[*URI]EResource)
 	GetURIResourceMap() map[*URI]EResource
+
+	GetResourceLifecycleListeners() EList
+
+	LoadAll(ctx context.Context, uris []*URI) ([]EResource, error)
 }
 
 func CreateEResourceSet(packages []EPackage) EResourceSet {
@@ -68,7 +68,7 @@ func (m *EStoreMap) SetEStore(store EStore) {
 // Set object with a cache for its feature values
 func (m *EStoreMap) SetCache(cache bool) {
 	// reset map data
-	m.mapData = nil
+	m.doClear()
 	// set internal list cache
 	cacheProvider := m.EList.(ECacheProvider)
 	cacheProvider.SetCache(cache)
@@ -4,6 +4,7 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -109,10 +110,12 @@ func TestEStoreMap_Put(t *testing.T) {
 	mockEntry.EXPECT().SetCache(false).Once()
 	mockEntry.EXPECT().SetKey(1).Once()
 	mockEntry.EXPECT().SetValue(2).Once()
-	mockStore.EXPECT().Contains(mockOwner, mockFeature, mockEntry).Return(false).Once()
 	mockStore.EXPECT().Add(mockOwner, mockFeature, 0, mockEntry).Return().Once()
+	mockEntry.EXPECT().GetKey().Return(1).Once()
+	mockEntry.EXPECT().GetValue().Return(2).Once()
 	mockOwner.EXPECT().EDeliver().Return(false).Once()
 	m.Put(1, 2)
+	assert.Equal(t, 2, m.GetValue(1))
 }
 
 func TestEStoreMap_Add(t *testing.T) {
@@ -117,3 +117,10 @@ func IsMapType(feature EStructuralFeature) bool {
 	}
 	return false
 }
+
+func IsFeatureMap(feature EStructuralFeature) bool {
+	if attribute, isAttribute := feature.(EAttribute); isAttribute {
+		return attribute.GetEType() == GetPackage().GetEFeatureMapEntry()
+	}
+	return false
+}
@@ -85,3 +85,15 @@ func TestEStructuralFeatureExtSetDefaultValue(t *testing.T) {
 		mock.AssertExpectationsForObjects(t, mockType, mockDefaultValue, mockPackage, mockFactory)
 	}
 }
+
+func TestIsFeatureMap(t *testing.T) {
+	mockAttribute := NewMockEAttribute(t)
+	mockAttribute.EXPECT().GetEType().Return(GetPackage().GetEFeatureMapEntry()).Once()
+	assert.True(t, IsFeatureMap(mockAttribute))
+
+	mockAttribute.EXPECT().GetEType().Return(GetPackage().GetEString()).Once()
+	assert.False(t, IsFeatureMap(mockAttribute))
+
+	mockReference := NewMockEReference(t)
+	assert.False(t, IsFeatureMap(mockReference))
+}
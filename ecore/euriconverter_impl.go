@@ -1,6 +1,7 @@
 package ecore
 
 import (
+	"context"
 	"fmt"
 	"io"
 )
@@ -12,27 +13,45 @@ type EURIConverterImpl struct {
 
 func NewEURIConverterImpl() *EURIConverterImpl {
 	r := new(EURIConverterImpl)
-	r.uriHandlers = NewImmutableEList([]any{new(FileURIHandler), new(MemoryURIHandler)})
+	r.uriHandlers = NewBasicEList([]any{new(FileURIHandler), new(MemoryURIHandler), NewHTTPURIHandler(), NewArchiveURIHandler(r)})
 	r.uriMap = make(map[URI]URI)
 	return r
 }
 
 func (r *EURIConverterImpl) CreateReader(uri *URI) (io.ReadCloser, error) {
+	return r.CreateReaderContext(context.Background(), uri)
+}
+
+func (r *EURIConverterImpl) CreateReaderContext(ctx context.Context, uri *URI) (io.ReadCloser, error) {
 	normalized := r.Normalize(uri)
 	uriHandler := r.GetURIHandler(normalized)
 	if uriHandler == nil {
 		return nil, fmt.Errorf("URIHandler for URI '%s' not found", normalized.String())
 	}
-	return uriHandler.CreateReader(normalized)
+	rc, err := createReaderContext(ctx, uriHandler, normalized)
+	if err != nil {
+		return nil, err
+	}
+	// decompress according to uri extension
+	return newCompressedReader(normalized, rc)
 }
 
 func (r *EURIConverterImpl) CreateWriter(uri *URI) (io.WriteCloser, error) {
+	return r.CreateWriterContext(context.Background(), uri)
+}
+
+func (r *EURIConverterImpl) CreateWriterContext(ctx context.Context, uri *URI) (io.WriteCloser, error) {
 	normalized := r.Normalize(uri)
 	uriHandler := r.GetURIHandler(normalized)
 	if uriHandler == nil {
 		return nil, fmt.Errorf("URIHandler for URI '%s' not found", normalized.String())
 	}
-	return uriHandler.CreateWriter(normalized)
+	wc, err := createWriterContext(ctx, uriHandler, normalized)
+	if err != nil {
+		return nil, err
+	}
+	// compress according to uri extension
+	return newCompressedWriter(normalized, wc)
 }
 
 func (r *EURIConverterImpl) GetURIMap() map[URI]URI {
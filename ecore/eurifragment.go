@@ -0,0 +1,123 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"net/url"
+	"strings"
+)
+
+// fragmentKey is a key of a key based fragment segment such as @books[title='Dune']
+type fragmentKey struct {
+	name   string
+	value  string
+	isNull bool
+}
+
+// writeFragmentKeys writes the keys of object for reference : [key1='value1',key2='value2']
+// A nil value is written as null, other values are quoted and
+// characters which are significant in a uri or a segment are percent encoded
+func writeFragmentKeys(s *strings.Builder, reference EReference, object EObject) {
+	s.WriteByte('[')
+	for i, it := 0, reference.GetEKeys().Iterator(); it.HasNext(); i++ {
+		eKey := it.Next().(EAttribute)
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		s.WriteString(eKey.GetName())
+		s.WriteByte('=')
+		value := object.EGet(eKey)
+		if value == nil {
+			s.WriteString("null")
+		} else {
+			s.WriteByte('\'')
+			writeFragmentValue(s, convertKeyToString(eKey, value))
+			s.WriteByte('\'')
+		}
+	}
+	s.WriteByte(']')
+}
+
+func writeFragmentValue(s *strings.Builder, value string) {
+	const hex = "0123456789ABCDEF"
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c < ' ', c == ' ', c == '"', c == '#', c == '%', c == '\'', c == '/', c == '?', c == 0x7F:
+			s.WriteByte('%')
+			s.WriteByte(hex[c>>4])
+			s.WriteByte(hex[c&0xF])
+		default:
+			s.WriteByte(c)
+		}
+	}
+}
+
+// parseFragmentKeys parses the keys of a key based segment without its brackets
+func parseFragmentKeys(s string) ([]fragmentKey, bool) {
+	keys := []fragmentKey{}
+	for len(s) > 0 {
+		index := strings.IndexByte(s, '=')
+		if index <= 0 {
+			return nil, false
+		}
+		key := fragmentKey{name: s[:index]}
+		s = s[index+1:]
+		if strings.HasPrefix(s, "null") {
+			key.isNull = true
+			s = s[len("null"):]
+		} else if len(s) > 0 && s[0] == '\'' {
+			end := strings.IndexByte(s[1:], '\'')
+			if end == -1 {
+				return nil, false
+			}
+			value, err := url.PathUnescape(s[1 : end+1])
+			if err != nil {
+				return nil, false
+			}
+			key.value = value
+			s = s[end+2:]
+		} else {
+			return nil, false
+		}
+		keys = append(keys, key)
+		if len(s) > 0 {
+			if s[0] != ',' {
+				return nil, false
+			}
+			s = s[1:]
+		}
+	}
+	return keys, true
+}
+
+// matchFragmentKeys returns true if the key attributes of object have the values of keys
+func matchFragmentKeys(object EObject, keys []fragmentKey) bool {
+	eClass := object.EClass()
+	for _, key := range keys {
+		eKey, _ := eClass.GetEStructuralFeatureFromName(key.name).(EAttribute)
+		if eKey == nil {
+			return false
+		}
+		value := object.EGet(eKey)
+		if key.isNull {
+			if value != nil {
+				return false
+			}
+		} else if value == nil || convertKeyToString(eKey, value) != key.value {
+			return false
+		}
+	}
+	return true
+}
+
+func convertKeyToString(eKey EAttribute, value any) string {
+	eDataType := eKey.GetEAttributeType()
+	return eDataType.GetEPackage().GetEFactoryInstance().ConvertToString(eDataType, value)
+}
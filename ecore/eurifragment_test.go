@@ -0,0 +1,194 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keysModel struct {
+	ePackage      EPackage
+	libraryClass  EClass
+	bookClass     EClass
+	borrowerClass EClass
+	books         EReference
+	borrowers     EReference
+	borrowed      EReference
+	title         EAttribute
+	edition       EAttribute
+	borrowerName  EAttribute
+}
+
+func loadKeysModel(t *testing.T) *keysModel {
+	ePackage := loadPackage("library.keys.ecore")
+	require.NotNil(t, ePackage)
+	m := &keysModel{ePackage: ePackage}
+	m.libraryClass = ePackage.GetEClassifier("Library").(EClass)
+	m.bookClass = ePackage.GetEClassifier("Book").(EClass)
+	m.borrowerClass = ePackage.GetEClassifier("Borrower").(EClass)
+	m.books = m.libraryClass.GetEStructuralFeatureFromName("books").(EReference)
+	m.borrowers = m.libraryClass.GetEStructuralFeatureFromName("borrowers").(EReference)
+	m.borrowed = m.borrowerClass.GetEStructuralFeatureFromName("borrowed").(EReference)
+	m.title = m.bookClass.GetEStructuralFeatureFromName("title").(EAttribute)
+	m.edition = m.bookClass.GetEStructuralFeatureFromName("edition").(EAttribute)
+	m.borrowerName = m.borrowerClass.GetEStructuralFeatureFromName("name").(EAttribute)
+	return m
+}
+
+func (m *keysModel) newBook(title any, edition int) EObject {
+	eBook := m.ePackage.GetEFactoryInstance().Create(m.bookClass)
+	eBook.ESet(m.title, title)
+	eBook.ESet(m.edition, edition)
+	return eBook
+}
+
+func TestFragmentValue(t *testing.T) {
+	var s strings.Builder
+	writeFragmentValue(&s, "a/b c'd%e#f?g\"h\n,]=")
+	assert.Equal(t, "a%2Fb%20c%27d%25e%23f%3Fg%22h%0A,]=", s.String())
+}
+
+func TestParseFragmentKeys(t *testing.T) {
+	keys, ok := parseFragmentKeys("title='Dune',edition='1'")
+	assert.True(t, ok)
+	assert.Equal(t, []fragmentKey{{name: "title", value: "Dune"}, {name: "edition", value: "1"}}, keys)
+
+	keys, ok = parseFragmentKeys("title='a%2Fb,c]',edition=null")
+	assert.True(t, ok)
+	assert.Equal(t, []fragmentKey{{name: "title", value: "a/b,c]"}, {name: "edition", isNull: true}}, keys)
+
+	for _, invalid := range []string{"title", "='a'", "title='a", "title=a", "title='a'edition='1'", "title='%zz'"} {
+		_, ok = parseFragmentKeys(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestKeysURIFragment(t *testing.T) {
+	m := loadKeysModel(t)
+	eResource := NewEResourceImpl()
+	eLibrary := m.ePackage.GetEFactoryInstance().Create(m.libraryClass)
+	eResource.GetContents().Add(eLibrary)
+	eBooks := eLibrary.EGet(m.books).(EList)
+	dune := m.newBook("Dune", 1)
+	other := m.newBook("A/B 'c'", 2)
+	untitled := m.newBook(nil, 3)
+	eBooks.AddAll(NewImmutableEList([]any{dune, other, untitled}))
+
+	assert.Equal(t, "//@books[title='Dune',edition='1']", eResource.GetURIFragment(dune))
+	assert.Equal(t, "//@books[title='A%2FB%20%27c%27',edition='2']", eResource.GetURIFragment(other))
+	assert.Equal(t, "//@books[title='',edition='3']", eResource.GetURIFragment(untitled))
+	assert.Equal(t, dune, eResource.GetEObject("//@books[title='Dune',edition='1']"))
+	assert.Equal(t, other, eResource.GetEObject("//@books[title='A%2FB%20%27c%27',edition='2']"))
+	assert.Equal(t, untitled, eResource.GetEObject("//@books[title='',edition='3']"))
+	assert.Nil(t, eResource.GetEObject("//@books[title=null,edition='3']"))
+	assert.Nil(t, eResource.GetEObject("//@books[title='Dune',edition='2']"))
+	assert.Nil(t, eResource.GetEObject("//@books[unknown='Dune']"))
+	assert.Nil(t, eResource.GetEObject("//@books[title='Dune',edition]"))
+
+	// fragments do not depend on positions
+	eBooks.Insert(0, m.newBook("Foundation", 1))
+	assert.Equal(t, "//@books[title='Dune',edition='1']", eResource.GetURIFragment(dune))
+	assert.Equal(t, dune, eResource.GetEObject("//@books[title='Dune',edition='1']"))
+
+	// positional fragments of references without keys
+	borrower := m.ePackage.GetEFactoryInstance().Create(m.borrowerClass)
+	eLibrary.EGet(m.borrowers).(EList).Add(borrower)
+	assert.Equal(t, "//@borrowers.0", eResource.GetURIFragment(borrower))
+	assert.Equal(t, borrower, eResource.GetEObject("//@borrowers.0"))
+}
+
+func (m *keysModel) newLibraries(resourceSet EResourceSet, dir, ext string) []EResource {
+	eFactory := m.ePackage.GetEFactoryInstance()
+	libraryResource := resourceSet.CreateResource(NewURI(filepath.ToSlash(filepath.Join(dir, "library.keys."+ext))))
+	borrowersResource := resourceSet.CreateResource(NewURI(filepath.ToSlash(filepath.Join(dir, "library.keys.borrowers."+ext))))
+
+	eLibrary := eFactory.Create(m.libraryClass)
+	libraryResource.GetContents().Add(eLibrary)
+	dune := m.newBook("Dune", 1)
+	other := m.newBook("A/B 'c'", 2)
+	eLibrary.EGet(m.books).(EList).AddAll(NewImmutableEList([]any{dune, other}))
+	local := eFactory.Create(m.borrowerClass)
+	local.ESet(m.borrowerName, "local")
+	local.EGet(m.borrowed).(EList).AddAll(NewImmutableEList([]any{dune, other}))
+	eLibrary.EGet(m.borrowers).(EList).Add(local)
+
+	eBorrowers := eFactory.Create(m.libraryClass)
+	borrowersResource.GetContents().Add(eBorrowers)
+	remote := eFactory.Create(m.borrowerClass)
+	remote.ESet(m.borrowerName, "remote")
+	remote.EGet(m.borrowed).(EList).AddAll(NewImmutableEList([]any{other, dune}))
+	eBorrowers.EGet(m.borrowers).(EList).Add(remote)
+	return []EResource{libraryResource, borrowersResource}
+}
+
+// checkLibraries checks that the references of the borrowers of the libraries in resourceSet are resolved by keys
+func (m *keysModel) checkLibraries(t *testing.T, resourceSet EResourceSet, borrowersURI *URI) {
+	resourceSet.GetPackageRegistry().RegisterPackage(m.ePackage)
+	borrowersResource := resourceSet.GetResource(borrowersURI, true)
+	require.NotNil(t, borrowersResource)
+	require.True(t, borrowersResource.GetErrors().Empty(), diagnosticError(borrowersResource.GetErrors()))
+	remote := borrowersResource.GetContents().Get(0).(EObject).EGet(m.borrowers).(EList).Get(0).(EObject)
+	borrowed := remote.EGet(m.borrowed).(EList)
+	require.Equal(t, 2, borrowed.Size())
+	other := borrowed.Get(0).(EObject)
+	dune := borrowed.Get(1).(EObject)
+	assert.False(t, other.EIsProxy())
+	assert.Equal(t, "A/B 'c'", other.EGet(m.title))
+	assert.False(t, dune.EIsProxy())
+	assert.Equal(t, "Dune", dune.EGet(m.title))
+
+	libraryResource := dune.EResource()
+	require.NotNil(t, libraryResource)
+	eLibrary := libraryResource.GetContents().Get(0).(EObject)
+	local := eLibrary.EGet(m.borrowers).(EList).Get(0).(EObject)
+	assert.Equal(t, []any{dune, other}, local.EGet(m.borrowed).(EList).ToArray())
+}
+
+func TestKeysXMLEncoder(t *testing.T) {
+	m := loadKeysModel(t)
+	dir := t.TempDir()
+	for _, resource := range m.newLibraries(NewEResourceSetImpl(), dir, "xml") {
+		resource.Save()
+		require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	}
+	for _, fileName := range []string{"library.keys.xml", "library.keys.borrowers.xml"} {
+		expected, err := os.ReadFile(filepath.Join("testdata", fileName))
+		require.Nil(t, err)
+		actual, err := os.ReadFile(filepath.Join(dir, fileName))
+		require.Nil(t, err)
+		assert.Equal(t, strings.ReplaceAll(string(expected), "\r\n", "\n"), string(actual), fileName)
+	}
+}
+
+func TestKeysXMLDecoder(t *testing.T) {
+	m := loadKeysModel(t)
+	m.checkLibraries(t, NewEResourceSetImpl(), NewURI("testdata/library.keys.borrowers.xml"))
+}
+
+func TestKeysCodecs(t *testing.T) {
+	for _, ext := range []string{"ecore", "bin", "yaml"} {
+		t.Run(ext, func(t *testing.T) {
+			m := loadKeysModel(t)
+			dir := t.TempDir()
+			resources := m.newLibraries(NewEResourceSetImpl(), dir, ext)
+			for _, resource := range resources {
+				resource.Save()
+				require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+			}
+			m.checkLibraries(t, NewEResourceSetImpl(), resources[1].GetURI())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package ecore
 
 import (
+	"context"
 	"io"
 )
 
@@ -12,3 +13,17 @@ type EURIHandler interface {
 
 	CreateWriter(uri *URI) (io.WriteCloser, error)
 }
+
+// EAbortWriter is implemented by writers of uri handlers which are able to discard
+// what was written instead of committing it on Close
+type EAbortWriter interface {
+	Abort() error
+}
+
+// EContextURIHandler is implemented by uri handlers and uri converters which are able
+// to bind their readers and writers to a context
+type EContextURIHandler interface {
+	CreateReaderContext(ctx context.Context, uri *URI) (io.ReadCloser, error)
+
+	CreateWriterContext(ctx context.Context, uri *URI) (io.WriteCloser, error)
+}
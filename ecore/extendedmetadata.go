@@ -9,14 +9,25 @@
 
 package ecore
 
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
 const (
 	annotationURI = "http:///org/eclipse/emf/ecore/util/ExtendedMetaData"
 )
 
+// ExtendedMetaData is safe for concurrent use: its cache is guarded by mutex
 type ExtendedMetaData struct {
+	mutex    sync.Mutex
 	metaData map[any]any
 }
 
+// defaultExtendedMetaData is shared by objects storing feature values in feature maps
+var defaultExtendedMetaData = NewExtendedMetaData()
+
 type ENamedElementExtendedMetaData interface {
 	getName() string
 }
@@ -55,7 +66,7 @@ func (m *EPackageExtentedMetaDataImpl) getType(name string) EClassifier {
 			nameToClassifierMap := make(map[string]EClassifier)
 			for it := eClassifiers.Iterator(); it.HasNext(); {
 				eClassifier := it.Next().(EClassifier)
-				eClassifierName := m.emd.GetName(eClassifier)
+				eClassifierName := m.emd.getName(eClassifier)
 				nameToClassifierMap[eClassifierName] = eClassifier
 				if eClassifierName == name {
 					eResult = eClassifier
@@ -127,14 +138,25 @@ func (emd *ExtendedMetaData) getEPackageExtentedMetaData(ePackage EPackage) EPac
 }
 
 func (emd *ExtendedMetaData) GetType(ePackage EPackage, name string) EClassifier {
+	emd.mutex.Lock()
+	defer emd.mutex.Unlock()
 	return emd.getEPackageExtentedMetaData(ePackage).getType(name)
 }
 
 func (emd *ExtendedMetaData) GetName(eElement ENamedElement) string {
+	emd.mutex.Lock()
+	defer emd.mutex.Unlock()
+	return emd.getName(eElement)
+}
+
+// getName returns the name of eElement, mutex must be locked
+func (emd *ExtendedMetaData) getName(eElement ENamedElement) string {
 	return emd.getENamedElementExtendedMetaData(eElement).getName()
 }
 
 func (emd *ExtendedMetaData) GetNamespace(eFeature EStructuralFeature) string {
+	emd.mutex.Lock()
+	defer emd.mutex.Unlock()
 	return emd.getEStructuralFeatureExtentedMetaData(eFeature).getNamespace()
 }
 
@@ -194,3 +216,257 @@ func (emd *ExtendedMetaData) basicGetNamespace(eFeature EStructuralFeature) stri
 	}
 	return ""
 }
+
+// feature kinds
+const (
+	UNSPECIFIED_FEATURE = iota
+	SIMPLE_FEATURE
+	ATTRIBUTE_FEATURE
+	ATTRIBUTE_WILDCARD_FEATURE
+	ELEMENT_FEATURE
+	ELEMENT_WILDCARD_FEATURE
+	GROUP_FEATURE
+)
+
+var featureKinds = map[string]int{
+	"simple":            SIMPLE_FEATURE,
+	"attribute":         ATTRIBUTE_FEATURE,
+	"attributeWildcard": ATTRIBUTE_WILDCARD_FEATURE,
+	"element":           ELEMENT_FEATURE,
+	"elementWildcard":   ELEMENT_WILDCARD_FEATURE,
+	"group":             GROUP_FEATURE,
+}
+
+// content kinds
+const (
+	UNSPECIFIED_CONTENT = iota
+	EMPTY_CONTENT
+	SIMPLE_CONTENT
+	MIXED_CONTENT
+	ELEMENT_ONLY_CONTENT
+)
+
+var contentKinds = map[string]int{
+	"empty":       EMPTY_CONTENT,
+	"simple":      SIMPLE_CONTENT,
+	"mixed":       MIXED_CONTENT,
+	"elementOnly": ELEMENT_ONLY_CONTENT,
+}
+
+const mixedFeatureName = ":mixed"
+
+func (emd *ExtendedMetaData) getAnnotationDetail(eElement EModelElement, key string) string {
+	if annotation := eElement.GetEAnnotation(annotationURI); annotation != nil {
+		if value, _ := annotation.GetDetails().GetValue(key).(string); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// GetFeatureKind returns the kind of eFeature in xml documents
+func (emd *ExtendedMetaData) GetFeatureKind(eFeature EStructuralFeature) int {
+	return featureKinds[emd.getAnnotationDetail(eFeature, "kind")]
+}
+
+// GetContentKind returns the kind of content of eClass in xml documents
+func (emd *ExtendedMetaData) GetContentKind(eClass EClass) int {
+	return contentKinds[emd.getAnnotationDetail(eClass, "kind")]
+}
+
+// GetMixedFeature returns the feature map storing elements and text of a mixed content eClass
+func (emd *ExtendedMetaData) GetMixedFeature(eClass EClass) EAttribute {
+	if emd.GetContentKind(eClass) == MIXED_CONTENT {
+		for it := eClass.GetEAllAttributes().Iterator(); it.HasNext(); {
+			eAttribute := it.Next().(EAttribute)
+			if emd.GetName(eAttribute) == mixedFeatureName {
+				return eAttribute
+			}
+		}
+	}
+	return nil
+}
+
+// GetGroup returns the group feature of eFeature
+func (emd *ExtendedMetaData) GetGroup(eFeature EStructuralFeature) EStructuralFeature {
+	if qualifiedName := emd.getAnnotationDetail(eFeature, "group"); qualifiedName != "" {
+		if eClass := eFeature.GetEContainingClass(); eClass != nil {
+			namespace, name := emd.splitQualifiedName(eFeature, qualifiedName)
+			return emd.getClassFeature(eClass, namespace, name, nil)
+		}
+	}
+	return nil
+}
+
+// GetAffiliation returns the head of the substitution group of the global element eFeature
+func (emd *ExtendedMetaData) GetAffiliation(eFeature EStructuralFeature) EStructuralFeature {
+	if qualifiedName := emd.getAnnotationDetail(eFeature, "affiliation"); qualifiedName != "" {
+		namespace, name := emd.splitQualifiedName(eFeature, qualifiedName)
+		if ePackage := emd.getPackage(eFeature, namespace); ePackage != nil {
+			return emd.GetElement(ePackage, name)
+		}
+	}
+	return nil
+}
+
+// GetWildcards returns the namespaces matched by the wildcard eFeature.
+// "##any" matches any namespace, "!ns" any namespace except ns and "" the absent namespace
+func (emd *ExtendedMetaData) GetWildcards(eFeature EStructuralFeature) []string {
+	value := emd.getAnnotationDetail(eFeature, "wildcards")
+	if value == "" {
+		return nil
+	}
+	targetNamespace := ""
+	if eClass := eFeature.GetEContainingClass(); eClass != nil {
+		if ePackage := eClass.GetEPackage(); ePackage != nil {
+			targetNamespace = ePackage.GetNsURI()
+		}
+	}
+	wildcards := strings.Fields(value)
+	for i, wildcard := range wildcards {
+		switch wildcard {
+		case "##other":
+			wildcards[i] = "!" + targetNamespace
+		case "##local":
+			wildcards[i] = ""
+		case "##targetNamespace":
+			wildcards[i] = targetNamespace
+		}
+	}
+	return wildcards
+}
+
+// MatchesWildcard returns true if namespace is matched by one of the wildcards
+func (emd *ExtendedMetaData) MatchesWildcard(wildcards []string, namespace string) bool {
+	for _, wildcard := range wildcards {
+		switch {
+		case wildcard == "##any":
+			return true
+		case strings.HasPrefix(wildcard, "!"):
+			if namespace != "" && namespace != wildcard[1:] {
+				return true
+			}
+		case wildcard == namespace:
+			return true
+		}
+	}
+	return false
+}
+
+// GetElement returns the global element named name in ePackage
+func (emd *ExtendedMetaData) GetElement(ePackage EPackage, name string) EStructuralFeature {
+	return emd.getGlobalFeature(ePackage, name, ELEMENT_FEATURE)
+}
+
+// GetAttribute returns the global attribute named name in ePackage
+func (emd *ExtendedMetaData) GetAttribute(ePackage EPackage, name string) EStructuralFeature {
+	return emd.getGlobalFeature(ePackage, name, ATTRIBUTE_FEATURE)
+}
+
+// GetClassAffiliation returns the feature of eClass which accepts the values of eFeature :
+// eFeature itself, a feature with the same xml name, the head of its substitution group or a wildcard
+func (emd *ExtendedMetaData) GetClassAffiliation(eClass EClass, eFeature EStructuralFeature) EStructuralFeature {
+	if eClass.GetFeatureID(eFeature) >= 0 {
+		return eFeature
+	}
+	namespace := emd.GetNamespace(eFeature)
+	switch kind := emd.GetFeatureKind(eFeature); kind {
+	case ATTRIBUTE_FEATURE:
+		if eResult := emd.getClassFeature(eClass, namespace, emd.GetName(eFeature), []int{ATTRIBUTE_FEATURE}); eResult != nil {
+			return eResult
+		}
+		return emd.getClassWildcard(eClass, namespace, ATTRIBUTE_WILDCARD_FEATURE)
+	case ELEMENT_FEATURE, UNSPECIFIED_FEATURE:
+		if eResult := emd.getClassFeature(eClass, namespace, emd.GetName(eFeature), []int{ELEMENT_FEATURE, UNSPECIFIED_FEATURE}); eResult != nil {
+			return eResult
+		}
+		if affiliation := emd.GetAffiliation(eFeature); affiliation != nil {
+			if eResult := emd.GetClassAffiliation(eClass, affiliation); eResult != nil {
+				return eResult
+			}
+		}
+		return emd.getClassWildcard(eClass, namespace, ELEMENT_WILDCARD_FEATURE)
+	}
+	return nil
+}
+
+// GetFeatureMap returns the feature map storing the values of eFeature in instances of eClass
+// or nil if these values are not stored in a feature map
+func (emd *ExtendedMetaData) GetFeatureMap(eClass EClass, eFeature EStructuralFeature) EStructuralFeature {
+	if eClass.GetFeatureID(eFeature) >= 0 {
+		if !eFeature.IsDerived() {
+			return nil
+		}
+		if group := emd.GetGroup(eFeature); group != nil && group != eFeature {
+			return group
+		}
+		switch emd.GetFeatureKind(eFeature) {
+		case SIMPLE_FEATURE, ATTRIBUTE_FEATURE, ATTRIBUTE_WILDCARD_FEATURE:
+			return nil
+		}
+		if mixed := emd.GetMixedFeature(eClass); mixed != nil && mixed != eFeature {
+			return mixed
+		}
+		return nil
+	}
+	if affiliation := emd.GetClassAffiliation(eClass, eFeature); affiliation != nil {
+		if IsFeatureMap(affiliation) && !affiliation.IsDerived() {
+			return affiliation
+		}
+		return emd.GetFeatureMap(eClass, affiliation)
+	}
+	return nil
+}
+
+// splitQualifiedName splits a qualified name 'namespace#name' of an annotation of eFeature
+func (emd *ExtendedMetaData) splitQualifiedName(eFeature EStructuralFeature, qualifiedName string) (string, string) {
+	switch index := strings.LastIndex(qualifiedName, "#"); index {
+	case -1:
+		return emd.GetNamespace(eFeature), qualifiedName
+	case 0:
+		return "", qualifiedName[1:]
+	default:
+		return qualifiedName[:index], qualifiedName[index+1:]
+	}
+}
+
+func (emd *ExtendedMetaData) getPackage(eFeature EStructuralFeature, namespace string) EPackage {
+	if eClass := eFeature.GetEContainingClass(); eClass != nil {
+		if ePackage := eClass.GetEPackage(); ePackage != nil && ePackage.GetNsURI() == namespace {
+			return ePackage
+		}
+	}
+	return GetPackageRegistry().GetPackage(namespace)
+}
+
+func (emd *ExtendedMetaData) getGlobalFeature(ePackage EPackage, name string, kind int) EStructuralFeature {
+	if documentRoot := emd.GetDocumentRoot(ePackage); documentRoot != nil {
+		for it := documentRoot.GetEAllStructuralFeatures().Iterator(); it.HasNext(); {
+			eFeature := it.Next().(EStructuralFeature)
+			if emd.GetName(eFeature) == name && emd.GetFeatureKind(eFeature) == kind {
+				return eFeature
+			}
+		}
+	}
+	return nil
+}
+
+func (emd *ExtendedMetaData) getClassFeature(eClass EClass, namespace string, name string, kinds []int) EStructuralFeature {
+	for it := eClass.GetEAllStructuralFeatures().Iterator(); it.HasNext(); {
+		eFeature := it.Next().(EStructuralFeature)
+		if emd.GetName(eFeature) == name && emd.GetNamespace(eFeature) == namespace && (kinds == nil || slices.Contains(kinds, emd.GetFeatureKind(eFeature))) {
+			return eFeature
+		}
+	}
+	return nil
+}
+
+func (emd *ExtendedMetaData) getClassWildcard(eClass EClass, namespace string, kind int) EStructuralFeature {
+	for it := eClass.GetEAllAttributes().Iterator(); it.HasNext(); {
+		eAttribute := it.Next().(EAttribute)
+		if emd.GetFeatureKind(eAttribute) == kind && emd.MatchesWildcard(emd.GetWildcards(eAttribute), namespace) {
+			return eAttribute
+		}
+	}
+	return nil
+}
@@ -10,10 +10,12 @@
 package ecore
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtendedMetatData_GetName(t *testing.T) {
@@ -39,6 +41,23 @@ func TestExtendedMetatData_GetName(t *testing.T) {
 	mock.AssertExpectationsForObjects(t, mockFeature, mockAnnotation, mockDetails)
 }
 
+func TestExtendedMetatData_GetNameConcurrent(t *testing.T) {
+	m := NewExtendedMetaData()
+	mockElement := NewMockENamedElement(t)
+	// name is computed once
+	mockElement.EXPECT().GetEAnnotation(annotationURI).Return(nil).Once()
+	mockElement.EXPECT().GetName().Return("name").Once()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "name", m.GetName(mockElement))
+		}()
+	}
+	wg.Wait()
+}
+
 func TestExtendedMetatData_GetType(t *testing.T) {
 	m := NewExtendedMetaData()
 	mockPackage := NewMockEPackage(t)
@@ -130,3 +149,68 @@ func TestExtendedMetatData_GetDocumentRoot(t *testing.T) {
 		mock.AssertExpectationsForObjects(t, mockPackage, mockClass1, mockClass2)
 	}
 }
+
+func TestExtendedMetatData_GetFeatureKind(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	emd := NewExtendedMetaData()
+	assert.Equal(t, GROUP_FEATURE, emd.GetFeatureKind(m.shapeGroup))
+	assert.Equal(t, ELEMENT_FEATURE, emd.GetFeatureKind(m.shape))
+	assert.Equal(t, ELEMENT_WILDCARD_FEATURE, emd.GetFeatureKind(m.documentAny))
+	assert.Equal(t, ATTRIBUTE_WILDCARD_FEATURE, emd.GetFeatureKind(m.documentAnyAttrs))
+	assert.Equal(t, ATTRIBUTE_FEATURE, emd.GetFeatureKind(m.shapeName))
+	assert.Equal(t, UNSPECIFIED_FEATURE, emd.GetFeatureKind(GetXMLTypeTextFeature()))
+}
+
+func TestExtendedMetatData_GetContentKind(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	emd := NewExtendedMetaData()
+	assert.Equal(t, ELEMENT_ONLY_CONTENT, emd.GetContentKind(m.documentClass))
+	assert.Equal(t, MIXED_CONTENT, emd.GetContentKind(m.paragraphClass))
+	assert.Equal(t, UNSPECIFIED_CONTENT, emd.GetContentKind(m.circleClass))
+	assert.Equal(t, m.paragraphMixed, emd.GetMixedFeature(m.paragraphClass))
+	assert.Nil(t, emd.GetMixedFeature(m.documentClass))
+}
+
+func TestExtendedMetatData_GetGroupAndAffiliation(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	emd := NewExtendedMetaData()
+	assert.Equal(t, m.shapeGroup, emd.GetGroup(m.shape))
+	assert.Nil(t, emd.GetGroup(m.shapeGroup))
+
+	shapeElement := emd.GetElement(m.ePackage, "shape")
+	require.NotNil(t, shapeElement)
+	assert.Equal(t, shapeElement, emd.GetAffiliation(m.circleElement))
+	assert.Nil(t, emd.GetAffiliation(shapeElement))
+	assert.Nil(t, emd.GetAttribute(m.ePackage, "shape"))
+}
+
+func TestExtendedMetatData_GetWildcards(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	emd := NewExtendedMetaData()
+	wildcards := emd.GetWildcards(m.documentAny)
+	assert.Equal(t, []string{"!http://www.masagroup.com/featuremap"}, wildcards)
+	assert.True(t, emd.MatchesWildcard(wildcards, "http://www.masagroup.com/extension"))
+	assert.False(t, emd.MatchesWildcard(wildcards, "http://www.masagroup.com/featuremap"))
+	assert.False(t, emd.MatchesWildcard(wildcards, ""))
+	assert.True(t, emd.MatchesWildcard([]string{"##any"}, ""))
+	assert.True(t, emd.MatchesWildcard([]string{""}, ""))
+	assert.Nil(t, emd.GetWildcards(m.shape))
+}
+
+func TestExtendedMetatData_GetClassAffiliation(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	emd := NewExtendedMetaData()
+	assert.Equal(t, m.shape, emd.GetClassAffiliation(m.documentClass, m.shape))
+	assert.Equal(t, m.shape, emd.GetClassAffiliation(m.documentClass, m.circleElement))
+	assert.Nil(t, emd.GetClassAffiliation(m.paragraphClass, m.circleElement))
+}
+
+func TestExtendedMetatData_GetFeatureMap(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	emd := NewExtendedMetaData()
+	assert.Equal(t, m.shapeGroup, emd.GetFeatureMap(m.documentClass, m.shape))
+	assert.Equal(t, m.shapeGroup, emd.GetFeatureMap(m.documentClass, m.circleElement))
+	assert.Equal(t, m.paragraphMixed, emd.GetFeatureMap(m.paragraphClass, m.paragraphBold))
+	assert.Nil(t, emd.GetFeatureMap(m.documentClass, m.paragraphs))
+	assert.Nil(t, emd.GetFeatureMap(m.documentClass, m.shapeGroup))
+}
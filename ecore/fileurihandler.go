@@ -1,13 +1,99 @@
 package ecore
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 )
 
+type fileURIHandlerOption interface {
+	apply(*FileURIHandler)
+}
+
+type funcFileURIHandlerOption struct {
+	f func(*FileURIHandler)
+}
+
+func (ffo *funcFileURIHandlerOption) apply(h *FileURIHandler) {
+	ffo.f(h)
+}
+
+func newFuncFileURIHandlerOption(f func(*FileURIHandler)) *funcFileURIHandlerOption {
+	return &funcFileURIHandlerOption{
+		f: f,
+	}
+}
+
+// FileURIHandlerBackups keeps n numbered backups ( file.1 being the most recent ) of replaced files
+func FileURIHandlerBackups(n int) fileURIHandlerOption {
+	return newFuncFileURIHandlerOption(func(h *FileURIHandler) {
+		h.backups = n
+	})
+}
+
 // URIHandler ...
 type FileURIHandler struct {
+	backups int
+}
+
+// atomicFileWriter writes in a temporary file of the target directory.
+// Target is replaced on Close and left untouched on Abort
+type atomicFileWriter struct {
+	*os.File
+	fileName string
+	backups  int
+}
+
+func (w *atomicFileWriter) Close() error {
+	if err := w.File.Sync(); err != nil {
+		w.Abort()
+		return err
+	}
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+	if err := rotateFileBackups(w.fileName, w.backups); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+	if err := os.Rename(w.File.Name(), w.fileName); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+	// rename is durable once the directory entry is synced
+	return syncDir(filepath.Dir(w.fileName))
+}
+
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		// directories can't be synced on windows
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (w *atomicFileWriter) Abort() error {
+	w.File.Close()
+	return os.Remove(w.File.Name())
+}
+
+func NewFileURIHandler(opts ...fileURIHandlerOption) *FileURIHandler {
+	h := &FileURIHandler{}
+	for _, opt := range opts {
+		opt.apply(h)
+	}
+	return h
 }
 
 func (fuh *FileURIHandler) CanHandle(uri *URI) bool {
@@ -15,10 +101,7 @@ func (fuh *FileURIHandler) CanHandle(uri *URI) bool {
 }
 
 func (fuh *FileURIHandler) CreateReader(uri *URI) (io.ReadCloser, error) {
-	fileName := uri.Path()
-	if runtime.GOOS == "windows" && fileName[0] == '/' {
-		fileName = fileName[1:]
-	}
+	fileName := getFileName(uri)
 	f, err := os.Open(fileName)
 	if err != nil {
 		return nil, err
@@ -27,13 +110,59 @@ func (fuh *FileURIHandler) CreateReader(uri *URI) (io.ReadCloser, error) {
 }
 
 func (fuh *FileURIHandler) CreateWriter(uri *URI) (io.WriteCloser, error) {
+	fileName := getFileName(uri)
+	f, err := os.CreateTemp(filepath.Dir(fileName), "."+filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	// keep permissions of the replaced file
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(fileName); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &atomicFileWriter{File: f, fileName: fileName, backups: fuh.backups}, nil
+}
+
+func getFileName(uri *URI) string {
 	fileName := uri.Path()
 	if runtime.GOOS == "windows" && fileName[0] == '/' {
 		fileName = fileName[1:]
 	}
-	f, err := os.Create(fileName)
-	if err != nil {
-		return nil, err
+	return fileName
+}
+
+// rotateFileBackups shifts backups of fileName and links the current file as its first backup
+func rotateFileBackups(fileName string, backups int) error {
+	if backups <= 0 {
+		return nil
 	}
-	return f, nil
+	if _, err := os.Stat(fileName); os.IsNotExist(err) {
+		return nil
+	}
+	backupName := func(i int) string {
+		return fmt.Sprintf("%s.%d", fileName, i)
+	}
+	if err := os.Remove(backupName(backups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := backups - 1; i > 0; i-- {
+		if err := os.Rename(backupName(i), backupName(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	// current file stays in place until it is replaced
+	if err := os.Link(fileName, backupName(1)); err != nil {
+		// file system without hard links
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(backupName(1), content, 0644)
+	}
+	return nil
 }
@@ -0,0 +1,127 @@
+package ecore
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFileURI(t *testing.T, h *FileURIHandler, uri *URI, content string) {
+	w, err := h.CreateWriter(uri)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func TestFileURIHandler_CanHandle(t *testing.T) {
+	h := NewFileURIHandler()
+	assert.True(t, h.CanHandle(NewURI("file:///path/file.xml")))
+	assert.True(t, h.CanHandle(NewURI("path/file.xml")))
+	assert.False(t, h.CanHandle(NewURI("http://host/file.xml")))
+}
+
+func TestFileURIHandler_CreateWriter_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(fileName, []byte("previous"), 0600))
+	uri := CreateFileURI(fileName)
+
+	h := NewFileURIHandler()
+	w, err := h.CreateWriter(uri)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "content")
+	require.NoError(t, err)
+
+	// target is untouched until writer is closed
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+
+	require.NoError(t, w.Close())
+	content, err = os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	// permissions are kept and no temporary file is left
+	info, err := os.Stat(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestFileURIHandler_CreateWriter_Abort(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(fileName, []byte("previous"), 0644))
+
+	w, err := NewFileURIHandler().CreateWriter(CreateFileURI(fileName))
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "content")
+	require.NoError(t, err)
+	abortWriter, _ := w.(EAbortWriter)
+	require.NotNil(t, abortWriter)
+	require.NoError(t, abortWriter.Abort())
+
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestFileURIHandler_Backups(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "file.txt")
+	uri := CreateFileURI(fileName)
+	h := NewFileURIHandler(FileURIHandlerBackups(2))
+	for _, content := range []string{"v1", "v2", "v3", "v4"} {
+		writeFileURI(t, h, uri, content)
+	}
+	for name, expected := range map[string]string{fileName: "v4", fileName + ".1": "v3", fileName + ".2": "v2"} {
+		content, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+	_, err := os.Stat(fileName + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileURIHandler_SyncDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, syncDir(dir))
+	if runtime.GOOS != "windows" {
+		assert.Error(t, syncDir(filepath.Join(dir, "unknown")))
+	}
+}
+
+func TestFileURIHandler_SaveWithErrors(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(fileName, []byte("previous"), 0644))
+
+	mockCodec := NewMockECodec(t)
+	mockEncoder := NewMockEEncoder(t)
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetCodecRegistry().GetExtensionToCodecMap()["txt"] = mockCodec
+	resource := resourceSet.CreateResource(CreateFileURI(fileName))
+
+	// encoder fails after writing
+	mockCodec.EXPECT().NewEncoder(resource, mock.Anything, mock.Anything).Return(mockEncoder).Once()
+	mockEncoder.EXPECT().EncodeResource().Run(func() {
+		resource.GetErrors().Add(NewEDiagnosticImpl("error", fileName, 0, 0))
+	}).Once()
+	resource.Save()
+	assert.Equal(t, 1, resource.GetErrors().Size())
+
+	content, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+}
@@ -0,0 +1,70 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// FSURIHandler reads resources from a fs.FS ( embed.FS, fstest.MapFS, os.DirFS ... ).
+// It handles uris starting with its prefix, the remaining path is the name of the file in the fs.FS
+type FSURIHandler struct {
+	fsys   fs.FS
+	prefix *URI
+}
+
+func NewFSURIHandler(fsys fs.FS, prefix *URI) *FSURIHandler {
+	return &FSURIHandler{fsys: fsys, prefix: prefix}
+}
+
+func (fsh *FSURIHandler) CanHandle(uri *URI) bool {
+	return uri.scheme == fsh.prefix.scheme &&
+		uri.Authority() == fsh.prefix.Authority() &&
+		hasPathPrefix(uri.path, fsh.prefix.path)
+}
+
+// hasPathPrefix reports whether prefix is path or one of its parent directories
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || len(prefix) == 0 || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+func (fsh *FSURIHandler) CreateReader(uri *URI) (io.ReadCloser, error) {
+	name, err := fsh.getName(uri)
+	if err != nil {
+		return nil, err
+	}
+	return fsh.fsys.Open(name)
+}
+
+func (fsh *FSURIHandler) CreateWriter(uri *URI) (io.WriteCloser, error) {
+	name, err := fsh.getName(uri)
+	if err != nil {
+		return nil, err
+	}
+	// fs.FS is read only
+	return nil, &fs.PathError{Op: "write", Path: name, Err: errors.ErrUnsupported}
+}
+
+func (fsh *FSURIHandler) getName(uri *URI) (string, error) {
+	name := strings.TrimPrefix(strings.TrimPrefix(uri.path, fsh.prefix.path), "/")
+	if len(name) == 0 {
+		name = "."
+	}
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return name, nil
+}
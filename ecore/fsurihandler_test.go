@@ -0,0 +1,66 @@
+package ecore
+
+import (
+	"embed"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/library.simple.ecore
+var fsURIHandlerTestFS embed.FS
+
+func TestFSURIHandler_CanHandle(t *testing.T) {
+	h := NewFSURIHandler(fstest.MapFS{}, NewURI("embed:/models/"))
+	assert.True(t, h.CanHandle(NewURI("embed:/models/library.ecore")))
+	assert.False(t, h.CanHandle(NewURI("embed:/other/library.ecore")))
+	assert.False(t, h.CanHandle(NewURI("file:/models/library.ecore")))
+
+	h = NewFSURIHandler(fstest.MapFS{}, NewURI("embed:/models"))
+	assert.True(t, h.CanHandle(NewURI("embed:/models")))
+	assert.True(t, h.CanHandle(NewURI("embed:/models/library.ecore")))
+	assert.False(t, h.CanHandle(NewURI("embed:/models2/library.ecore")))
+	assert.False(t, h.CanHandle(NewURI("embed:/modelsx")))
+}
+
+func TestFSURIHandler_CreateReader(t *testing.T) {
+	h := NewFSURIHandler(fstest.MapFS{"models/library.ecore": {Data: []byte("content")}}, NewURI("embed:/"))
+	r, err := h.CreateReader(NewURI("embed:/models/library.ecore"))
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "content", string(content))
+
+	_, err = h.CreateReader(NewURI("embed:/models/unknown.ecore"))
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, err = h.CreateReader(NewURI("embed:/models/../../library.ecore"))
+	assert.True(t, errors.Is(err, fs.ErrInvalid))
+}
+
+func TestFSURIHandler_CreateWriter(t *testing.T) {
+	h := NewFSURIHandler(fstest.MapFS{}, NewURI("embed:/"))
+	w, err := h.CreateWriter(NewURI("embed:/models/library.ecore"))
+	assert.Nil(t, w)
+	assert.True(t, errors.Is(err, errors.ErrUnsupported))
+}
+
+func TestFSURIHandler_ResourceSet(t *testing.T) {
+	resourceSet := NewEResourceSetImpl()
+	uriConverter := resourceSet.GetURIConverter()
+	uriConverter.GetURIHandlers().Insert(0, NewFSURIHandler(fsURIHandlerTestFS, NewURI("embed:/")))
+	uriConverter.GetURIMap()[*NewURI("platform:/resource/")] = *NewURI("embed:/testdata/")
+
+	resource := resourceSet.GetResource(NewURI("platform:/resource/library.simple.ecore"), true)
+	require.NotNil(t, resource)
+	require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	ePackage, _ := resource.GetContents().Get(0).(EPackage)
+	require.NotNil(t, ePackage)
+	assert.Equal(t, "library", ePackage.GetName())
+}
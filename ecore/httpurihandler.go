@@ -0,0 +1,225 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// HTTPError is returned when a server answers with a non 2xx status
+type HTTPError struct {
+	URI        string
+	Method     string
+	Status     string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s '%s' failed: %s", e.Method, e.URI, e.Status)
+}
+
+type httpURIHandlerOption interface {
+	apply(*HTTPURIHandler)
+}
+
+type funcHTTPURIHandlerOption struct {
+	f func(*HTTPURIHandler)
+}
+
+func (fho *funcHTTPURIHandlerOption) apply(h *HTTPURIHandler) {
+	fho.f(h)
+}
+
+func newFuncHTTPURIHandlerOption(f func(*HTTPURIHandler)) *funcHTTPURIHandlerOption {
+	return &funcHTTPURIHandlerOption{
+		f: f,
+	}
+}
+
+// HTTPURIHandlerClient sets the client used to send requests
+func HTTPURIHandlerClient(client *http.Client) httpURIHandlerOption {
+	return newFuncHTTPURIHandlerOption(func(h *HTTPURIHandler) {
+		h.client = client
+	})
+}
+
+// HTTPURIHandlerHeader adds a header to all requests
+func HTTPURIHandlerHeader(key string, value string) httpURIHandlerOption {
+	return newFuncHTTPURIHandlerOption(func(h *HTTPURIHandler) {
+		h.header.Add(key, value)
+	})
+}
+
+// HTTPURIHandlerBasicAuth sets basic authentication of all requests
+func HTTPURIHandlerBasicAuth(username string, password string) httpURIHandlerOption {
+	return newFuncHTTPURIHandlerOption(func(h *HTTPURIHandler) {
+		h.username = username
+		h.password = password
+	})
+}
+
+// HTTPURIHandlerWriteMethod sets the method used to write resources ( PUT by default )
+func HTTPURIHandlerWriteMethod(method string) httpURIHandlerOption {
+	return newFuncHTTPURIHandlerOption(func(h *HTTPURIHandler) {
+		h.writeMethod = method
+	})
+}
+
+// HTTPURIHandler reads resources with GET and writes them with PUT or POST when the writer is closed.
+// ETag of a read or written resource is sent in an If-Match header when it is written again.
+type HTTPURIHandler struct {
+	client      *http.Client
+	header      http.Header
+	username    string
+	password    string
+	writeMethod string
+	mutex       sync.Mutex
+	etags       map[string]string
+}
+
+type httpWriter struct {
+	bytes.Buffer
+	ctx     context.Context
+	handler *HTTPURIHandler
+	uri     *URI
+}
+
+func (w *httpWriter) Close() error {
+	return w.handler.write(w.ctx, w.uri, w.Bytes())
+}
+
+func (w *httpWriter) Abort() error {
+	w.Reset()
+	return nil
+}
+
+func NewHTTPURIHandler(opts ...httpURIHandlerOption) *HTTPURIHandler {
+	h := &HTTPURIHandler{
+		header: http.Header{},
+	}
+	for _, opt := range opts {
+		opt.apply(h)
+	}
+	return h
+}
+
+func (h *HTTPURIHandler) CanHandle(uri *URI) bool {
+	return uri.scheme == "http" || uri.scheme == "https"
+}
+
+func (h *HTTPURIHandler) CreateReader(uri *URI) (io.ReadCloser, error) {
+	return h.CreateReaderContext(context.Background(), uri)
+}
+
+// CreateReaderContext sends the GET request with ctx which also bounds reading of the response body
+func (h *HTTPURIHandler) CreateReaderContext(ctx context.Context, uri *URI) (io.ReadCloser, error) {
+	request, err := h.newRequest(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := h.getClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.checkResponse(request, response); err != nil {
+		return nil, err
+	}
+	h.setETag(uri, response.Header.Get("ETag"))
+	return response.Body, nil
+}
+
+func (h *HTTPURIHandler) CreateWriter(uri *URI) (io.WriteCloser, error) {
+	return h.CreateWriterContext(context.Background(), uri)
+}
+
+// CreateWriterContext returns a writer sending its content with ctx when it is closed
+func (h *HTTPURIHandler) CreateWriterContext(ctx context.Context, uri *URI) (io.WriteCloser, error) {
+	return &httpWriter{ctx: ctx, handler: h, uri: uri}, nil
+}
+
+func (h *HTTPURIHandler) write(ctx context.Context, uri *URI, content []byte) error {
+	method := h.writeMethod
+	if len(method) == 0 {
+		method = http.MethodPut
+	}
+	request, err := h.newRequest(ctx, method, uri, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	if etag := h.getETag(uri); len(etag) > 0 {
+		request.Header.Set("If-Match", etag)
+	}
+	response, err := h.getClient().Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if err := h.checkResponse(request, response); err != nil {
+		return err
+	}
+	h.setETag(uri, response.Header.Get("ETag"))
+	return nil
+}
+
+func (h *HTTPURIHandler) newRequest(ctx context.Context, method string, uri *URI, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, uri.TrimFragment().String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range h.header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	if len(h.username) > 0 || len(h.password) > 0 {
+		request.SetBasicAuth(h.username, h.password)
+	}
+	return request, nil
+}
+
+func (h *HTTPURIHandler) checkResponse(request *http.Request, response *http.Response) error {
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		response.Body.Close()
+		return &HTTPError{URI: request.URL.String(), Method: request.Method, Status: response.Status, StatusCode: response.StatusCode}
+	}
+	return nil
+}
+
+func (h *HTTPURIHandler) getClient() *http.Client {
+	if h.client == nil {
+		return http.DefaultClient
+	}
+	return h.client
+}
+
+func (h *HTTPURIHandler) getETag(uri *URI) string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.etags[uri.TrimFragment().String()]
+}
+
+func (h *HTTPURIHandler) setETag(uri *URI, etag string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	key := uri.TrimFragment().String()
+	if len(etag) == 0 {
+		delete(h.etags, key)
+		return
+	}
+	if h.etags == nil {
+		h.etags = map[string]string{}
+	}
+	h.etags[key] = etag
+}
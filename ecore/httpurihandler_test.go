@@ -0,0 +1,177 @@
+package ecore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type httpTestStore struct {
+	mutex    sync.Mutex
+	contents map[string][]byte
+	versions map[string]int
+	requests []*http.Request
+}
+
+func newHTTPTestServer(t *testing.T, contents map[string][]byte) (*httptest.Server, *httpTestStore) {
+	store := &httpTestStore{contents: contents, versions: map[string]int{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		store.mutex.Lock()
+		defer store.mutex.Unlock()
+		store.requests = append(store.requests, r)
+		if user, password, ok := r.BasicAuth(); ok && (user != "user" || password != "password") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		etag := strconv.Quote(strconv.Itoa(store.versions[r.URL.Path]))
+		switch r.Method {
+		case http.MethodGet:
+			content, isContent := store.contents[r.URL.Path]
+			if !isContent {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Write(content)
+		case http.MethodPut, http.MethodPost:
+			if ifMatch := r.Header.Get("If-Match"); len(ifMatch) > 0 && ifMatch != etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			content, _ := io.ReadAll(r.Body)
+			store.contents[r.URL.Path] = content
+			store.versions[r.URL.Path]++
+			w.Header().Set("ETag", strconv.Quote(strconv.Itoa(store.versions[r.URL.Path])))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, store
+}
+
+func TestHTTPURIHandler_CanHandle(t *testing.T) {
+	h := NewHTTPURIHandler()
+	assert.True(t, h.CanHandle(NewURI("http://host/file.xml")))
+	assert.True(t, h.CanHandle(NewURI("https://host/file.xml")))
+	assert.False(t, h.CanHandle(NewURI("file:///file.xml")))
+	assert.False(t, h.CanHandle(NewURI("file.xml")))
+}
+
+func TestHTTPURIHandler_CreateReader(t *testing.T) {
+	server, store := newHTTPTestServer(t, map[string][]byte{"/file.txt": []byte("content")})
+	h := NewHTTPURIHandler(HTTPURIHandlerClient(server.Client()), HTTPURIHandlerHeader("X-Test", "value"), HTTPURIHandlerBasicAuth("user", "password"))
+
+	r, err := h.CreateReader(NewURI(server.URL + "/file.txt"))
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "content", string(content))
+	require.Len(t, store.requests, 1)
+	assert.Equal(t, "value", store.requests[0].Header.Get("X-Test"))
+
+	_, err = h.CreateReader(NewURI(server.URL + "/unknown.txt"))
+	require.Error(t, err)
+	httpError, _ := err.(*HTTPError)
+	require.NotNil(t, httpError)
+	assert.Equal(t, http.StatusNotFound, httpError.StatusCode)
+}
+
+func TestHTTPURIHandler_BasicAuth(t *testing.T) {
+	server, _ := newHTTPTestServer(t, map[string][]byte{"/file.txt": []byte("content")})
+	h := NewHTTPURIHandler(HTTPURIHandlerClient(server.Client()), HTTPURIHandlerBasicAuth("user", "invalid"))
+	_, err := h.CreateReader(NewURI(server.URL + "/file.txt"))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(*HTTPError).StatusCode)
+}
+
+func TestHTTPURIHandler_Context(t *testing.T) {
+	server, store := newHTTPTestServer(t, map[string][]byte{"/file.txt": []byte("content")})
+	h := NewHTTPURIHandler(HTTPURIHandlerClient(server.Client()))
+	uri := NewURI(server.URL + "/file.txt")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := h.CreateReaderContext(ctx, uri)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	w, err := h.CreateWriterContext(ctx, uri)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("new content"))
+	require.NoError(t, err)
+	assert.ErrorIs(t, w.Close(), context.Canceled)
+	assert.Equal(t, "content", string(store.contents["/file.txt"]))
+	assert.Empty(t, store.requests)
+}
+
+func TestHTTPURIHandler_CreateWriter(t *testing.T) {
+	server, store := newHTTPTestServer(t, map[string][]byte{"/file.txt": []byte("content")})
+	h := NewHTTPURIHandler(HTTPURIHandlerClient(server.Client()), HTTPURIHandlerWriteMethod(http.MethodPost))
+	uri := NewURI(server.URL + "/file.txt")
+
+	// read to retrieve etag
+	r, err := h.CreateReader(uri)
+	require.NoError(t, err)
+	r.Close()
+
+	w, err := h.CreateWriter(uri)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("new content"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "new content", string(store.contents["/file.txt"]))
+	request := store.requests[len(store.requests)-1]
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, `"0"`, request.Header.Get("If-Match"))
+
+	// concurrent modification
+	store.versions["/file.txt"]++
+	w, err = h.CreateWriter(uri)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("other content"))
+	require.NoError(t, err)
+	err = w.Close()
+	require.Error(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, err.(*HTTPError).StatusCode)
+	assert.Equal(t, "new content", string(store.contents["/file.txt"]))
+}
+
+func TestHTTPURIHandler_ResourceSet(t *testing.T) {
+	content, err := os.ReadFile("testdata/library.simple.ecore")
+	require.NoError(t, err)
+	server, store := newHTTPTestServer(t, map[string][]byte{"/library.simple.ecore": content})
+
+	// load with default uri converter
+	resourceSet := NewEResourceSetImpl()
+	resource := resourceSet.GetResource(NewURI(server.URL+"/library.simple.ecore"), true)
+	require.NotNil(t, resource)
+	require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	ePackage, _ := resource.GetContents().Get(0).(EPackage)
+	require.NotNil(t, ePackage)
+	assert.Equal(t, "library", ePackage.GetName())
+
+	// save
+	ePackage.SetName("library2")
+	resource.Save()
+	require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	assert.Contains(t, string(store.contents["/library.simple.ecore"]), `name="library2"`)
+
+	// errors end up in resource errors
+	store.versions["/library.simple.ecore"]++
+	resource.Save()
+	assert.Equal(t, 1, resource.GetErrors().Size())
+
+	resource = resourceSet.GetResource(NewURI(server.URL+"/unknown.ecore"), true)
+	require.NotNil(t, resource)
+	assert.Equal(t, 1, resource.GetErrors().Size())
+}
@@ -157,6 +157,10 @@ func (e *JSONEncoder) shouldSaveFeature(o EObject, f EStructuralFeature) bool {
 }
 
 func (e *JSONEncoder) getClassName(eClass EClass) string {
+	return getJSONClassName(eClass)
+}
+
+func getJSONClassName(eClass EClass) string {
 	ePackage := eClass.GetEPackage()
 	return ePackage.GetNsURI() + "#//" + eClass.GetName()
 }
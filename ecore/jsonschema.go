@@ -0,0 +1,285 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"errors"
+	"io"
+
+	"github.com/karlseguin/jsonwriter"
+)
+
+const (
+	JSON_SCHEMA_OPTION_SCHEMA_VERSION = "SCHEMA_VERSION" // the $schema dialect uri (default is draft 2020-12)
+)
+
+const jsonSchemaDefaultVersion = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchemaGenerator writes the JSON Schema of the documents produced by JSONEncoder
+// for the classes of an EPackage. Every EClass is mapped to a definition in $defs where
+// super types are expressed with allOf. Classifiers from other packages referenced by
+// the package are added to the definitions with their package prefix.
+type JSONSchemaGenerator struct {
+	ePackage        EPackage
+	w               *jsonwriter.Writer
+	featureKinds    map[EStructuralFeature]jsonFeatureKind
+	classifiers     []EClassifier
+	classifiersSet  map[EClassifier]struct{}
+	idAttributeName string
+	schemaVersion   string
+}
+
+func NewJSONSchemaGenerator(ePackage EPackage, w io.Writer, options map[string]any) *JSONSchemaGenerator {
+	g := &JSONSchemaGenerator{
+		ePackage:       ePackage,
+		w:              jsonwriter.New(w),
+		featureKinds:   map[EStructuralFeature]jsonFeatureKind{},
+		classifiersSet: map[EClassifier]struct{}{},
+		schemaVersion:  jsonSchemaDefaultVersion,
+	}
+	if options != nil {
+		g.idAttributeName, _ = options[JSON_OPTION_ID_ATTRIBUTE_NAME].(string)
+		if version, _ := options[JSON_SCHEMA_OPTION_SCHEMA_VERSION].(string); len(version) > 0 {
+			g.schemaVersion = version
+		}
+	}
+	return g
+}
+
+func (g *JSONSchemaGenerator) Generate() error {
+	if g.ePackage == nil {
+		return errors.New("json schema generation requires a package")
+	}
+	g.collectClassifiers()
+	g.w.RootObject(func() {
+		g.keyString("$schema", g.schemaVersion)
+		g.keyString("$id", g.ePackage.GetNsURI())
+		g.keyString("title", g.ePackage.GetName())
+		// a document is one of the concrete classes of the package
+		g.w.Array("anyOf", func() {
+			for _, eClassifier := range g.classifiers {
+				if eClass, _ := eClassifier.(EClass); eClass != nil && eClass.GetEPackage() == g.ePackage && !eClass.IsAbstract() && !eClass.IsInterface() {
+					g.w.ArrayObject(func() {
+						g.keyString("$ref", g.getDefinitionRef(eClass))
+					})
+				}
+			}
+		})
+		g.w.Object("$defs", func() {
+			for _, eClassifier := range g.classifiers {
+				g.w.Object(g.getDefinitionName(eClassifier), func() {
+					switch c := eClassifier.(type) {
+					case EClass:
+						g.generateClass(c)
+					case EEnum:
+						g.generateEnum(c)
+					}
+				})
+			}
+		})
+	})
+	return nil
+}
+
+func (g *JSONSchemaGenerator) collectClassifiers() {
+	for itClassifier := g.ePackage.GetEClassifiers().Iterator(); itClassifier.HasNext(); {
+		g.addClassifier(itClassifier.Next().(EClassifier))
+	}
+	// classifiers from other packages are appended while iterating
+	for i := 0; i < len(g.classifiers); i++ {
+		eClass, _ := g.classifiers[i].(EClass)
+		if eClass == nil {
+			continue
+		}
+		for itSuper := eClass.GetESuperTypes().Iterator(); itSuper.HasNext(); {
+			g.addClassifier(itSuper.Next().(EClassifier))
+		}
+		for itFeature := eClass.GetEStructuralFeatures().Iterator(); itFeature.HasNext(); {
+			eFeature := itFeature.Next().(EStructuralFeature)
+			if eType := eFeature.GetEType(); eType != nil {
+				g.addClassifier(eType)
+			}
+		}
+	}
+}
+
+func (g *JSONSchemaGenerator) addClassifier(eClassifier EClassifier) {
+	switch eClassifier.(type) {
+	case EClass, EEnum:
+		if _, isCollected := g.classifiersSet[eClassifier]; !isCollected {
+			g.classifiersSet[eClassifier] = struct{}{}
+			g.classifiers = append(g.classifiers, eClassifier)
+		}
+	}
+}
+
+func (g *JSONSchemaGenerator) generateClass(eClass EClass) {
+	g.keyString("type", "object")
+	if superTypes := eClass.GetESuperTypes(); !superTypes.Empty() {
+		g.w.Array("allOf", func() {
+			for itSuper := superTypes.Iterator(); itSuper.HasNext(); {
+				eSuper := itSuper.Next().(EClass)
+				g.w.ArrayObject(func() {
+					g.keyString("$ref", g.getDefinitionRef(eSuper))
+				})
+			}
+		})
+	}
+	g.w.Object("properties", func() {
+		g.w.Object("eClass", func() {
+			g.generateClassName(eClass)
+		})
+		if len(g.idAttributeName) > 0 {
+			g.w.Object(g.idAttributeName, func() {
+				g.keyString("type", "string")
+			})
+		}
+		for itFeature := eClass.GetEStructuralFeatures().Iterator(); itFeature.HasNext(); {
+			eFeature := itFeature.Next().(EStructuralFeature)
+			g.generateFeature(eFeature)
+		}
+	})
+	g.w.Array("required", func() {
+		g.w.Value("eClass")
+	})
+}
+
+// generateClassName writes the eClass discriminator: the class names of eClass and
+// of all its concrete sub classes, so that the allOf intersection with super types
+// definitions is the set of names valid for eClass
+func (g *JSONSchemaGenerator) generateClassName(eClass EClass) {
+	classNames := []string{}
+	for _, eClassifier := range g.classifiers {
+		if eOther, _ := eClassifier.(EClass); eOther != nil && !eOther.IsAbstract() && !eOther.IsInterface() {
+			if eOther == eClass || eOther.GetEAllSuperTypes().Contains(eClass) {
+				classNames = append(classNames, getJSONClassName(eOther))
+			}
+		}
+	}
+	if len(classNames) == 0 {
+		g.keyString("type", "string")
+	} else {
+		g.w.Array("enum", func() {
+			for _, className := range classNames {
+				g.w.RawValue([]byte(jsonEscape(className)))
+			}
+		})
+	}
+}
+
+func (g *JSONSchemaGenerator) generateEnum(eEnum EEnum) {
+	g.keyString("type", "string")
+	g.w.Array("enum", func() {
+		for itLiteral := eEnum.GetELiterals().Iterator(); itLiteral.HasNext(); {
+			eLiteral := itLiteral.Next().(EEnumLiteral)
+			g.w.RawValue([]byte(jsonEscape(eLiteral.GetLiteral())))
+		}
+	})
+}
+
+func (g *JSONSchemaGenerator) generateFeature(eFeature EStructuralFeature) {
+	kind, ok := g.featureKinds[eFeature]
+	if !ok {
+		kind = getJSONCodecFeatureKind(eFeature)
+		g.featureKinds[eFeature] = kind
+	}
+	switch kind {
+	case jfkData:
+		g.w.Object(eFeature.GetName(), func() {
+			g.generateData(eFeature)
+		})
+	case jfkDataList:
+		g.w.Object(eFeature.GetName(), func() {
+			g.keyString("type", "array")
+			g.w.Object("items", func() {
+				g.generateData(eFeature)
+			})
+		})
+	case jfkObject:
+		g.w.Object(eFeature.GetName(), func() {
+			g.generateObject(eFeature)
+		})
+	case jfkObjectList:
+		g.w.Object(eFeature.GetName(), func() {
+			g.keyString("type", "array")
+			g.w.Object("items", func() {
+				g.generateObject(eFeature)
+			})
+		})
+	case jfkObjectReference:
+		g.w.Object(eFeature.GetName(), func() {
+			g.generateObjectReference(eFeature)
+		})
+	case jfkObjectReferenceList:
+		g.w.Object(eFeature.GetName(), func() {
+			g.keyString("type", "array")
+			g.w.Object("items", func() {
+				g.generateObjectReference(eFeature)
+			})
+		})
+	}
+}
+
+func (g *JSONSchemaGenerator) generateData(eFeature EStructuralFeature) {
+	// JSONEncoder writes every data value as its string literal
+	if eEnum, _ := eFeature.GetEType().(EEnum); eEnum != nil {
+		g.keyString("$ref", g.getDefinitionRef(eEnum))
+	} else {
+		g.keyString("type", "string")
+	}
+}
+
+func (g *JSONSchemaGenerator) generateObject(eFeature EStructuralFeature) {
+	if eClass, _ := eFeature.GetEType().(EClass); eClass != nil {
+		g.keyString("$ref", g.getDefinitionRef(eClass))
+	} else {
+		g.keyString("type", "object")
+	}
+}
+
+// generateObjectReference writes the schema of the reference encoding of JSONEncoder:
+// an object with the class name and the uri of the referenced object
+func (g *JSONSchemaGenerator) generateObjectReference(eFeature EStructuralFeature) {
+	g.keyString("type", "object")
+	g.w.Object("properties", func() {
+		g.w.Object("eClass", func() {
+			if eClass, _ := eFeature.GetEType().(EClass); eClass != nil {
+				g.generateClassName(eClass)
+			} else {
+				g.keyString("type", "string")
+			}
+		})
+		g.w.Object("eRef", func() {
+			g.keyString("type", "string")
+			g.keyString("format", "uri-reference")
+		})
+	})
+	g.w.Array("required", func() {
+		g.w.Value("eClass")
+		g.w.Value("eRef")
+	})
+}
+
+func (g *JSONSchemaGenerator) getDefinitionName(eClassifier EClassifier) string {
+	if ePackage := eClassifier.GetEPackage(); ePackage != nil && ePackage != g.ePackage {
+		return ePackage.GetNsPrefix() + "." + eClassifier.GetName()
+	}
+	return eClassifier.GetName()
+}
+
+func (g *JSONSchemaGenerator) getDefinitionRef(eClassifier EClassifier) string {
+	return "#/$defs/" + g.getDefinitionName(eClassifier)
+}
+
+func (g *JSONSchemaGenerator) keyString(key string, value string) {
+	// don't use g.w.KeyString because json escaping is bugged
+	// in jsonwriter
+	g.w.KeyRaw(key, []byte(jsonEscape(value)))
+}
@@ -0,0 +1,86 @@
+package ecore
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchemaGenerator_GenerateNoPackage(t *testing.T) {
+	var buffer bytes.Buffer
+	g := NewJSONSchemaGenerator(nil, &buffer, nil)
+	require.Error(t, g.Generate())
+}
+
+func TestJSONSchemaGenerator_GenerateComplex(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	var buffer bytes.Buffer
+	g := NewJSONSchemaGenerator(ePackage, &buffer, nil)
+	require.NoError(t, g.Generate())
+
+	//os.WriteFile("testdata/library.complex.schema.json", buffer.Bytes(), 0644)
+
+	bytes, err := os.ReadFile("testdata/library.complex.schema.json")
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(buffer.String(), "\r\n", "\n"))
+}
+
+func TestJSONSchemaGenerator_GenerateDefinitions(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	var buffer bytes.Buffer
+	g := NewJSONSchemaGenerator(ePackage, &buffer, map[string]any{JSON_OPTION_ID_ATTRIBUTE_NAME: "id"})
+	require.NoError(t, g.Generate())
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &schema))
+	assert.Equal(t, jsonSchemaDefaultVersion, schema["$schema"])
+	assert.Equal(t, ePackage.GetNsURI(), schema["$id"])
+
+	definitions, _ := schema["$defs"].(map[string]any)
+	require.NotNil(t, definitions)
+
+	// class with super types
+	book, _ := definitions["Book"].(map[string]any)
+	require.NotNil(t, book)
+	assert.Equal(t, []any{map[string]any{"$ref": "#/$defs/CirculatingItem"}}, book["allOf"])
+	properties, _ := book["properties"].(map[string]any)
+	require.NotNil(t, properties)
+	assert.Equal(t, map[string]any{"enum": []any{ePackage.GetNsURI() + "#//Book"}}, properties["eClass"])
+	assert.Equal(t, map[string]any{"type": "string"}, properties["id"])
+	assert.Equal(t, map[string]any{"$ref": "#/$defs/BookCategory"}, properties["category"])
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"eClass": map[string]any{"enum": []any{ePackage.GetNsURI() + "#//Writer"}},
+			"eRef":   map[string]any{"type": "string", "format": "uri-reference"},
+		},
+		"required": []any{"eClass", "eRef"},
+	}, properties["author"])
+
+	// abstract class discriminator lists its concrete sub classes
+	item, _ := definitions["AudioVisualItem"].(map[string]any)
+	require.NotNil(t, item)
+	properties, _ = item["properties"].(map[string]any)
+	require.NotNil(t, properties)
+	assert.Equal(t, map[string]any{"enum": []any{ePackage.GetNsURI() + "#//BookOnTape", ePackage.GetNsURI() + "#//VideoCassette"}}, properties["eClass"])
+
+	// many valued containment
+	library, _ := definitions["Library"].(map[string]any)
+	require.NotNil(t, library)
+	properties, _ = library["properties"].(map[string]any)
+	require.NotNil(t, properties)
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"$ref": "#/$defs/Book"}}, properties["books"])
+
+	// enum
+	category, _ := definitions["BookCategory"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"Mystery", "ScienceFiction", "Biography"}}, category)
+}
@@ -0,0 +1,52 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"sync"
+
+	"github.com/petermattis/goid"
+)
+
+// loadingWaitGraph records for each goroutine waiting for the end of a loading the goroutine owning this loading.
+// Loadings depending on each other, in one or several goroutines, are detected instead of waiting forever
+type loadingWaitGraph struct {
+	mutex sync.Mutex
+	waits map[int64]int64
+}
+
+var loadingWaits = &loadingWaitGraph{waits: map[int64]int64{}}
+
+// beginWait registers that the current goroutine waits for a loading owned by owner and returns the current goroutine id.
+// It returns false without registering if owner is the current goroutine or waits, directly or not, for it
+func (g *loadingWaitGraph) beginWait(owner int64) (int64, bool) {
+	id := goid.Get()
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	for current := owner; ; {
+		if current == id {
+			return id, false
+		}
+		next, isWaiting := g.waits[current]
+		if !isWaiting {
+			break
+		}
+		current = next
+	}
+	g.waits[id] = owner
+	return id, true
+}
+
+// endWait unregisters the wait of goroutine id
+func (g *loadingWaitGraph) endWait(id int64) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	delete(g.waits, id)
+}
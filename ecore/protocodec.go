@@ -0,0 +1,122 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"io"
+)
+
+const (
+	PROTO_OPTION_ID_ATTRIBUTE = "ID_ATTRIBUTE" // if true, save id attribute of the object
+	PROTO_OPTION_PACKAGE      = "PACKAGE"      // the EPackage describing the resource messages ( EPackage )
+)
+
+// PROTO_ANNOTATION_SOURCE is the source of the annotations used to pin protobuf numbers:
+// the 'number' detail of an EStructuralFeature is its field number, the 'number' detail
+// of an EClass is its case number in the oneof of its super classes
+const PROTO_ANNOTATION_SOURCE = "http://net.masagroup/soft/2025/Protobuf"
+
+type ProtoCodec struct {
+}
+
+func (pc *ProtoCodec) NewEncoder(resource EResource, w io.Writer, options map[string]any) EEncoder {
+	return NewProtoEncoder(resource, w, options)
+}
+func (pc *ProtoCodec) NewDecoder(resource EResource, r io.Reader, options map[string]any) EDecoder {
+	return NewProtoDecoder(resource, r, options)
+}
+
+type protoFeatureKind int
+
+const (
+	pfkTransient protoFeatureKind = iota
+	pfkData
+	pfkDataList
+	pfkObject
+	pfkObjectList
+	pfkObjectReference
+	pfkObjectReferenceList
+)
+
+func getProtoCodecFeatureKind(eFeature EStructuralFeature) protoFeatureKind {
+	if eFeature.IsTransient() {
+		return pfkTransient
+	} else if eReference, _ := eFeature.(EReference); eReference != nil {
+		if eReference.IsContainment() {
+			if eReference.IsMany() {
+				return pfkObjectList
+			} else {
+				return pfkObject
+			}
+		}
+		if eReference.IsContainer() {
+			return pfkTransient
+		}
+		if eReference.IsMany() {
+			return pfkObjectReferenceList
+		} else {
+			return pfkObjectReference
+		}
+	} else if eAttribute, _ := eFeature.(EAttribute); eAttribute != nil {
+		if eAttribute.IsMany() {
+			return pfkDataList
+		} else {
+			return pfkData
+		}
+	}
+	return -1
+}
+
+type protoScalarKind int
+
+const (
+	pskString protoScalarKind = iota
+	pskBytes
+	pskBool
+	pskInt
+	pskInt64
+	pskInt32
+	pskInt16
+	pskByte
+	pskFloat64
+	pskFloat32
+	pskEnum
+	pskData
+)
+
+func getProtoCodecScalarKind(eDataType EDataType) protoScalarKind {
+	if eEnum, _ := eDataType.(EEnum); eEnum != nil {
+		return pskEnum
+	}
+	switch getInstanceTypeName(eDataType) {
+	case "float64", "java.lang.Double", "double":
+		return pskFloat64
+	case "float32", "java.lang.Float", "float":
+		return pskFloat32
+	case "int", "java.lang.Integer":
+		return pskInt
+	case "int64", "java.lang.Long", "java.math.BigInteger", "long":
+		return pskInt64
+	case "int32":
+		return pskInt32
+	case "int16", "java.lang.Short", "short":
+		return pskInt16
+	case "byte":
+		return pskByte
+	case "bool", "java.lang.Boolean", "boolean":
+		return pskBool
+	case "string", "java.lang.String":
+		return pskString
+	case "[]byte", "java.util.ByteArray":
+		return pskBytes
+	}
+	// any other data type is encoded with its string literal
+	return pskData
+}
@@ -0,0 +1,318 @@
+package ecore
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoCodec_NewEncoder(t *testing.T) {
+	mockResource := NewMockEResource(t)
+	mockResource.EXPECT().GetURI().Return(nil).Once()
+	codec := &ProtoCodec{}
+	require.NotNil(t, codec.NewEncoder(mockResource, nil, nil))
+}
+
+func TestProtoCodec_NewDecoder(t *testing.T) {
+	mockResource := NewMockEResource(t)
+	mockResource.EXPECT().GetURI().Return(nil).Once()
+	codec := &ProtoCodec{}
+	require.NotNil(t, codec.NewDecoder(mockResource, nil, nil))
+}
+
+func TestGetProtoCodecFeatureKind_Attribute(t *testing.T) {
+	mockAttribute := NewMockEAttribute(t)
+	mockAttribute.EXPECT().IsTransient().Return(true).Once()
+	require.Equal(t, pfkTransient, getProtoCodecFeatureKind(mockAttribute))
+	mockAttribute.EXPECT().IsTransient().Return(false).Once()
+	mockAttribute.EXPECT().IsMany().Return(false).Once()
+	require.Equal(t, pfkData, getProtoCodecFeatureKind(mockAttribute))
+	mockAttribute.EXPECT().IsTransient().Return(false).Once()
+	mockAttribute.EXPECT().IsMany().Return(true).Once()
+	require.Equal(t, pfkDataList, getProtoCodecFeatureKind(mockAttribute))
+}
+
+func TestGetProtoCodecFeatureKind_Reference(t *testing.T) {
+	mockReference := NewMockEReference(t)
+	mockReference.EXPECT().IsTransient().Return(false).Once()
+	mockReference.EXPECT().IsContainment().Return(true).Once()
+	mockReference.EXPECT().IsMany().Return(true).Once()
+	require.Equal(t, pfkObjectList, getProtoCodecFeatureKind(mockReference))
+
+	mockReference.EXPECT().IsTransient().Return(false).Once()
+	mockReference.EXPECT().IsContainment().Return(true).Once()
+	mockReference.EXPECT().IsMany().Return(false).Once()
+	require.Equal(t, pfkObject, getProtoCodecFeatureKind(mockReference))
+
+	mockReference.EXPECT().IsTransient().Return(false).Once()
+	mockReference.EXPECT().IsContainment().Return(false).Once()
+	mockReference.EXPECT().IsContainer().Return(true).Once()
+	require.Equal(t, pfkTransient, getProtoCodecFeatureKind(mockReference))
+
+	mockReference.EXPECT().IsTransient().Return(false).Once()
+	mockReference.EXPECT().IsContainment().Return(false).Once()
+	mockReference.EXPECT().IsContainer().Return(false).Once()
+	mockReference.EXPECT().IsMany().Return(true).Once()
+	require.Equal(t, pfkObjectReferenceList, getProtoCodecFeatureKind(mockReference))
+
+	mockReference.EXPECT().IsTransient().Return(false).Once()
+	mockReference.EXPECT().IsContainment().Return(false).Once()
+	mockReference.EXPECT().IsContainer().Return(false).Once()
+	mockReference.EXPECT().IsMany().Return(false).Once()
+	require.Equal(t, pfkObjectReference, getProtoCodecFeatureKind(mockReference))
+}
+
+func TestProtoCodec_EncodeDecodeResource(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.complex.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// encode
+	var buffer bytes.Buffer
+	codec := &ProtoCodec{}
+	codec.NewEncoder(eResource, &buffer, nil).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// decode in a resource with the same uri, package is retrieved from its namespace
+	eNewResourceSet := NewEResourceSetImpl()
+	eNewResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eNewResource := eNewResourceSet.CreateResource(NewURI("testdata/library.complex.xml"))
+	codec.NewDecoder(eNewResource, &buffer, nil).DecodeResource()
+	require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+	require.Equal(t, 1, eNewResource.GetContents().Size())
+
+	// document roots differ by their transient namespaces maps
+	eDocumentRootClass, _ := ePackage.GetEClassifier("DocumentRoot").(EClass)
+	require.NotNil(t, eDocumentRootClass)
+	eLibraryFeature := eDocumentRootClass.GetEStructuralFeatureFromName("library")
+	eLibrary, _ := eResource.GetContents().Get(0).(EObject).EGet(eLibraryFeature).(EObject)
+	require.NotNil(t, eLibrary)
+	eNewLibrary, _ := eNewResource.GetContents().Get(0).(EObject).EGet(eLibraryFeature).(EObject)
+	require.NotNil(t, eNewLibrary)
+	assert.True(t, Equals(eLibrary, eNewLibrary))
+}
+
+func TestProtoCodec_EncodeDecodeObject_WithExternalReferences(t *testing.T) {
+	eResourceSet := NewEResourceSetImpl()
+	codecOptions := map[string]any{PROTO_OPTION_ID_ATTRIBUTE: true}
+	_, eShopPackage := loadTestPackage(t, eResourceSet, NewURI("testdata/shop.ecore"))
+	require.NotNil(t, eShopPackage)
+	_, eShopModel := loadTestModel(t, eResourceSet, NewURI("testdata/shop.xml"))
+	require.NotNil(t, eShopModel)
+	_, eOrdersPackage := loadTestPackage(t, eResourceSet, NewURI("testdata/orders.ecore"))
+	require.NotNil(t, eOrdersPackage)
+	eOrdersModelResource, eOrdersModel := loadTestModel(t, eResourceSet, NewURI("testdata/orders.xml"))
+	require.NotNil(t, eOrdersModel)
+	ResolveAll(eOrdersModel)
+
+	// encode orders
+	var buffer bytes.Buffer
+	encoder := NewProtoEncoder(eOrdersModelResource, &buffer, codecOptions)
+	require.NoError(t, encoder.EncodeObject(eOrdersModel))
+	eResourceSet.GetResources().Remove(eOrdersModelResource)
+
+	// decode orders
+	eNewResource := NewEResourceImpl()
+	eNewResource.SetObjectIDManager(NewIncrementalIDManager())
+	eNewResource.SetURI(NewURI("testdata/orders.xml"))
+	eResourceSet.GetResources().Add(eNewResource)
+	decoder := NewProtoDecoder(eNewResource, &buffer, map[string]any{PROTO_OPTION_PACKAGE: eOrdersPackage})
+	eNewOrders, err := decoder.DecodeObject()
+	require.NoError(t, err)
+	require.NotNil(t, eNewOrders)
+	assert.Equal(t, eOrdersModelResource.GetObjectIDManager().GetID(eOrdersModel), eNewResource.GetObjectIDManager().GetID(eNewOrders))
+
+	eOrdersClass, _ := eOrdersPackage.GetEClassifier("Orders").(EClass)
+	require.NotNil(t, eOrdersClass)
+	eOrderClass, _ := eOrdersPackage.GetEClassifier("Order").(EClass)
+	require.NotNil(t, eOrderClass)
+	eOrder, _ := eNewOrders.EGet(eOrdersClass.GetEStructuralFeatureFromName("order")).(EList).Get(0).(EObject)
+	require.NotNil(t, eOrder)
+
+	// product is a proxy to the shop resource resolved once decoded object is in the resource
+	eNewResource.GetContents().Add(eNewOrders)
+	eProduct, _ := eOrder.EGetResolve(eOrderClass.GetEStructuralFeatureFromName("product"), false).(EObject)
+	require.NotNil(t, eProduct)
+	assert.True(t, eProduct.EIsProxy())
+	eProduct, _ = eOrder.EGet(eOrderClass.GetEStructuralFeatureFromName("product")).(EObject)
+	require.NotNil(t, eProduct)
+	assert.False(t, eProduct.EIsProxy())
+}
+
+func TestProtoDecoder_UnknownPackage(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.simple.xml"))
+	require.NotNil(t, eResource)
+
+	var buffer bytes.Buffer
+	NewProtoEncoder(eResource, &buffer, nil).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	eNewResource := NewEResourceImpl()
+	NewProtoDecoder(eNewResource, &buffer, nil).DecodeResource()
+	assert.Equal(t, 1, eNewResource.GetErrors().Size())
+}
+
+func TestProtoDecoder_Invalid(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+	eResource := NewEResourceImpl()
+	decoder := NewProtoDecoder(eResource, bytes.NewReader([]byte{0x12, 0xFF}), map[string]any{PROTO_OPTION_PACKAGE: ePackage})
+	_, err := decoder.DecodeObject()
+	assert.Error(t, err)
+}
+
+type protoBookCategory int
+
+// protoTypedEnumFactory is a factory creating values of a named enum type, like generated factories
+type protoTypedEnumFactory struct {
+	*EFactoryExt
+}
+
+func (f *protoTypedEnumFactory) CreateFromString(eDataType EDataType, literalValue string) any {
+	if eEnum, _ := eDataType.(EEnum); eEnum != nil {
+		return protoBookCategory(f.EFactoryExt.CreateFromString(eDataType, literalValue).(int))
+	}
+	return f.EFactoryExt.CreateFromString(eDataType, literalValue)
+}
+
+func (f *protoTypedEnumFactory) ConvertToString(eDataType EDataType, instanceValue any) string {
+	if category, isCategory := instanceValue.(protoBookCategory); isCategory {
+		if eDataType.(EEnum).GetEEnumLiteralByValue(int(category)) == nil {
+			return "Unknown"
+		}
+		return f.EFactoryExt.ConvertToString(eDataType, int(category))
+	}
+	return f.EFactoryExt.ConvertToString(eDataType, instanceValue)
+}
+
+func TestProtoCodec_EncodeDecodeTypedEnum(t *testing.T) {
+	f := GetFactory()
+	ePackage := f.CreateEPackage()
+	ePackage.SetName("books")
+	ePackage.SetNsPrefix("books")
+	ePackage.SetNsURI("http://www.masagroup.com/books")
+	eFactory := &protoTypedEnumFactory{EFactoryExt: newEFactoryExt()}
+	eFactory.SetInterfaces(eFactory)
+	ePackage.SetEFactoryInstance(eFactory)
+
+	eCategory := f.CreateEEnum()
+	eCategory.SetName("BookCategory")
+	for i, name := range []string{"Mystery", "ScienceFiction", "Biography"} {
+		eLiteral := f.CreateEEnumLiteral()
+		eLiteral.SetName(name)
+		eLiteral.SetLiteral(name)
+		eLiteral.SetValue(i)
+		eCategory.GetELiterals().Add(eLiteral)
+	}
+	eBookClass := f.CreateEClass()
+	eBookClass.SetName("Book")
+	eCategoryAttribute := f.CreateEAttribute()
+	eCategoryAttribute.SetName("category")
+	eCategoryAttribute.SetEType(eCategory)
+	eBookClass.GetEStructuralFeatures().Add(eCategoryAttribute)
+	ePackage.GetEClassifiers().AddAll(NewImmutableEList([]any{eCategory, eBookClass}))
+
+	eBook := eFactory.Create(eBookClass)
+	eBook.ESet(eCategoryAttribute, protoBookCategory(2))
+	eResource := NewEResourceImpl()
+	eResource.SetURI(NewURI("books.pb"))
+	eResource.GetContents().Add(eBook)
+
+	// encode
+	var buffer bytes.Buffer
+	codec := &ProtoCodec{}
+	codec.NewEncoder(eResource, &buffer, nil).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// decode
+	eNewResourceSet := NewEResourceSetImpl()
+	eNewResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eNewResource := eNewResourceSet.CreateResource(NewURI("books.pb"))
+	codec.NewDecoder(eNewResource, &buffer, nil).DecodeResource()
+	require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+	require.Equal(t, 1, eNewResource.GetContents().Size())
+	eNewBook, _ := eNewResource.GetContents().Get(0).(EObject)
+	require.NotNil(t, eNewBook)
+	assert.Equal(t, protoBookCategory(2), eNewBook.EGet(eCategoryAttribute))
+
+	// unknown literal
+	eBook.ESet(eCategoryAttribute, protoBookCategory(7))
+	buffer.Reset()
+	codec.NewEncoder(eResource, &buffer, nil).EncodeResource()
+	assert.Equal(t, 1, eResource.GetErrors().Size())
+}
+
+type protoTitle string
+type protoPages int
+type protoRating float32
+type protoAvailable bool
+
+func TestProtoCodec_EncodeDecodeNamedTypes(t *testing.T) {
+	f := GetFactory()
+	ePackage := f.CreateEPackage()
+	ePackage.SetName("books")
+	ePackage.SetNsPrefix("books")
+	ePackage.SetNsURI("http://www.masagroup.com/books")
+
+	eBookClass := f.CreateEClass()
+	eBookClass.SetName("Book")
+	ePackage.GetEClassifiers().Add(eBookClass)
+	values := map[string]any{
+		"string":  protoTitle("Dune"),
+		"int":     protoPages(412),
+		"float32": protoRating(4.5),
+		"bool":    protoAvailable(true),
+	}
+	eAttributes := map[string]EAttribute{}
+	for _, name := range []string{"string", "int", "float32", "bool"} {
+		// generated data types have a default value of their named type
+		eDataType := f.CreateEDataType()
+		eDataType.SetName(name + "Type")
+		eDataType.SetInstanceTypeName(name)
+		eDataType.(EDataTypeInternal).SetDefaultValue(reflect.Zero(reflect.TypeOf(values[name])).Interface())
+		ePackage.GetEClassifiers().Add(eDataType)
+		eAttribute := f.CreateEAttribute()
+		eAttribute.SetName(name)
+		eAttribute.SetEType(eDataType)
+		eBookClass.GetEStructuralFeatures().Add(eAttribute)
+		eAttributes[name] = eAttribute
+	}
+
+	eBook := ePackage.GetEFactoryInstance().Create(eBookClass)
+	for name, value := range values {
+		eBook.ESet(eAttributes[name], value)
+	}
+	eResource := NewEResourceImpl()
+	eResource.SetURI(NewURI("books.pb"))
+	eResource.GetContents().Add(eBook)
+
+	// encode
+	var buffer bytes.Buffer
+	codec := &ProtoCodec{}
+	codec.NewEncoder(eResource, &buffer, nil).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// decode
+	eNewResourceSet := NewEResourceSetImpl()
+	eNewResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eNewResource := eNewResourceSet.CreateResource(NewURI("books.pb"))
+	codec.NewDecoder(eNewResource, &buffer, nil).DecodeResource()
+	require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+	require.Equal(t, 1, eNewResource.GetContents().Size())
+	eNewBook, _ := eNewResource.GetContents().Get(0).(EObject)
+	require.NotNil(t, eNewBook)
+	for name, value := range values {
+		assert.Equal(t, value, eNewBook.EGet(eAttributes[name]), name)
+	}
+}
@@ -0,0 +1,435 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type protoReference struct {
+	eObject  EObject
+	eFeature EStructuralFeature
+	uri      string
+}
+
+type ProtoDecoder struct {
+	resource   EResource
+	r          io.Reader
+	ePackage   EPackage
+	schema     *ProtoSchema
+	baseURI    *URI
+	references []protoReference
+}
+
+func NewProtoDecoder(resource EResource, r io.Reader, options map[string]any) *ProtoDecoder {
+	d := &ProtoDecoder{
+		resource: resource,
+		r:        r,
+	}
+	if uri := resource.GetURI(); uri != nil {
+		d.baseURI = uri
+	}
+	if options != nil {
+		d.ePackage, _ = options[PROTO_OPTION_PACKAGE].(EPackage)
+	}
+	return d
+}
+
+func (d *ProtoDecoder) DecodeResource() {
+	var err error
+	defer func() {
+		if err != nil {
+			// add error to resource errors
+			resourcePath := ""
+			if d.resource.GetURI() != nil {
+				resourcePath = d.resource.GetURI().String()
+			}
+			d.resource.GetErrors().Add(NewEDiagnosticImpl(err.Error(), resourcePath, 0, 0))
+		}
+	}()
+	var objects []any
+	if objects, err = d.decodeResource(); err != nil {
+		return
+	}
+	// add objects to resource
+	d.resource.GetContents().AddAll(NewImmutableEList(objects))
+	// resolve references once all objects are in the resource
+	d.resolveReferences()
+}
+
+func (d *ProtoDecoder) DecodeObject() (EObject, error) {
+	objects, err := d.decodeResource()
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, errors.New("no object decoded")
+	}
+	d.resolveReferences()
+	return objects[0].(EObject), nil
+}
+
+func (d *ProtoDecoder) decodeResource() ([]any, error) {
+	b, err := io.ReadAll(d.r)
+	if err != nil {
+		return nil, err
+	}
+
+	// package
+	if d.schema == nil {
+		ePackage := d.ePackage
+		if ePackage == nil {
+			nsURI, err := d.decodeNsURI(b)
+			if err != nil {
+				return nil, err
+			}
+			packageRegistry := GetPackageRegistry()
+			if resourceSet := d.resource.GetResourceSet(); resourceSet != nil {
+				packageRegistry = resourceSet.GetPackageRegistry()
+			}
+			if ePackage = packageRegistry.GetPackage(nsURI); ePackage == nil {
+				return nil, fmt.Errorf("unable to find package '%s'", nsURI)
+			}
+		}
+		if d.schema, err = NewProtoSchema(ePackage); err != nil {
+			return nil, err
+		}
+	}
+
+	// contents
+	objects := []any{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num == protoResourceContentsNumber && typ == protowire.BytesType {
+			content, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			eObject, err := d.decodeOneOf(content, d.schema.contents)
+			if err != nil {
+				return nil, err
+			}
+			if eObject != nil {
+				objects = append(objects, eObject)
+			}
+			b = b[n:]
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return objects, nil
+}
+
+func (d *ProtoDecoder) decodeNsURI(b []byte) (string, error) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return "", protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num == protoResourceNsURINumber && typ == protowire.BytesType {
+			nsURI, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return "", protowire.ParseError(n)
+			}
+			return nsURI, nil
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return "", protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return "", errors.New("unable to find resource package namespace")
+}
+
+func (d *ProtoDecoder) decodeOneOf(b []byte, oneOf *protoOneOf) (EObject, error) {
+	var eObject EObject
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if c := oneOf.numberToCase[num]; c != nil && typ == protowire.BytesType {
+			content, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			// last case wins
+			var err error
+			if eObject, err = d.decodeMessage(content, c.message); err != nil {
+				return nil, err
+			}
+			b = b[n:]
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return eObject, nil
+}
+
+func (d *ProtoDecoder) decodeMessage(b []byte, message *protoMessage) (EObject, error) {
+	eClass := message.eClass
+	eObject := eClass.GetEPackage().GetEFactoryInstance().Create(eClass)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if num == protoIDNumber && typ == protowire.BytesType {
+			id, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			if objectIDManager := d.resource.GetObjectIDManager(); objectIDManager != nil {
+				if err := objectIDManager.SetID(eObject, id); err != nil {
+					return nil, err
+				}
+			}
+			b = b[n:]
+			continue
+		}
+		field := message.numberToField[num]
+		if field == nil {
+			// unknown field
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		n, err := d.decodeField(b, typ, field, eObject)
+		if err != nil {
+			return nil, err
+		}
+		b = b[n:]
+	}
+	return eObject, nil
+}
+
+func (d *ProtoDecoder) decodeField(b []byte, typ protowire.Type, field *protoField, eObject EObject) (int, error) {
+	switch field.kind {
+	case pfkData:
+		if typ != getProtoWireType(field.scalar) {
+			return 0, d.newInvalidWireTypeError(typ, field)
+		}
+		value, n, err := d.decodeScalar(b, field)
+		if err != nil {
+			return 0, err
+		}
+		eObject.ESet(field.eFeature, value)
+		return n, nil
+	case pfkDataList:
+		l := eObject.EGetResolve(field.eFeature, false).(EList)
+		wireType := getProtoWireType(field.scalar)
+		if typ == protowire.BytesType && wireType != protowire.BytesType {
+			// packed encoding
+			packed, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			values := []any{}
+			for len(packed) > 0 {
+				value, m, err := d.decodeScalar(packed, field)
+				if err != nil {
+					return 0, err
+				}
+				values = append(values, value)
+				packed = packed[m:]
+			}
+			l.AddAll(NewBasicEList(values))
+			return n, nil
+		} else if typ != wireType {
+			return 0, d.newInvalidWireTypeError(typ, field)
+		}
+		value, n, err := d.decodeScalar(b, field)
+		if err != nil {
+			return 0, err
+		}
+		l.Add(value)
+		return n, nil
+	case pfkObject, pfkObjectList:
+		if typ != protowire.BytesType {
+			return 0, d.newInvalidWireTypeError(typ, field)
+		}
+		content, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		var child EObject
+		var err error
+		if field.message != nil {
+			child, err = d.decodeMessage(content, field.message)
+		} else {
+			child, err = d.decodeOneOf(content, field.oneOf)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if child != nil {
+			if field.kind == pfkObject {
+				eObject.ESet(field.eFeature, child)
+			} else {
+				eObject.EGetResolve(field.eFeature, false).(EList).Add(child)
+			}
+		}
+		return n, nil
+	case pfkObjectReference, pfkObjectReferenceList:
+		if typ != protowire.BytesType {
+			return 0, d.newInvalidWireTypeError(typ, field)
+		}
+		uri, n := protowire.ConsumeString(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		if len(uri) > 0 {
+			d.references = append(d.references, protoReference{eObject: eObject, eFeature: field.eFeature, uri: uri})
+		}
+		return n, nil
+	}
+	return 0, d.newInvalidWireTypeError(typ, field)
+}
+
+func (d *ProtoDecoder) newInvalidWireTypeError(typ protowire.Type, field *protoField) error {
+	return fmt.Errorf("invalid wire type '%v' for field '%v'", typ, field.name)
+}
+
+func (d *ProtoDecoder) decodeScalar(b []byte, field *protoField) (any, int, error) {
+	value, n, err := d.decodeWireScalar(b, field)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch field.scalar {
+	case pskEnum, pskData:
+		// already created by the factory
+		return value, n, nil
+	default:
+		return getProtoInstanceValue(field.eDataType, value), n, nil
+	}
+}
+
+// getProtoInstanceValue converts a decoded scalar to the instance type of eDataType.
+// Data types of generated models may have a named type given by their default value
+func getProtoInstanceValue(eDataType EDataType, value any) any {
+	defaultValue := eDataType.GetDefaultValue()
+	if defaultValue == nil {
+		return value
+	}
+	instanceType := reflect.TypeOf(defaultValue)
+	if v := reflect.ValueOf(value); v.Type() != instanceType && v.CanConvert(instanceType) {
+		return v.Convert(instanceType).Interface()
+	}
+	return value
+}
+
+func (d *ProtoDecoder) decodeWireScalar(b []byte, field *protoField) (any, int, error) {
+	switch getProtoWireType(field.scalar) {
+	case protowire.VarintType:
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		switch field.scalar {
+		case pskBool:
+			return protowire.DecodeBool(v), n, nil
+		case pskInt:
+			return int(v), n, nil
+		case pskInt64:
+			return int64(v), n, nil
+		case pskInt32:
+			return int32(v), n, nil
+		case pskInt16:
+			return int16(v), n, nil
+		case pskByte:
+			return byte(v), n, nil
+		default:
+			// enum values are created by the factory to get the type of generated enums
+			value := int(int32(v))
+			eLiteral := field.eDataType.(EEnum).GetEEnumLiteralByValue(value)
+			if eLiteral == nil {
+				return nil, 0, fmt.Errorf("invalid value '%v' for enum '%v'", value, field.eDataType.GetName())
+			}
+			return field.eFactory.CreateFromString(field.eDataType, eLiteral.GetLiteral()), n, nil
+		}
+	case protowire.Fixed64Type:
+		v, n := protowire.ConsumeFixed64(b)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		return math.Float64frombits(v), n, nil
+	case protowire.Fixed32Type:
+		v, n := protowire.ConsumeFixed32(b)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		return math.Float32frombits(v), n, nil
+	default:
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return nil, 0, protowire.ParseError(n)
+		}
+		switch field.scalar {
+		case pskBytes:
+			return append([]byte(nil), v...), n, nil
+		case pskString:
+			return string(v), n, nil
+		default:
+			return field.eFactory.CreateFromString(field.eDataType, string(v)), n, nil
+		}
+	}
+}
+
+func (d *ProtoDecoder) resolveReferences() {
+	for _, reference := range d.references {
+		uri := NewURI(reference.uri)
+		var eTarget EObject
+		if uri.TrimFragment().IsEmpty() {
+			// reference to an object of this resource
+			eTarget = d.resource.GetEObject(uri.Fragment())
+			uri = NewURIBuilder(d.baseURI).SetFragment(uri.Fragment()).URI()
+		} else if d.baseURI != nil {
+			uri = d.baseURI.Resolve(uri)
+		}
+		if eTarget == nil {
+			// proxy to an object of an other resource
+			eClass := reference.eFeature.GetEType().(EClass)
+			eProxy := eClass.GetEPackage().GetEFactoryInstance().Create(eClass).(EObjectInternal)
+			eProxy.ESetProxyURI(uri)
+			eTarget = eProxy
+		}
+		if reference.eFeature.IsMany() {
+			reference.eObject.EGetResolve(reference.eFeature, false).(EList).Add(eTarget)
+		} else {
+			reference.eObject.ESet(reference.eFeature, eTarget)
+		}
+	}
+	d.references = nil
+}
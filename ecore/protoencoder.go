@@ -0,0 +1,256 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type ProtoEncoder struct {
+	resource             EResource
+	w                    io.Writer
+	ePackage             EPackage
+	schema               *ProtoSchema
+	baseURI              *URI
+	isIDAttributeEncoded bool
+}
+
+func NewProtoEncoder(resource EResource, w io.Writer, options map[string]any) *ProtoEncoder {
+	e := &ProtoEncoder{
+		resource: resource,
+		w:        w,
+	}
+	if uri := resource.GetURI(); uri != nil {
+		e.baseURI = uri
+	}
+	if options != nil {
+		e.isIDAttributeEncoded = options[PROTO_OPTION_ID_ATTRIBUTE] == true
+		e.ePackage, _ = options[PROTO_OPTION_PACKAGE].(EPackage)
+	}
+	return e
+}
+
+func (e *ProtoEncoder) EncodeResource() {
+	objects := []EObject{}
+	for it := e.resource.GetContents().Iterator(); it.HasNext(); {
+		objects = append(objects, it.Next().(EObject))
+	}
+	if err := e.encodeResource(objects); err != nil {
+		// add error to resource errors
+		resourcePath := ""
+		if e.resource.GetURI() != nil {
+			resourcePath = e.resource.GetURI().String()
+		}
+		e.resource.GetErrors().Add(NewEDiagnosticImpl(err.Error(), resourcePath, 0, 0))
+	}
+}
+
+func (e *ProtoEncoder) EncodeObject(object EObject) error {
+	return e.encodeResource([]EObject{object})
+}
+
+func (e *ProtoEncoder) encodeResource(objects []EObject) error {
+	if e.schema == nil {
+		ePackage := e.ePackage
+		if ePackage == nil && len(objects) > 0 {
+			ePackage = objects[0].EClass().GetEPackage()
+		}
+		if ePackage == nil {
+			return errors.New("unable to find resource package")
+		}
+		schema, err := NewProtoSchema(ePackage)
+		if err != nil {
+			return err
+		}
+		e.schema = schema
+	}
+
+	b := protowire.AppendTag(nil, protoResourceNsURINumber, protowire.BytesType)
+	b = protowire.AppendString(b, e.schema.ePackage.GetNsURI())
+	for _, eObject := range objects {
+		content, err := e.encodeOneOf(nil, e.schema.contents, eObject)
+		if err != nil {
+			return err
+		}
+		b = protowire.AppendTag(b, protoResourceContentsNumber, protowire.BytesType)
+		b = protowire.AppendBytes(b, content)
+	}
+	_, err := e.w.Write(b)
+	return err
+}
+
+func (e *ProtoEncoder) encodeOneOf(b []byte, oneOf *protoOneOf, eObject EObject) ([]byte, error) {
+	c := oneOf.classToCase[eObject.EClass()]
+	if c == nil {
+		return nil, fmt.Errorf("class '%v' is not a case of '%v'", eObject.EClass().GetName(), oneOf.name)
+	}
+	message, err := e.encodeMessage(nil, c.message, eObject)
+	if err != nil {
+		return nil, err
+	}
+	b = protowire.AppendTag(b, c.number, protowire.BytesType)
+	return protowire.AppendBytes(b, message), nil
+}
+
+func (e *ProtoEncoder) encodeMessage(b []byte, message *protoMessage, eObject EObject) ([]byte, error) {
+	var err error
+	for _, field := range message.fields {
+		if !eObject.EIsSet(field.eFeature) {
+			continue
+		}
+		value := eObject.EGetResolve(field.eFeature, false)
+		switch field.kind {
+		case pfkData:
+			b = protowire.AppendTag(b, field.number, getProtoWireType(field.scalar))
+			if b, err = e.encodeScalar(b, field, value); err != nil {
+				return nil, err
+			}
+		case pfkDataList:
+			l := value.(EList)
+			if wireType := getProtoWireType(field.scalar); wireType == protowire.BytesType {
+				for it := l.Iterator(); it.HasNext(); {
+					b = protowire.AppendTag(b, field.number, wireType)
+					if b, err = e.encodeScalar(b, field, it.Next()); err != nil {
+						return nil, err
+					}
+				}
+			} else {
+				// packed encoding
+				var packed []byte
+				for it := l.Iterator(); it.HasNext(); {
+					if packed, err = e.encodeScalar(packed, field, it.Next()); err != nil {
+						return nil, err
+					}
+				}
+				b = protowire.AppendTag(b, field.number, protowire.BytesType)
+				b = protowire.AppendBytes(b, packed)
+			}
+		case pfkObject:
+			if b, err = e.encodeFieldObject(b, field, value.(EObject)); err != nil {
+				return nil, err
+			}
+		case pfkObjectList:
+			for it := value.(EList).Iterator(); it.HasNext(); {
+				if b, err = e.encodeFieldObject(b, field, it.Next().(EObject)); err != nil {
+					return nil, err
+				}
+			}
+		case pfkObjectReference:
+			b = protowire.AppendTag(b, field.number, protowire.BytesType)
+			b = protowire.AppendString(b, e.getReference(value.(EObject)))
+		case pfkObjectReferenceList:
+			for it := value.(EList).Iterator(); it.HasNext(); {
+				b = protowire.AppendTag(b, field.number, protowire.BytesType)
+				b = protowire.AppendString(b, e.getReference(it.Next().(EObject)))
+			}
+		}
+	}
+	// id attribute
+	if objectIDManager := e.resource.GetObjectIDManager(); e.isIDAttributeEncoded && objectIDManager != nil {
+		if id := objectIDManager.GetID(eObject); id != nil {
+			b = protowire.AppendTag(b, protoIDNumber, protowire.BytesType)
+			b = protowire.AppendString(b, fmt.Sprintf("%v", id))
+		}
+	}
+	return b, nil
+}
+
+func (e *ProtoEncoder) encodeFieldObject(b []byte, field *protoField, eObject EObject) ([]byte, error) {
+	var content []byte
+	var err error
+	if field.message != nil {
+		if eObject.EClass() != field.message.eClass {
+			return nil, fmt.Errorf("class '%v' is not supported by feature '%v'", eObject.EClass().GetName(), field.name)
+		}
+		content, err = e.encodeMessage(nil, field.message, eObject)
+	} else {
+		content, err = e.encodeOneOf(nil, field.oneOf, eObject)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b = protowire.AppendTag(b, field.number, protowire.BytesType)
+	return protowire.AppendBytes(b, content), nil
+}
+
+// encodeScalar appends value to b. Values of generated models may have a named type
+func (e *ProtoEncoder) encodeScalar(b []byte, field *protoField, value any) ([]byte, error) {
+	switch field.scalar {
+	case pskString:
+		return protowire.AppendString(b, reflect.ValueOf(value).String()), nil
+	case pskBytes:
+		return protowire.AppendBytes(b, reflect.ValueOf(value).Bytes()), nil
+	case pskBool:
+		return protowire.AppendVarint(b, protowire.EncodeBool(reflect.ValueOf(value).Bool())), nil
+	case pskInt, pskInt64, pskInt32, pskInt16:
+		return protowire.AppendVarint(b, uint64(reflect.ValueOf(value).Int())), nil
+	case pskByte:
+		return protowire.AppendVarint(b, reflect.ValueOf(value).Uint()), nil
+	case pskFloat64:
+		return protowire.AppendFixed64(b, math.Float64bits(reflect.ValueOf(value).Float())), nil
+	case pskFloat32:
+		return protowire.AppendFixed32(b, math.Float32bits(float32(reflect.ValueOf(value).Float()))), nil
+	case pskEnum:
+		enumValue, err := e.getEnumValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendVarint(b, uint64(enumValue)), nil
+	default:
+		return protowire.AppendString(b, field.eFactory.ConvertToString(field.eDataType, value)), nil
+	}
+}
+
+// getEnumValue returns the value of the enum literal of value, which may have the type of a generated enum
+func (e *ProtoEncoder) getEnumValue(field *protoField, value any) (int, error) {
+	literal := field.eFactory.ConvertToString(field.eDataType, value)
+	if eLiteral := field.eDataType.(EEnum).GetEEnumLiteralByLiteral(literal); eLiteral != nil {
+		return eLiteral.GetValue(), nil
+	}
+	return 0, fmt.Errorf("unknown literal '%v' of enum '%v' for feature '%v'", literal, field.eDataType.GetName(), field.name)
+}
+
+func (e *ProtoEncoder) getReference(eObject EObject) string {
+	eInternal, _ := eObject.(EObjectInternal)
+	if eInternal == nil {
+		return ""
+	}
+	objectURI := eInternal.EProxyURI()
+	if objectURI == nil {
+		eOtherResource := eObject.EResource()
+		if eOtherResource == nil {
+			return ""
+		}
+		objectURI = NewURIBuilder(eOtherResource.GetURI()).SetFragment(eOtherResource.GetURIFragment(eObject)).URI()
+	}
+	if e.baseURI != nil {
+		objectURI = e.baseURI.Relativize(objectURI)
+	}
+	return objectURI.String()
+}
+
+func getProtoWireType(scalar protoScalarKind) protowire.Type {
+	switch scalar {
+	case pskBool, pskInt, pskInt64, pskInt32, pskInt16, pskByte, pskEnum:
+		return protowire.VarintType
+	case pskFloat64:
+		return protowire.Fixed64Type
+	case pskFloat32:
+		return protowire.Fixed32Type
+	default:
+		return protowire.BytesType
+	}
+}
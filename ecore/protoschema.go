@@ -0,0 +1,479 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	protoResourceName        = "EResource"
+	protoResourceContentName = "EResourceContent"
+	protoOneOfPrefix         = "Any"
+	protoUnspecifiedSuffix   = "UNSPECIFIED"
+)
+
+const (
+	protoResourceNsURINumber    protowire.Number = 1
+	protoResourceContentsNumber protowire.Number = 2
+	protoIDNumber               protowire.Number = protowire.MaxValidNumber
+	protoPackageNumberOffset                     = 1000
+)
+
+type protoField struct {
+	name      string
+	number    protowire.Number
+	eFeature  EStructuralFeature
+	kind      protoFeatureKind
+	scalar    protoScalarKind
+	eDataType EDataType
+	eFactory  EFactory
+	message   *protoMessage
+	oneOf     *protoOneOf
+}
+
+type protoMessage struct {
+	name          string
+	eClass        EClass
+	fields        []*protoField
+	numberToField map[protowire.Number]*protoField
+}
+
+type protoCase struct {
+	name    string
+	number  protowire.Number
+	message *protoMessage
+}
+
+type protoOneOf struct {
+	name         string
+	cases        []*protoCase
+	numberToCase map[protowire.Number]*protoCase
+	classToCase  map[EClass]*protoCase
+}
+
+type protoEnumValue struct {
+	name  string
+	value int
+}
+
+type protoEnum struct {
+	name   string
+	values []*protoEnumValue
+}
+
+// ProtoSchema is the protobuf description of the resources of an EPackage.
+// Each concrete EClass is a message whose fields are the class structural features,
+// a containment typed with a class that has sub classes is a oneof wrapper message
+// of the concrete classes and cross references are URI fragment strings.
+type ProtoSchema struct {
+	ePackage    EPackage
+	packages    []EPackage
+	classifiers []EClassifier
+	enums       map[EEnum]*protoEnum
+	messages    map[EClass]*protoMessage
+	oneOfs      map[EClass]*protoOneOf
+	contents    *protoOneOf
+}
+
+func NewProtoSchema(ePackage EPackage) (*ProtoSchema, error) {
+	if ePackage == nil {
+		return nil, errors.New("protobuf schema requires a package")
+	}
+	s := &ProtoSchema{
+		ePackage: ePackage,
+		enums:    map[EEnum]*protoEnum{},
+		messages: map[EClass]*protoMessage{},
+		oneOfs:   map[EClass]*protoOneOf{},
+	}
+	s.collectClassifiers()
+	if err := s.computeMessages(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *ProtoSchema) collectClassifiers() {
+	classifiersSet := map[EClassifier]struct{}{}
+	addClassifier := func(eClassifier EClassifier) {
+		switch eClassifier.(type) {
+		case EClass, EEnum:
+			if _, isCollected := classifiersSet[eClassifier]; !isCollected {
+				classifiersSet[eClassifier] = struct{}{}
+				s.classifiers = append(s.classifiers, eClassifier)
+				if ePackage := eClassifier.GetEPackage(); ePackage != nil && !slices.Contains(s.packages, ePackage) {
+					s.packages = append(s.packages, ePackage)
+				}
+			}
+		}
+	}
+	s.packages = append(s.packages, s.ePackage)
+	for itClassifier := s.ePackage.GetEClassifiers().Iterator(); itClassifier.HasNext(); {
+		addClassifier(itClassifier.Next().(EClassifier))
+	}
+	// classifiers from other packages are appended while iterating
+	for i := 0; i < len(s.classifiers); i++ {
+		eClass, _ := s.classifiers[i].(EClass)
+		if eClass == nil {
+			continue
+		}
+		for itSuper := eClass.GetEAllSuperTypes().Iterator(); itSuper.HasNext(); {
+			addClassifier(itSuper.Next().(EClassifier))
+		}
+		for itFeature := eClass.GetEAllStructuralFeatures().Iterator(); itFeature.HasNext(); {
+			eFeature := itFeature.Next().(EStructuralFeature)
+			if eType := eFeature.GetEType(); eType != nil && getProtoCodecFeatureKind(eFeature) != pfkTransient {
+				addClassifier(eType)
+			}
+		}
+	}
+}
+
+func (s *ProtoSchema) computeMessages() error {
+	// enums & messages
+	for _, eClassifier := range s.classifiers {
+		switch c := eClassifier.(type) {
+		case EClass:
+			if isProtoConcreteClass(c) {
+				s.messages[c] = &protoMessage{
+					name:          s.getName(c),
+					eClass:        c,
+					numberToField: map[protowire.Number]*protoField{},
+				}
+			}
+		case EEnum:
+			s.enums[c] = s.newEnum(c)
+		}
+	}
+	// messages fields
+	for _, eClassifier := range s.classifiers {
+		if eClass, _ := eClassifier.(EClass); eClass != nil {
+			if message := s.messages[eClass]; message != nil {
+				if err := s.computeFields(message); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// resource contents
+	contents, err := s.newOneOf(protoResourceContentName, nil)
+	if err != nil {
+		return err
+	}
+	s.contents = contents
+	return nil
+}
+
+func (s *ProtoSchema) computeFields(message *protoMessage) error {
+	eClass := message.eClass
+	for itFeature := eClass.GetEAllStructuralFeatures().Iterator(); itFeature.HasNext(); {
+		eFeature := itFeature.Next().(EStructuralFeature)
+		kind := getProtoCodecFeatureKind(eFeature)
+		if kind == pfkTransient {
+			continue
+		}
+		number, err := getProtoNumber(eFeature, eClass.GetFeatureID(eFeature)+1)
+		if err != nil {
+			return err
+		}
+		if number == protoIDNumber {
+			return fmt.Errorf("field number '%v' of feature '%v' is reserved for object id", number, eFeature.GetName())
+		}
+		if other := message.numberToField[number]; other != nil {
+			return fmt.Errorf("features '%v' and '%v' of class '%v' have the same field number '%v'", other.name, eFeature.GetName(), eClass.GetName(), number)
+		}
+		field := &protoField{
+			name:     eFeature.GetName(),
+			number:   number,
+			eFeature: eFeature,
+			kind:     kind,
+		}
+		switch kind {
+		case pfkData, pfkDataList:
+			eDataType, _ := eFeature.GetEType().(EDataType)
+			if eDataType == nil {
+				return fmt.Errorf("attribute '%v' of class '%v' has no data type", eFeature.GetName(), eClass.GetName())
+			}
+			field.eDataType = eDataType
+			field.eFactory = eDataType.GetEPackage().GetEFactoryInstance()
+			field.scalar = getProtoCodecScalarKind(eDataType)
+		case pfkObject, pfkObjectList, pfkObjectReference, pfkObjectReferenceList:
+			eReferenceClass, _ := eFeature.GetEType().(EClass)
+			if eReferenceClass == nil {
+				return fmt.Errorf("reference '%v' of class '%v' has no class type", eFeature.GetName(), eClass.GetName())
+			}
+			if kind == pfkObject || kind == pfkObjectList {
+				if concretes := s.getConcreteClasses(eReferenceClass); len(concretes) == 1 && concretes[0] == eReferenceClass {
+					field.message = s.messages[eReferenceClass]
+				} else if field.oneOf, err = s.getOneOf(eReferenceClass); err != nil {
+					return err
+				}
+			}
+		}
+		message.fields = append(message.fields, field)
+		message.numberToField[number] = field
+	}
+	return nil
+}
+
+func (s *ProtoSchema) getOneOf(eClass EClass) (*protoOneOf, error) {
+	if oneOf := s.oneOfs[eClass]; oneOf != nil {
+		return oneOf, nil
+	}
+	oneOf, err := s.newOneOf(protoOneOfPrefix+s.getName(eClass), eClass)
+	if err != nil {
+		return nil, err
+	}
+	s.oneOfs[eClass] = oneOf
+	return oneOf, nil
+}
+
+// newOneOf creates a wrapper of the concrete sub classes of eClass or of all
+// concrete classes of the package if eClass is nil
+func (s *ProtoSchema) newOneOf(name string, eClass EClass) (*protoOneOf, error) {
+	oneOf := &protoOneOf{
+		name:         name,
+		numberToCase: map[protowire.Number]*protoCase{},
+		classToCase:  map[EClass]*protoCase{},
+	}
+	var concretes []EClass
+	if eClass == nil {
+		for _, eClassifier := range s.classifiers {
+			if eConcrete, _ := eClassifier.(EClass); eConcrete != nil && isProtoConcreteClass(eConcrete) {
+				concretes = append(concretes, eConcrete)
+			}
+		}
+	} else {
+		concretes = s.getConcreteClasses(eClass)
+	}
+	for _, eConcrete := range concretes {
+		offset := slices.Index(s.packages, eConcrete.GetEPackage()) * protoPackageNumberOffset
+		number, err := getProtoNumber(eConcrete, offset+eConcrete.GetClassifierID()+1)
+		if err != nil {
+			return nil, err
+		}
+		if other := oneOf.numberToCase[number]; other != nil {
+			return nil, fmt.Errorf("classes '%v' and '%v' have the same case number '%v' in '%v'", other.message.name, eConcrete.GetName(), number, name)
+		}
+		message := s.messages[eConcrete]
+		c := &protoCase{
+			name:    lowerFirst(message.name),
+			number:  number,
+			message: message,
+		}
+		oneOf.cases = append(oneOf.cases, c)
+		oneOf.numberToCase[number] = c
+		oneOf.classToCase[eConcrete] = c
+	}
+	return oneOf, nil
+}
+
+func (s *ProtoSchema) newEnum(eEnum EEnum) *protoEnum {
+	prefix := toUpperSnake(s.getName(eEnum)) + "_"
+	enum := &protoEnum{name: s.getName(eEnum)}
+	hasZero := false
+	for itLiteral := eEnum.GetELiterals().Iterator(); itLiteral.HasNext(); {
+		eLiteral := itLiteral.Next().(EEnumLiteral)
+		value := &protoEnumValue{name: prefix + toUpperSnake(eLiteral.GetName()), value: eLiteral.GetValue()}
+		if value.value == 0 {
+			// proto3 first enum value must be zero
+			hasZero = true
+			enum.values = append([]*protoEnumValue{value}, enum.values...)
+		} else {
+			enum.values = append(enum.values, value)
+		}
+	}
+	if !hasZero {
+		enum.values = append([]*protoEnumValue{{name: prefix + protoUnspecifiedSuffix, value: 0}}, enum.values...)
+	}
+	return enum
+}
+
+func (s *ProtoSchema) getConcreteClasses(eClass EClass) []EClass {
+	concretes := []EClass{}
+	for _, eClassifier := range s.classifiers {
+		if eOther, _ := eClassifier.(EClass); eOther != nil && isProtoConcreteClass(eOther) {
+			if eOther == eClass || eOther.GetEAllSuperTypes().Contains(eClass) {
+				concretes = append(concretes, eOther)
+			}
+		}
+	}
+	return concretes
+}
+
+func (s *ProtoSchema) getName(eClassifier EClassifier) string {
+	if ePackage := eClassifier.GetEPackage(); ePackage != nil && ePackage != s.ePackage {
+		return upperFirst(ePackage.GetNsPrefix()) + "_" + eClassifier.GetName()
+	}
+	return eClassifier.GetName()
+}
+
+func (s *ProtoSchema) getMessage(eClass EClass) *protoMessage {
+	return s.messages[eClass]
+}
+
+// WriteProto writes the schema as a proto3 .proto file
+func (s *ProtoSchema) WriteProto(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %v\n", s.ePackage.GetNsURI())
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %v;\n", s.ePackage.GetNsPrefix())
+	for _, eClassifier := range s.classifiers {
+		switch c := eClassifier.(type) {
+		case EEnum:
+			enum := s.enums[c]
+			fmt.Fprintf(&b, "\nenum %v {\n", enum.name)
+			for _, value := range enum.values {
+				fmt.Fprintf(&b, "  %v = %v;\n", value.name, value.value)
+			}
+			b.WriteString("}\n")
+		case EClass:
+			if message := s.messages[c]; message != nil {
+				s.writeMessage(&b, message)
+			}
+		}
+	}
+	for _, eClassifier := range s.classifiers {
+		if eClass, _ := eClassifier.(EClass); eClass != nil {
+			if oneOf := s.oneOfs[eClass]; oneOf != nil {
+				s.writeOneOf(&b, oneOf)
+			}
+		}
+	}
+	s.writeOneOf(&b, s.contents)
+	fmt.Fprintf(&b, "\nmessage %v {\n", protoResourceName)
+	fmt.Fprintf(&b, "  string nsURI = %v;\n", protoResourceNsURINumber)
+	fmt.Fprintf(&b, "  repeated %v contents = %v;\n", protoResourceContentName, protoResourceContentsNumber)
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (s *ProtoSchema) writeMessage(b *strings.Builder, message *protoMessage) {
+	fmt.Fprintf(b, "\nmessage %v {\n", message.name)
+	for _, field := range message.fields {
+		label := ""
+		switch field.kind {
+		case pfkData, pfkObjectReference:
+			label = "optional "
+		case pfkDataList, pfkObjectList, pfkObjectReferenceList:
+			label = "repeated "
+		}
+		fmt.Fprintf(b, "  %v%v %v = %v;\n", label, s.getFieldTypeName(field), field.name, field.number)
+	}
+	fmt.Fprintf(b, "  optional string eID = %v;\n", protoIDNumber)
+	b.WriteString("}\n")
+}
+
+func (s *ProtoSchema) writeOneOf(b *strings.Builder, oneOf *protoOneOf) {
+	fmt.Fprintf(b, "\nmessage %v {\n", oneOf.name)
+	if len(oneOf.cases) > 0 {
+		b.WriteString("  oneof kind {\n")
+		for _, c := range oneOf.cases {
+			fmt.Fprintf(b, "    %v %v = %v;\n", c.message.name, c.name, c.number)
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+}
+
+func (s *ProtoSchema) getFieldTypeName(field *protoField) string {
+	switch field.kind {
+	case pfkObject, pfkObjectList:
+		if field.message != nil {
+			return field.message.name
+		}
+		return field.oneOf.name
+	case pfkObjectReference, pfkObjectReferenceList:
+		return "string"
+	}
+	switch field.scalar {
+	case pskBytes:
+		return "bytes"
+	case pskBool:
+		return "bool"
+	case pskInt, pskInt64:
+		return "int64"
+	case pskInt32, pskInt16:
+		return "int32"
+	case pskByte:
+		return "uint32"
+	case pskFloat64:
+		return "double"
+	case pskFloat32:
+		return "float"
+	case pskEnum:
+		return s.enums[field.eDataType.(EEnum)].name
+	}
+	return "string"
+}
+
+func isProtoConcreteClass(eClass EClass) bool {
+	return !eClass.IsAbstract() && !eClass.IsInterface()
+}
+
+func getProtoNumber(eElement EModelElement, defaultNumber int) (protowire.Number, error) {
+	number := protowire.Number(defaultNumber)
+	if eAnnotation := eElement.GetEAnnotation(PROTO_ANNOTATION_SOURCE); eAnnotation != nil {
+		if numberStr, _ := eAnnotation.GetDetails().GetValue("number").(string); len(numberStr) > 0 {
+			n, err := strconv.Atoi(numberStr)
+			if err != nil {
+				return 0, fmt.Errorf("invalid protobuf number '%v': %w", numberStr, err)
+			}
+			number = protowire.Number(n)
+		}
+	}
+	if !number.IsValid() {
+		return 0, fmt.Errorf("invalid protobuf number '%v'", number)
+	}
+	return number, nil
+}
+
+func toUpperSnake(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			b.WriteRune('_')
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+func upperFirst(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,91 @@
+package ecore
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestProtoSchema_NoPackage(t *testing.T) {
+	s, err := NewProtoSchema(nil)
+	assert.Nil(t, s)
+	assert.Error(t, err)
+}
+
+func TestProtoSchema_WriteProto(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	s, err := NewProtoSchema(ePackage)
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, s.WriteProto(&b))
+
+	//os.WriteFile("testdata/library.complex.proto", []byte(b.String()), 0644)
+
+	bytes, err := os.ReadFile("testdata/library.complex.proto")
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(b.String(), "\r\n", "\n"))
+}
+
+func TestProtoSchema_Messages(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	s, err := NewProtoSchema(ePackage)
+	require.NoError(t, err)
+
+	eBookClass, _ := ePackage.GetEClassifier("Book").(EClass)
+	require.NotNil(t, eBookClass)
+	message := s.getMessage(eBookClass)
+	require.NotNil(t, message)
+	eTitleFeature := eBookClass.GetEStructuralFeatureFromName("title")
+	field := message.numberToField[protowire.Number(eBookClass.GetFeatureID(eTitleFeature)+1)]
+	require.NotNil(t, field)
+	assert.Equal(t, eTitleFeature, field.eFeature)
+
+	// abstract classes have no message
+	eItemClass, _ := ePackage.GetEClassifier("Item").(EClass)
+	require.NotNil(t, eItemClass)
+	assert.Nil(t, s.getMessage(eItemClass))
+}
+
+func TestProtoSchema_AnnotationNumbers(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	eBookClass, _ := ePackage.GetEClassifier("Book").(EClass)
+	require.NotNil(t, eBookClass)
+	eTitleFeature := eBookClass.GetEStructuralFeatureFromName("title")
+	eAnnotation := GetFactory().CreateEAnnotation()
+	eAnnotation.SetSource(PROTO_ANNOTATION_SOURCE)
+	eAnnotation.GetDetails().Put("number", "100")
+	eTitleFeature.GetEAnnotations().Add(eAnnotation)
+
+	s, err := NewProtoSchema(ePackage)
+	require.NoError(t, err)
+	field := s.getMessage(eBookClass).numberToField[100]
+	require.NotNil(t, field)
+	assert.Equal(t, eTitleFeature, field.eFeature)
+
+	// duplicate field number
+	eAnnotation.GetDetails().Put("number", "1")
+	_, err = NewProtoSchema(ePackage)
+	assert.Error(t, err)
+
+	// invalid field number
+	eAnnotation.GetDetails().Put("number", "invalid")
+	_, err = NewProtoSchema(ePackage)
+	assert.Error(t, err)
+}
+
+func TestToUpperSnake(t *testing.T) {
+	assert.Equal(t, "BOOK_CATEGORY", toUpperSnake("BookCategory"))
+	assert.Equal(t, "SCIENCE_FICTION", toUpperSnake("ScienceFiction"))
+	assert.Equal(t, "A_B", toUpperSnake("a-b"))
+}
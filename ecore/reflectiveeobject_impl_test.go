@@ -205,9 +205,10 @@ func TestReflectiveEObjectImpl_GetAttribute_Many(t *testing.T) {
 	mockClass.EXPECT().GetFeatureCount().Return(2).Once()
 	mockClass.EXPECT().GetEStructuralFeature(0).Return(mockAttribute).Once()
 	mockAttribute.EXPECT().IsMany().Return(true).Once()
+	mockAttribute.EXPECT().IsDerived().Return(false).Once()
 	mockAttribute.EXPECT().GetFeatureID().Return(2).Once()
 	mockAttribute.EXPECT().IsUnique().Return(true).Once()
-	mockAttribute.EXPECT().GetEType().Return(nil).Once()
+	mockAttribute.EXPECT().GetEType().Return(nil).Twice()
 	val := o.EGetFromID(0, false)
 	assert.NotNil(t, val)
 	l, _ := val.(EList)
@@ -226,11 +227,12 @@ func TestReflectiveEObjectImpl_GetReference_Many(t *testing.T) {
 	mockClass.EXPECT().GetFeatureCount().Return(2).Once()
 	mockClass.EXPECT().GetEStructuralFeature(0).Return(mockReference).Once()
 	mockReference.EXPECT().IsMany().Return(true).Once()
+	mockReference.EXPECT().IsDerived().Return(false).Once()
 	mockReference.EXPECT().GetEType().Return(nil).Once()
 	mockReference.EXPECT().GetEOpposite().Return(nil).Twice()
 	mockReference.EXPECT().IsContainment().Return(false).Once()
 	mockReference.EXPECT().GetFeatureID().Return(0).Once()
-	mockReference.EXPECT().EIsProxy().Return(false).Once()
+	mockReference.EXPECT().IsResolveProxies().Return(false).Once()
 	mockReference.EXPECT().IsUnsettable().Return(false).Once()
 	val := o.EGetFromID(0, false)
 	assert.NotNil(t, val)
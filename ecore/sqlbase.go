@@ -123,6 +123,15 @@ type sqlBase struct {
 	connPool         *sqlitex.Pool
 	connPoolProvider func() (*sqlitex.Pool, error)
 	connPoolClose    func(conn *sqlitex.Pool) error
+	ctx              context.Context
+}
+
+// getContext returns the context of the decoding or encoding
+func (s *sqlBase) getContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
 }
 
 func (s *sqlBase) setLogger(logger *zap.Logger) {
@@ -199,7 +208,8 @@ func (s *sqlBase) executeSqlite(fn executeQueryFn, cmd string, opts *sqlitex.Exe
 		}
 
 		// execute query
-		conn, err := s.connPool.Take(context.Background())
+		// connection is interrupted when context is done
+		conn, err := s.connPool.Take(s.getContext())
 		if err != nil {
 			reject(err)
 			return
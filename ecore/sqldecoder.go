@@ -173,6 +173,17 @@ func (d *sqlDecoder) decodeSchema(schemaOptions []sqlSchemaOption) error {
 		d.isObjectID = d.objectIDName != "objectID" && d.objectIDManager != nil
 	}
 
+	// object id high-water mark
+	if propertyHighWaterMark := properties["objectIDHighWaterMark"]; len(propertyHighWaterMark) > 0 {
+		if idManager, _ := d.objectIDManager.(EObjectIDHighWaterMark); idManager != nil {
+			highWaterMark, err := strconv.ParseInt(propertyHighWaterMark, 10, 64)
+			if err != nil {
+				return err
+			}
+			idManager.SetHighWaterMark(highWaterMark)
+		}
+	}
+
 	// container id
 	if propertyContainerID, isPropertyContainerID := properties["containerID"]; isPropertyContainerID {
 		d.isContainerID, err = strconv.ParseBool(propertyContainerID)
@@ -803,6 +814,12 @@ func newSQLDecoder(connectionPoolProvider func() (*sqlitex.Pool, error), connect
 }
 
 func (d *SQLDecoder) DecodeResource() {
+	d.DecodeResourceContext(context.Background())
+}
+
+// DecodeResourceContext decodes the resource, interrupting sqlite queries when ctx is done
+func (d *SQLDecoder) DecodeResourceContext(ctx context.Context) {
+	d.ctx = ctx
 	var err error
 	if d.connPool, err = d.connPoolProvider(); err != nil {
 		d.addError(err)
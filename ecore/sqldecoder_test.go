@@ -36,6 +36,32 @@ func TestSQLDecoder_DecodeResource(t *testing.T) {
 	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
 }
 
+func TestSQLDecoder_DecodeResourceContext_Canceled(t *testing.T) {
+	// load package
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	// create resource & resourceset
+	uri := NewURI("testdata/library.complex.sqlite")
+	eResource := NewEResourceImpl()
+	eResource.SetURI(uri)
+	eResourceSet := NewEResourceSetImpl()
+	eResourceSet.GetResources().Add(eResource)
+	eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+
+	r, err := os.Open(uri.String())
+	require.NoError(t, err)
+	defer r.Close()
+
+	// queries are interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sqlDecoder := NewSQLReaderDecoder(r, eResource, nil)
+	sqlDecoder.DecodeResourceContext(ctx)
+	require.False(t, eResource.GetErrors().Empty())
+	require.True(t, eResource.GetContents().Empty())
+}
+
 func TestSQLDecoder_DecodeResource_Memory(t *testing.T) {
 	// load package
 	ePackage := loadPackage("library.complex.ecore")
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -242,14 +244,18 @@ func (e *sqlEncoder) encodeProperties() (err error) {
 	properties := map[string]string{}
 	if len(e.objectIDName) > 0 {
 		properties["objectID"] = e.objectIDName
+		if highWaterMark, isSequential := getIDHighWaterMark(e.objectIDManager); isSequential {
+			properties["objectIDHighWaterMark"] = strconv.FormatInt(highWaterMark, 10)
+		}
 	}
 	if e.isContainerID {
 		properties["containerID"] = "true"
 	}
 
 	query := e.schema.propertiesTable.insertOrReplaceQuery()
-	for k, v := range properties {
-		if previous[k] != v {
+	// properties are written in a stable order
+	for _, k := range slices.Sorted(maps.Keys(properties)) {
+		if v := properties[k]; previous[k] != v {
 			if err := e.executeQuery(query, &sqlitex.ExecOptions{
 				Args: []any{k, v},
 			}); err != nil {
@@ -864,6 +870,12 @@ func newSQLEncoder(connectionPoolProvider func() (*sqlitex.Pool, error), connect
 }
 
 func (e *SQLEncoder) EncodeResource() {
+	e.EncodeResourceContext(context.Background())
+}
+
+// EncodeResourceContext encodes the resource, interrupting sqlite queries when ctx is done
+func (e *SQLEncoder) EncodeResourceContext(ctx context.Context) {
+	e.ctx = ctx
 	var err error
 	if e.connPool, err = e.connPoolProvider(); err != nil {
 		e.addError(err)
@@ -245,7 +245,7 @@ func TestSQLEncoder_SimpleWithULIDs(t *testing.T) {
 		ids = append(ids, ulid.MustParse(u))
 	}
 	idManager := NewULIDManager()
-	idManager.newID = func() ulid.ULID {
+	idManager.newID = func(EObject) ulid.ULID {
 		require.True(t, len(ids) > 0)
 		id := ids[0]
 		ids = ids[1:]
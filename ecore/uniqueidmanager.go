@@ -1,9 +1,13 @@
 package ecore
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
@@ -14,14 +18,22 @@ type UniqueIDManager[ID comparable] struct {
 	detachedToID map[uintptr]ID
 	objectToID   map[EObject]ID
 	idToObject   map[ID]EObject
-	newID        func() ID
+	newID        func(EObject) ID
 	isValidID    func(ID) bool
 	getID        func(any) (ID, error)
 	setID        func(ID)
+	nextID       *int64
+	pending      map[EObject]int
+	pendingCount int
 	mutex        sync.RWMutex
 }
 
 func NewUniqueIDManager[ID comparable](newID func() ID, isValidID func(ID) bool, getID func(any) (ID, error), setID func(ID)) *UniqueIDManager[ID] {
+	return newUniqueIDManager(func(EObject) ID { return newID() }, isValidID, getID, setID)
+}
+
+// newUniqueIDManager returns a manager whose new ids may depend on the registered object
+func newUniqueIDManager[ID comparable](newID func(EObject) ID, isValidID func(ID) bool, getID func(any) (ID, error), setID func(ID)) *UniqueIDManager[ID] {
 	return &UniqueIDManager[ID]{
 		detachedToID: map[uintptr]ID{},
 		objectToID:   map[EObject]ID{},
@@ -38,10 +50,41 @@ func (m *UniqueIDManager[ID]) Clear() {
 	m.detachedToID = map[uintptr]ID{}
 	m.objectToID = map[EObject]ID{}
 	m.idToObject = map[ID]EObject{}
+	if m.pending != nil {
+		m.pending = map[EObject]int{}
+	}
 	m.mutex.Unlock()
 }
 
+// newPendingUniqueIDManager returns a manager whose ids are created when they are first needed rather than
+// on registration, so that they depend on the object contents at that time
+func newPendingUniqueIDManager[ID comparable](newID func(EObject) ID, isValidID func(ID) bool, getID func(any) (ID, error), setID func(ID)) *UniqueIDManager[ID] {
+	m := newUniqueIDManager(newID, isValidID, getID, setID)
+	m.pending = map[EObject]int{}
+	return m
+}
+
+// createPendingIDs creates the ids of registered objects without id in their registration order.
+// Manager must be locked
+func (m *UniqueIDManager[ID]) createPendingIDs() {
+	if len(m.pending) == 0 {
+		return
+	}
+	objects := make([]EObject, 0, len(m.pending))
+	for eObject := range m.pending {
+		objects = append(objects, eObject)
+	}
+	slices.SortFunc(objects, func(a, b EObject) int {
+		return m.pending[a] - m.pending[b]
+	})
+	clear(m.pending)
+	for _, eObject := range objects {
+		m.setObjectID(eObject, m.newID(eObject))
+	}
+}
+
 func (m *UniqueIDManager[ID]) setObjectID(eObject EObject, newID ID) {
+	delete(m.pending, eObject)
 	if oldID, isOldID := m.objectToID[eObject]; isOldID {
 		delete(m.idToObject, oldID)
 	}
@@ -66,16 +109,22 @@ func (m *UniqueIDManager[ID]) Register(eObject EObject) {
 		newID, isOldID := m.detachedToID[ptr]
 		if isOldID {
 			delete(m.detachedToID, ptr)
+			m.setObjectID(eObject, newID)
+		} else if m.pending != nil {
+			if _, isPending := m.pending[eObject]; !isPending {
+				m.pending[eObject] = m.pendingCount
+				m.pendingCount++
+			}
 		} else {
-			newID = m.newID()
+			m.setObjectID(eObject, m.newID(eObject))
 		}
-		m.setObjectID(eObject, newID)
 	}
 	m.mutex.Unlock()
 }
 
 func (m *UniqueIDManager[ID]) UnRegister(eObject EObject) {
 	m.mutex.Lock()
+	delete(m.pending, eObject)
 	if id, isID := m.objectToID[eObject]; isID {
 		delete(m.idToObject, id)
 		delete(m.objectToID, eObject)
@@ -96,8 +145,14 @@ func (m *UniqueIDManager[ID]) SetID(eObject EObject, id any) error {
 }
 
 func (m *UniqueIDManager[ID]) GetID(eObject EObject) any {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	if m.pending != nil {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.createPendingIDs()
+	} else {
+		m.mutex.RLock()
+		defer m.mutex.RUnlock()
+	}
 	if id, isPresent := m.objectToID[eObject]; isPresent {
 		return id
 	}
@@ -105,8 +160,14 @@ func (m *UniqueIDManager[ID]) GetID(eObject EObject) any {
 }
 
 func (m *UniqueIDManager[ID]) GetEObject(id any) EObject {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+	if m.pending != nil {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.createPendingIDs()
+	} else {
+		m.mutex.RLock()
+		defer m.mutex.RUnlock()
+	}
 	if v, err := m.getID(id); err == nil {
 		return m.idToObject[v]
 	}
@@ -179,11 +240,33 @@ func NewULIDManager() *ULIDManager {
 	)
 }
 
+// GetHighWaterMark returns the next generated id of a manager generating sequential ids and -1 otherwise
+func (m *UniqueIDManager[ID]) GetHighWaterMark() int64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.nextID == nil {
+		return -1
+	}
+	return *m.nextID
+}
+
+// SetHighWaterMark raises the high-water mark of a manager generating sequential ids to mark, it is never lowered
+func (m *UniqueIDManager[ID]) SetHighWaterMark(mark int64) {
+	m.mutex.Lock()
+	if m.nextID != nil {
+		*m.nextID = max(mark, *m.nextID)
+	}
+	m.mutex.Unlock()
+}
+
+// IncrementalIDManager generates sequential int64 ids.
+// Its high-water mark is the next generated id : it is saved and restored by codecs
+// so that the ids of deleted objects are never reused.
 type IncrementalIDManager = UniqueIDManager[int64]
 
 func NewIncrementalIDManager() *IncrementalIDManager {
 	currentID := int64(0)
-	return NewUniqueIDManager(
+	m := NewUniqueIDManager(
 		func() int64 {
 			id := currentID
 			currentID++
@@ -225,4 +308,66 @@ func NewIncrementalIDManager() *IncrementalIDManager {
 			currentID = max(id+1, currentID)
 		},
 	)
+	m.nextID = &currentID
+	return m
+}
+
+// ContentHashIDManager derives the id of an object from its containment path,
+// where contained objects are identified by their key attributes if any.
+// Ids are created when they are first needed, usually when the resource is saved, so that
+// key attributes set after adding objects are taken into account.
+// Building the same model twice gives the same ids.
+type ContentHashIDManager = UniqueIDManager[string]
+
+func NewContentHashIDManager() *ContentHashIDManager {
+	var m *ContentHashIDManager
+	m = newPendingUniqueIDManager(
+		func(eObject EObject) string {
+			path := getContentPath(eObject)
+			id := hashContentPath(path)
+			// objects with the same path are distinguished by their registration order
+			for i := 1; m.idToObject[id] != nil; i++ {
+				id = hashContentPath(path + "#" + strconv.Itoa(i))
+			}
+			return id
+		},
+		func(id string) bool {
+			return len(id) > 0
+		},
+		func(id any) (string, error) {
+			switch v := id.(type) {
+			case nil:
+				return "", nil
+			case string:
+				return v, nil
+			}
+			return "", fmt.Errorf("id:'%v' not supported by ContentHashIDManager", id)
+		},
+		func(id string) {
+		},
+	)
+	return m
+}
+
+// getContentPath returns the path of eObject from its root : the index of the root in its resource
+// followed by the fragment segments of its containers
+func getContentPath(eObject EObject) string {
+	segments := []string{}
+	eObjectInternal := eObject.(EObjectInternal)
+	for eContainer, _ := eObjectInternal.EInternalContainer().(EObjectInternal); eContainer != nil && eObjectInternal.EInternalResource() == nil; eContainer, _ = eObjectInternal.EInternalContainer().(EObjectInternal) {
+		segments = append(segments, eContainer.EURIFragmentSegment(eObjectInternal.EContainingFeature(), eObjectInternal))
+		eObjectInternal = eContainer
+	}
+	root := ""
+	if eResource := eObjectInternal.EInternalResource(); eResource != nil {
+		root = strconv.Itoa(eResource.GetContents().IndexOf(eObjectInternal))
+	}
+	segments = append(segments, root)
+	slices.Reverse(segments)
+	return "/" + strings.Join(segments, "/")
+}
+
+func hashContentPath(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:16])
 }
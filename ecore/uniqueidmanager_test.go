@@ -1,12 +1,15 @@
 package ecore
 
 import (
+	"bytes"
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/oklog/ulid/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIncrementalIDManagerRegister(t *testing.T) {
@@ -90,6 +93,36 @@ func TestIncrementalIDManagerClear(t *testing.T) {
 	assert.Equal(t, nil, m.GetID(mockObject))
 }
 
+func TestIncrementalIDManagerHighWaterMark(t *testing.T) {
+	m := NewIncrementalIDManager()
+	assert.Equal(t, int64(0), m.GetHighWaterMark())
+
+	mockObject := NewMockEObject(t)
+	m.Register(mockObject)
+	assert.Equal(t, int64(1), m.GetHighWaterMark())
+
+	// high-water mark is used for next ids
+	m.SetHighWaterMark(10)
+	mockOther := NewMockEObject(t)
+	m.Register(mockOther)
+	assert.Equal(t, int64(10), m.GetID(mockOther))
+	assert.Equal(t, int64(11), m.GetHighWaterMark())
+
+	// high-water mark is never lowered
+	m.SetHighWaterMark(3)
+	assert.Equal(t, int64(11), m.GetHighWaterMark())
+}
+
+func TestUUIDManagerHighWaterMark(t *testing.T) {
+	// ids are not sequential
+	m := NewUUIDManager()
+	assert.Equal(t, int64(-1), m.GetHighWaterMark())
+	m.SetHighWaterMark(10)
+	assert.Equal(t, int64(-1), m.GetHighWaterMark())
+	_, isSequential := getIDHighWaterMark(m)
+	assert.False(t, isSequential)
+}
+
 func TestUUIDManagerRegister(t *testing.T) {
 	m := NewUUIDManager()
 	mockObject := NewMockEObject(t)
@@ -148,3 +181,157 @@ func TestULIDManagerRegister(t *testing.T) {
 	assert.Nil(t, m.GetEObject(""))
 	assert.Nil(t, m.GetEObject(3))
 }
+
+func TestIncrementalIDManagerHighWaterMarkCodecs(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	eBooksReference, _ := eLibraryClass.GetEStructuralFeatureFromName("books").(EReference)
+	require.NotNil(t, eBooksReference)
+
+	dir := t.TempDir()
+	for _, test := range []struct {
+		ext     string
+		options map[string]any
+	}{
+		{"xml", map[string]any{XML_OPTION_ID_ATTRIBUTE_NAME: "id"}},
+		{"bin", map[string]any{BINARY_OPTION_ID_ATTRIBUTE: true}},
+		{"sqlite", map[string]any{SQL_OPTION_OBJECT_ID: "objectID"}},
+	} {
+		t.Run(test.ext, func(t *testing.T) {
+			// load resource and remove the object with the highest id
+			eResourceSet := NewEResourceSetImpl()
+			eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+			eResource := eResourceSet.CreateResource(NewURI("testdata/library.simple.ids.xml"))
+			eResource.SetObjectIDManager(NewIncrementalIDManager())
+			eResource.LoadWithOptions(map[string]any{XML_OPTION_ID_ATTRIBUTE_NAME: "id"})
+			require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+			eBook := eResource.GetObjectIDManager().GetEObject(4)
+			require.NotNil(t, eBook)
+			Remove(eBook)
+
+			// save
+			uri := NewURI(filepath.ToSlash(filepath.Join(dir, "library."+test.ext)))
+			eResource.SetURI(uri)
+			eResource.SaveWithOptions(test.options)
+			require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+			// load and check that the id of the removed object is not reused
+			idManager := NewIncrementalIDManager()
+			eNewResource := NewEResourceSetImpl().CreateResource(uri)
+			eNewResource.GetResourceSet().GetPackageRegistry().RegisterPackage(ePackage)
+			eNewResource.SetObjectIDManager(idManager)
+			eNewResource.LoadWithOptions(test.options)
+			require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+			assert.Equal(t, int64(5), idManager.GetHighWaterMark())
+
+			eLibrary, _ := eNewResource.GetContents().Get(0).(EObject)
+			require.NotNil(t, eLibrary)
+			eNewBook := ePackage.GetEFactoryInstance().Create(eBooksReference.GetEReferenceType())
+			eLibrary.EGet(eBooksReference).(EList).Add(eNewBook)
+			assert.Equal(t, int64(5), idManager.GetID(eNewBook))
+		})
+	}
+}
+
+func TestBinaryDecoderInitialVersion(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+	eResourceSet := NewEResourceSetImpl()
+	eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eResource := eResourceSet.CreateResource(NewURI("testdata/library.simple.ids.xml"))
+	eResource.SetObjectIDManager(NewIncrementalIDManager())
+	eResource.LoadWithOptions(map[string]any{XML_OPTION_ID_ATTRIBUTE_NAME: "id"})
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// encode without high-water mark
+	var buffer bytes.Buffer
+	options := map[string]any{BINARY_OPTION_ID_ATTRIBUTE: true}
+	NewBinaryEncoderWithVersion(eResource, &buffer, options, binaryVersionInitial).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// decode
+	idManager := NewIncrementalIDManager()
+	eNewResource := NewEResourceImpl()
+	eNewResource.SetObjectIDManager(idManager)
+	eResourceSet.GetResources().Add(eNewResource)
+	NewBinaryDecoder(eNewResource, &buffer, options).DecodeResource()
+	require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+	require.Equal(t, 1, eNewResource.GetContents().Size())
+	assert.Equal(t, int64(5), idManager.GetHighWaterMark())
+}
+
+func TestContentHashIDManager(t *testing.T) {
+	m := loadKeysModel(t)
+	newLibrary := func(editions ...int) (EObject, map[int]EObject) {
+		eResource := NewEResourceImpl()
+		eResource.SetObjectIDManager(NewContentHashIDManager())
+		eLibrary := m.ePackage.GetEFactoryInstance().Create(m.libraryClass)
+		eBooks := map[int]EObject{}
+		for _, edition := range editions {
+			eBook := m.newBook("Dune", edition)
+			eLibrary.EGet(m.books).(EList).Add(eBook)
+			eBooks[edition] = eBook
+		}
+		eResource.GetContents().Add(eLibrary)
+		return eLibrary, eBooks
+	}
+	getID := func(eObject EObject) any {
+		return eObject.EResource().GetObjectIDManager().GetID(eObject)
+	}
+
+	eLibrary, eBooks := newLibrary(1, 2)
+	ids := map[any]struct{}{}
+	for _, eObject := range []EObject{eLibrary, eBooks[1], eBooks[2]} {
+		id, _ := getID(eObject).(string)
+		assert.Len(t, id, 32)
+		assert.Equal(t, eObject, eObject.EResource().GetObjectIDManager().GetEObject(id))
+		ids[id] = struct{}{}
+	}
+	assert.Len(t, ids, 3)
+
+	// ids are the same for the same contents
+	eOtherLibrary, eOtherBooks := newLibrary(1, 2)
+	assert.Equal(t, getID(eLibrary), getID(eOtherLibrary))
+	assert.Equal(t, getID(eBooks[1]), getID(eOtherBooks[1]))
+	assert.Equal(t, getID(eBooks[2]), getID(eOtherBooks[2]))
+
+	// ids of books depend on their keys, not on their position
+	_, eReversedBooks := newLibrary(2, 1)
+	assert.Equal(t, getID(eBooks[1]), getID(eReversedBooks[1]))
+	assert.Equal(t, getID(eBooks[2]), getID(eReversedBooks[2]))
+
+	// objects with the same path have different ids
+	eLibrary, _ = newLibrary()
+	eDune := m.newBook("Dune", 1)
+	eDuplicate := m.newBook("Dune", 1)
+	eLibrary.EGet(m.books).(EList).AddAll(NewImmutableEList([]any{eDune, eDuplicate}))
+	assert.Equal(t, getID(eBooks[1]), getID(eDune))
+	assert.NotEqual(t, getID(eDune), getID(eDuplicate))
+
+	// ids of books whose keys are set once they are added
+	eLibrary, _ = newLibrary()
+	eLateBooks := map[int]EObject{}
+	for _, edition := range []int{1, 2} {
+		eBook := m.ePackage.GetEFactoryInstance().Create(m.bookClass)
+		eLibrary.EGet(m.books).(EList).Add(eBook)
+		eBook.ESet(m.title, "Dune")
+		eBook.ESet(m.edition, edition)
+		eLateBooks[edition] = eBook
+	}
+	assert.Equal(t, getID(eBooks[1]), getID(eLateBooks[1]))
+	assert.Equal(t, getID(eBooks[2]), getID(eLateBooks[2]))
+	assert.Equal(t, eLateBooks[2], eLibrary.EResource().GetObjectIDManager().GetEObject(getID(eBooks[2])))
+}
+
+func TestContentHashIDManagerSetID(t *testing.T) {
+	m := NewContentHashIDManager()
+	mockObject := NewMockEObject(t)
+	assert.Nil(t, m.SetID(mockObject, "id"))
+	assert.Equal(t, "id", m.GetID(mockObject))
+	assert.Equal(t, mockObject, m.GetEObject("id"))
+	assert.Equal(t, errors.New("id:'1' not supported by ContentHashIDManager"), m.SetID(mockObject, 1))
+	assert.Nil(t, m.SetID(mockObject, nil))
+	assert.Nil(t, m.GetID(mockObject))
+}
@@ -202,6 +202,43 @@ func NewURIFromComponents(scheme string, username string, password string, host
 	return uri
 }
 
+// unescapeFragment decodes escaped characters of fragment except in the keys of key based segments
+// ( @feature[key='value'] ) : their values are decoded when they are parsed so that they may contain '/'
+func unescapeFragment(fragment string) string {
+	if !strings.Contains(fragment, "%") {
+		return fragment
+	}
+	unescape := func(s string) string {
+		if unescaped, err := url.PathUnescape(s); err == nil {
+			return unescaped
+		}
+		return s
+	}
+	var s strings.Builder
+	start := 0
+	inKeys, inValue := false, false
+	for i := 0; i < len(fragment); i++ {
+		switch c := fragment[i]; {
+		case !inKeys && c == '[':
+			s.WriteString(unescape(fragment[start:i]))
+			start = i
+			inKeys = true
+		case inKeys && c == '\'':
+			inValue = !inValue
+		case inKeys && !inValue && c == ']':
+			s.WriteString(fragment[start : i+1])
+			start = i + 1
+			inKeys = false
+		}
+	}
+	if inKeys {
+		s.WriteString(fragment[start:])
+	} else {
+		s.WriteString(unescape(fragment[start:]))
+	}
+	return s.String()
+}
+
 func NewURI(rawURI string) *URI {
 	uri, _ := ParseURI(rawURI)
 	return uri
@@ -214,7 +251,10 @@ func ParseURI(rawURI string) (*URI, error) {
 		if url, err := url.Parse(rawURI); err != nil {
 			return nil, err
 		} else {
-			uri = &URI{scheme: url.Scheme, path: url.Path, fragment: url.Fragment, query: url.RawQuery, rawURI: rawURI}
+			uri = &URI{scheme: url.Scheme, path: url.Path, query: url.RawQuery, rawURI: rawURI}
+			if i := strings.IndexByte(rawURI, '#'); i >= 0 {
+				uri.fragment = unescapeFragment(rawURI[i+1:])
+			}
 			if url.User != nil {
 				uri.username = url.User.Username()
 				uri.password, _ = url.User.Password()
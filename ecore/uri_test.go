@@ -43,6 +43,18 @@ func TestURI_ParseURI(t *testing.T) {
 		assert.NotNil(t, uri)
 		assert.Equal(t, "file:///path#fragment", uri.String())
 	}
+	{
+		uri, err := ParseURI("file:///path#//@books[title='a%2Fb%20c']")
+		assert.Nil(t, err)
+		assert.NotNil(t, uri)
+		assert.Equal(t, "//@books[title='a%2Fb%20c']", uri.Fragment())
+	}
+	{
+		// only keys of key based segments are kept escaped
+		assert.Equal(t, "foo bar", NewURI("a.xml#foo%20bar").Fragment())
+		assert.Equal(t, "//@books[title='a%2Fb]c']/@an author", NewURI("a.xml#//@books[title='a%2Fb]c']/@an%20author").Fragment())
+		assert.Equal(t, "//@books[title='a%2Fb", NewURI("a.xml#//@books[title='a%2Fb").Fragment())
+	}
 }
 
 func TestURI_IsAbsolute(t *testing.T) {
@@ -144,3 +144,17 @@ func TestXMIDecoderLibraryComplex(t *testing.T) {
 	assert.Equal(t, "EStringToStringMapEntry", eType.GetName())
 	assert.False(t, eType.EIsProxy())
 }
+
+func TestXMIDecoderEcorePackage(t *testing.T) {
+	resourceSet := NewEResourceSetImpl()
+	eResource := resourceSet.GetResource(NewURI("testdata/library.simple.package.xmi"), true)
+	require.NotNil(t, eResource)
+	require.True(t, eResource.IsLoaded())
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	eLibrary := eResource.GetContents().Get(0).(EObject)
+	eLibraryClass := eLibrary.EClass()
+	assert.Equal(t, "Library", eLibraryClass.GetName())
+	assert.Equal(t, "Location", eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("location")))
+	assert.NotNil(t, resourceSet.GetPackageRegistry().GetPackage("http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0"))
+}
@@ -79,3 +79,17 @@ func BenchmarkXMIEncoderLibraryNoRoot(b *testing.B) {
 		resource = nil
 	}
 }
+
+func TestXMIEncoderRecordUnknownFeature(t *testing.T) {
+	// load/save
+	xmiProcessor := NewXMIProcessor()
+	resource := xmiProcessor.LoadWithOptions(NewURI("testdata/library.simple.unknown.ecore"), map[string]any{XML_OPTION_RECORD_UNKNOWN_FEATURE: true})
+	require.NotNil(t, resource)
+	require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	require.Len(t, resource.GetEObjectToExtensionMap(), 2)
+	result := xmiProcessor.SaveToString(resource, nil)
+	// check
+	bytes, err := os.ReadFile("testdata/library.simple.unknown.ecore")
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(result, "\r\n", "\n"))
+}
@@ -0,0 +1,136 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+var utf16BEBOM = []byte{0xFE, 0xFF}
+
+var utf16LEBOM = []byte{0xFF, 0xFE}
+
+// '<?' without byte order mark
+var utf16BEDeclaration = []byte{0x00, 0x3C, 0x00, 0x3F}
+
+var utf16LEDeclaration = []byte{0x3C, 0x00, 0x3F, 0x00}
+
+// isUTF8 returns true if label is an UTF-8 encoding label
+func isUTF8(label string) bool {
+	return strings.EqualFold(label, "utf-8") || strings.EqualFold(label, "utf8")
+}
+
+// getXMLEncoding returns the encoding named label in IANA registry
+func getXMLEncoding(label string) (encoding.Encoding, error) {
+	e, err := ianaindex.IANA.Encoding(label)
+	if err != nil || e == nil {
+		return nil, fmt.Errorf("unsupported encoding '%s'", label)
+	}
+	return e, nil
+}
+
+// newXMLCharsetReader returns a reader converting input encoded with label to UTF-8.
+// Label is looked up in WHATWG encodings ( utf8, cp1252 ... ) then in IANA registry
+func newXMLCharsetReader(label string, input io.Reader) (io.Reader, error) {
+	if r, err := charset.NewReaderLabel(label, input); err == nil {
+		return r, nil
+	}
+	e, err := getXMLEncoding(label)
+	if err != nil {
+		return nil, err
+	}
+	return transform.NewReader(input, e.NewDecoder()), nil
+}
+
+// newXMLUnicodeReader detects an UTF-16 or UTF-8 byte order mark at the start of r.
+// It returns an UTF-8 reader and true if r has been converted or false if its encoding
+// must be read from the xml declaration
+func newXMLUnicodeReader(r io.Reader) (io.Reader, bool) {
+	br := bufio.NewReader(r)
+	start, _ := br.Peek(4)
+	switch {
+	case bytes.HasPrefix(start, utf8BOM):
+		_, _ = br.Discard(len(utf8BOM))
+		return br, true
+	case bytes.HasPrefix(start, utf16BEBOM), bytes.HasPrefix(start, utf16LEBOM):
+		return transform.NewReader(br, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()), true
+	case bytes.Equal(start, utf16BEDeclaration):
+		return transform.NewReader(br, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()), true
+	case bytes.Equal(start, utf16LEDeclaration):
+		return transform.NewReader(br, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()), true
+	}
+	return br, false
+}
+
+// newXMLTokenDecoder returns a xml decoder of r converting its content to UTF-8
+// according to its byte order mark or its declared encoding
+func newXMLTokenDecoder(r io.Reader) *xml.Decoder {
+	r, isUnicode := newXMLUnicodeReader(r)
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if isUnicode {
+			// byte order mark takes precedence over the declared encoding
+			return input, nil
+		}
+		return newXMLCharsetReader(label, input)
+	}
+	return decoder
+}
+
+type xmlCharsetWriter struct {
+	w        io.WriteCloser
+	encoding encoding.Encoding
+	label    string
+}
+
+// newXMLCharsetWriter returns a writer converting UTF-8 content to label encoding.
+// Writer must be closed to flush its content
+func newXMLCharsetWriter(label string, w io.Writer) (io.WriteCloser, error) {
+	e, err := getXMLEncoding(label)
+	if err != nil {
+		return nil, err
+	}
+	return &xmlCharsetWriter{
+		w:        transform.NewWriter(w, e.NewEncoder()),
+		encoding: e,
+		label:    label,
+	}, nil
+}
+
+func (w *xmlCharsetWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		// find the character which cannot be represented
+		for i := 0; i < len(p); {
+			r, size := utf8.DecodeRune(p[i:])
+			if _, err := w.encoding.NewEncoder().Bytes(p[i : i+size]); err != nil {
+				return n, fmt.Errorf("character '%c' cannot be represented in encoding '%s'", r, w.label)
+			}
+			i += size
+		}
+	}
+	return n, err
+}
+
+func (w *xmlCharsetWriter) Close() error {
+	return w.w.Close()
+}
@@ -18,6 +18,13 @@ const (
 	XML_OPTION_DEFERRED_ROOT_ATTACHMENT      = "DEFERRED_ROOT_ATTACHMENT"      // if true , defer id ref resolution
 	XML_OPTION_ID_ATTRIBUTE_NAME             = "ID_ATTRIBUTE_NAME"             // value of the id attribute
 	XML_OPTION_ROOT_OBJECTS                  = "ROOT_OBJECTS"                  // list of root objects to save
+	XML_OPTION_RECORD_UNKNOWN_FEATURE        = "RECORD_UNKNOWN_FEATURE"        // if true , record unknown features in resource extension map
+	XML_OPTION_INDENT                        = "INDENT"                        // indentation width ( int )
+	XML_OPTION_LINE_WIDTH                    = "LINE_WIDTH"                    // attributes are wrapped on next line beyond this width ( int )
+	XML_OPTION_SORT_ATTRIBUTES               = "SORT_ATTRIBUTES"               // if true , attributes are sorted by name after type and id
+	XML_OPTION_DECLARE_XML                   = "DECLARE_XML"                   // if false , xml declaration is not saved
+	XML_OPTION_CANONICAL                     = "CANONICAL"                     // if true , save a canonical document : sorted attributes and deterministic prefixes
+	XML_OPTION_ENCODING                      = "ENCODING"                      // encoding of saved document ( string )
 )
 
 type XMLCodec struct {
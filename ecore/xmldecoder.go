@@ -13,9 +13,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
-
-	"golang.org/x/net/html/charset"
 )
 
 const (
@@ -23,9 +22,13 @@ const (
 	typeAttrib                      = "type"
 	schemaLocationAttrib            = "schemaLocation"
 	noNamespaceSchemaLocationAttrib = "noNamespaceSchemaLocation"
+	packageAttrib                   = "package"
+	highWaterMarkAttrib             = "highWaterMark"
 	xsiURI                          = "http://www.w3.org/2001/XMLSchema-instance"
 	xsiNS                           = "xsi"
 	xmlNS                           = "xmlns"
+	xmlURI                          = "http://www.w3.org/XML/1998/namespace"
+	xmlPrefix                       = "xml"
 )
 
 type xmlLoadFeatureKind int
@@ -50,6 +53,12 @@ type xmlReference struct {
 	pos     int
 }
 
+// xmlFeaturePosition is the position of the last known element of an object
+type xmlFeaturePosition struct {
+	feature EStructuralFeature
+	index   int
+}
+
 type xmlDecoderInternal interface {
 	getXSIType() string
 	handleAttributes(object EObject)
@@ -77,24 +86,41 @@ type XMLDecoder struct {
 	objects                []EObject
 	deferred               []EObject
 	elements               []string
+	unknownElements        []*XMLExtensionElement
+	unknownExtension       *XMLExtension
+	lastFeatures           map[EObject]xmlFeaturePosition
+	mixedFeatures          map[EClass]EAttribute
+	packageDescriptors     map[string]EPackageDescriptor
 	isSuppressDocumentRoot bool
 	isResolveDeferred      bool
+	isRecordUnknownFeature bool
 }
 
 func NewXMLDecoder(resource EResource, r io.Reader, options map[string]any) *XMLDecoder {
 	l := new(XMLDecoder)
 	l.interfaces = l
 	l.resource = resource
-	l.decoder = xml.NewDecoder(r)
-	l.decoder.CharsetReader = charset.NewReaderLabel
+	l.decoder = newXMLTokenDecoder(r)
 	l.namespaces = newXMLNamespaces()
 	l.prefixesToURI = make(map[string]string)
 	l.spacesToFactories = make(map[string]EFactory)
-	l.notFeatures = append(l.notFeatures, xml.Name{Space: xsiURI, Local: typeAttrib}, xml.Name{Space: xsiURI, Local: schemaLocationAttrib}, xml.Name{Space: xsiURI, Local: noNamespaceSchemaLocationAttrib})
+	l.mixedFeatures = make(map[EClass]EAttribute)
+	l.packageDescriptors = make(map[string]EPackageDescriptor)
+	l.notFeatures = append(l.notFeatures,
+		xml.Name{Space: xsiURI, Local: typeAttrib},
+		xml.Name{Space: xsiURI, Local: schemaLocationAttrib},
+		xml.Name{Space: xsiURI, Local: noNamespaceSchemaLocationAttrib},
+		xml.Name{Space: NS_URI, Local: packageAttrib},
+		xml.Name{Space: NS_URI, Local: highWaterMarkAttrib},
+	)
 	if options != nil {
 		l.idAttributeName, _ = options[XML_OPTION_ID_ATTRIBUTE_NAME].(string)
 		l.isResolveDeferred = options[XML_OPTION_DEFERRED_REFERENCE_RESOLUTION] == true
 		l.isSuppressDocumentRoot = options[XML_OPTION_SUPPRESS_DOCUMENT_ROOT] == true
+		if options[XML_OPTION_RECORD_UNKNOWN_FEATURE] == true {
+			l.isRecordUnknownFeature = true
+			l.lastFeatures = map[EObject]xmlFeaturePosition{}
+		}
 		if extendedMetaData := options[XML_OPTION_EXTENDED_META_DATA]; extendedMetaData != nil {
 			l.extendedMetaData = extendedMetaData.(*ExtendedMetaData)
 		}
@@ -172,8 +198,13 @@ func (l *XMLDecoder) startElement(e xml.StartElement) {
 	l.setAttributes(e.Attr)
 	l.namespaces.pushContext()
 	l.handlePrefixMapping()
+	if len(l.unknownElements) > 0 {
+		l.startUnknownElement(e.Name.Space, e.Name.Local)
+		return
+	}
 	if len(l.objects) == 0 {
 		l.handleSchemaLocation()
+		l.handleHighWaterMark()
 	}
 	l.processElement(e.Name.Space, e.Name.Local)
 }
@@ -183,6 +214,13 @@ func (l *XMLDecoder) endElement(_ xml.EndElement) {
 		l.elements = l.elements[:len(l.elements)-1]
 	}
 
+	// end of an unknown element
+	if len(l.unknownElements) > 0 {
+		l.unknownElements = l.unknownElements[:len(l.unknownElements)-1]
+		l.popNamespacesContext()
+		return
+	}
+
 	// remove last object
 	var eRoot EObject
 	var eObject EObject
@@ -221,11 +259,14 @@ func (l *XMLDecoder) endElement(_ xml.EndElement) {
 		l.recordSchemaLocations(eRoot)
 	}
 
+	l.popNamespacesContext()
+}
+
+func (l *XMLDecoder) popNamespacesContext() {
 	context := l.namespaces.popContext()
 	for _, p := range context {
 		delete(l.spacesToFactories, p[1].(string))
 	}
-
 }
 
 func (l *XMLDecoder) setAttributes(attributes []xml.Attr) []xml.Attr {
@@ -259,12 +300,60 @@ func (l *XMLDecoder) handleSchemaLocation() {
 	if len(xsiNoNamespaceSchemaLocation) > 0 {
 		l.handleXSINoNamespaceSchemaLocation(xsiNoNamespaceSchemaLocation)
 	}
+
+	ecorePackage := l.getAttributeValue(NS_URI, packageAttrib)
+	if len(ecorePackage) > 0 {
+		l.handleEcorePackage(ecorePackage)
+	}
+}
+
+// handleHighWaterMark restores the high-water mark of the id manager saved as an ecore:highWaterMark attribute
+func (l *XMLDecoder) handleHighWaterMark() {
+	highWaterMark := l.getAttributeValue(NS_URI, highWaterMarkAttrib)
+	if len(highWaterMark) == 0 {
+		return
+	}
+	if idManager, _ := l.resource.GetObjectIDManager().(EObjectIDHighWaterMark); idManager != nil {
+		if mark, err := strconv.ParseInt(highWaterMark, 10, 64); err != nil {
+			l.error(NewEDiagnosticImpl(err.Error(), l.resource.GetURI().String(), int(l.decoder.InputOffset()), 0))
+		} else {
+			idManager.SetHighWaterMark(mark)
+		}
+	}
 }
 
 func (l *XMLDecoder) handleXSISchemaLocation(loc string) {
+	l.handlePackageLocations(loc)
 }
 
 func (l *XMLDecoder) handleXSINoNamespaceSchemaLocation(loc string) {
+	l.handlePackageLocation("", loc)
+}
+
+// handleEcorePackage handles an ecore:package hint which is, like xsi:schemaLocation,
+// a list of namespace and location pairs
+func (l *XMLDecoder) handleEcorePackage(loc string) {
+	l.handlePackageLocations(loc)
+}
+
+func (l *XMLDecoder) handlePackageLocations(loc string) {
+	fields := strings.Fields(loc)
+	for i := 0; i+1 < len(fields); i += 2 {
+		l.handlePackageLocation(fields[i], fields[i+1])
+	}
+}
+
+// handlePackageLocation registers a descriptor loading the package of space from location.
+// The package is only loaded if space is not found in the package registry.
+func (l *XMLDecoder) handlePackageLocation(space, location string) {
+	uri := NewURI(location)
+	if !strings.HasSuffix(uri.Path(), ".ecore") {
+		return
+	}
+	if resourceURI := l.resource.GetURI(); resourceURI != nil {
+		uri = resourceURI.Resolve(uri)
+	}
+	l.packageDescriptors[space] = NewEPackageURIDescriptor(uri, l.resource.GetResourceSet())
 }
 
 func (l *XMLDecoder) handlePrefixMapping() {
@@ -421,6 +510,9 @@ func (l *XMLDecoder) handleFeature(space string, local string) {
 	}
 	if eObject != nil {
 		eFeature := l.getFeature(eObject, space, local)
+		if eFeature == nil {
+			eFeature = l.getGlobalFeature(eObject, space, local, false)
+		}
 		if eFeature != nil {
 			if featureKind := l.getLoadFeatureKind(eFeature); featureKind == xlfkSingle || featureKind == xlfkMany {
 				l.textBuilder = &strings.Builder{}
@@ -429,11 +521,25 @@ func (l *XMLDecoder) handleFeature(space string, local string) {
 			} else {
 				xsiType := l.interfaces.(xmlDecoderInternal).getXSIType()
 				if len(xsiType) > 0 {
+					if l.isRecordUnknownFeature && l.getFactoryForQName(xsiType) == nil {
+						l.recordUnknownElement(eObject, space, local)
+						return
+					}
 					l.createObjectFromTypeName(eObject, xsiType, eFeature)
 				} else {
 					l.createObjectFromFeatureType(eObject, eFeature)
 				}
 			}
+			if l.lastFeatures != nil {
+				position := l.lastFeatures[eObject]
+				if position.feature != eFeature {
+					position = xmlFeaturePosition{feature: eFeature}
+				}
+				position.index++
+				l.lastFeatures[eObject] = position
+			}
+		} else if l.isRecordUnknownFeature {
+			l.recordUnknownElement(eObject, space, local)
 		} else {
 			l.handleUnknownFeature(local)
 		}
@@ -448,6 +554,10 @@ func (l *XMLDecoder) setFeatureValue(eObject EObject,
 	eFeature EStructuralFeature,
 	value any,
 	position int) {
+	if featureMap := l.getFeatureMap(eObject, eFeature); featureMap != nil {
+		l.setFeatureMapValue(featureMap, eFeature, value)
+		return
+	}
 	kind := l.getLoadFeatureKind(eFeature)
 	switch kind {
 	case xlfkSingle:
@@ -529,7 +639,7 @@ func (l *XMLDecoder) handleAttributes(eObject EObject) {
 			} else if name == href {
 				l.handleProxy(eObject, value)
 			} else if name != xmlNS && uri != xmlNS && l.isUserAttribute(attr.Name) {
-				l.setAttributeValue(eObject, name, value)
+				l.setAttributeValue(eObject, attr)
 			}
 		}
 	}
@@ -552,6 +662,9 @@ func (l *XMLDecoder) getFactoryForSpace(space string) EFactory {
 			packageRegistry = l.resource.GetResourceSet().GetPackageRegistry()
 		}
 		factory = packageRegistry.GetFactory(space)
+		if factory == nil {
+			factory = l.getFactoryFromDescriptor(space, packageRegistry)
+		}
 		if factory != nil {
 			l.spacesToFactories[space] = factory
 		}
@@ -559,7 +672,22 @@ func (l *XMLDecoder) getFactoryForSpace(space string) EFactory {
 	return factory
 }
 
-func (l *XMLDecoder) setAttributeValue(eObject EObject, qname string, value string) {
+func (l *XMLDecoder) getFactoryFromDescriptor(space string, packageRegistry EPackageRegistry) EFactory {
+	if descriptor := l.packageDescriptors[space]; descriptor != nil {
+		if ePackage := descriptor.GetEPackage(); ePackage != nil {
+			if l.resource.GetResourceSet() != nil {
+				// loaded packages are shared by the resources of the resource set
+				packageRegistry.PutPackage(space, ePackage)
+			}
+			return ePackage.GetEFactoryInstance()
+		}
+	}
+	return nil
+}
+
+func (l *XMLDecoder) setAttributeValue(eObject EObject, attr xml.Attr) {
+	qname := attr.Name.Local
+	value := attr.Value
 	local := qname
 	prefix := ""
 	if index := strings.Index(qname, ":"); index > 0 {
@@ -568,6 +696,9 @@ func (l *XMLDecoder) setAttributeValue(eObject EObject, qname string, value stri
 	}
 	space, _ := l.namespaces.getURI(prefix)
 	eFeature := l.getFeature(eObject, space, local)
+	if eFeature == nil {
+		eFeature = l.getGlobalFeature(eObject, attr.Name.Space, local, true)
+	}
 	if eFeature != nil {
 		kind := l.getLoadFeatureKind(eFeature)
 		if kind == xlfkSingle || kind == xlfkMany {
@@ -575,6 +706,9 @@ func (l *XMLDecoder) setAttributeValue(eObject EObject, qname string, value stri
 		} else {
 			l.setValueFromId(eObject, eFeature.(EReference), value)
 		}
+	} else if l.isRecordUnknownFeature {
+		extension := l.getExtension(eObject)
+		extension.Attributes = append(extension.Attributes, XMLExtensionAttribute{Name: l.getExtensionQName(extension, attr.Name), Value: value})
 	} else {
 		l.handleUnknownFeature(local)
 	}
@@ -772,6 +906,69 @@ func (l *XMLDecoder) getFeature(eObject EObject, _, name string) EStructuralFeat
 	return eFeature
 }
 
+// getGlobalFeature returns the global element or attribute of space which is accepted by the class of eObject
+func (l *XMLDecoder) getGlobalFeature(eObject EObject, space, name string, isAttribute bool) EStructuralFeature {
+	if l.extendedMetaData == nil || space == "" {
+		return nil
+	}
+	eFactory := l.getFactoryForSpace(space)
+	if eFactory == nil {
+		return nil
+	}
+	var eFeature EStructuralFeature
+	if isAttribute {
+		eFeature = l.extendedMetaData.GetAttribute(eFactory.GetEPackage(), name)
+	} else {
+		eFeature = l.extendedMetaData.GetElement(eFactory.GetEPackage(), name)
+	}
+	if eFeature != nil && l.extendedMetaData.GetClassAffiliation(eObject.EClass(), eFeature) != nil {
+		return eFeature
+	}
+	return nil
+}
+
+// getFeatureMap returns the feature map of eObject storing the values of eFeature
+func (l *XMLDecoder) getFeatureMap(eObject EObject, eFeature EStructuralFeature) EFeatureMap {
+	if l.extendedMetaData == nil {
+		return nil
+	}
+	if eClass := eObject.EClass(); eFeature.IsDerived() || eClass.GetFeatureID(eFeature) < 0 {
+		if featureMapFeature := l.extendedMetaData.GetFeatureMap(eClass, eFeature); featureMapFeature != nil {
+			featureMap, _ := eObject.EGetResolve(featureMapFeature, false).(EFeatureMap)
+			return featureMap
+		}
+	}
+	return nil
+}
+
+func (l *XMLDecoder) setFeatureMapValue(featureMap EFeatureMap, eFeature EStructuralFeature, value any) {
+	if eDataType, _ := eFeature.GetEType().(EDataType); eDataType != nil {
+		if str, isString := value.(string); isString {
+			value = eDataType.GetEPackage().GetEFactoryInstance().CreateFromString(eDataType, str)
+		}
+	}
+	featureMap.AddValue(eFeature, value)
+}
+
+// getMixedFeatureMap returns the feature map storing the text of the current object or nil if it has no mixed content
+func (l *XMLDecoder) getMixedFeatureMap() EFeatureMap {
+	if l.extendedMetaData == nil || len(l.elements) == 0 || len(l.types) == 0 || l.types[len(l.types)-1] != load_object_type {
+		return nil
+	}
+	eObject := l.objects[len(l.objects)-1]
+	eClass := eObject.EClass()
+	mixedFeature, exists := l.mixedFeatures[eClass]
+	if !exists {
+		mixedFeature = l.extendedMetaData.GetMixedFeature(eClass)
+		l.mixedFeatures[eClass] = mixedFeature
+	}
+	if mixedFeature == nil {
+		return nil
+	}
+	featureMap, _ := eObject.EGetResolve(mixedFeature, false).(EFeatureMap)
+	return featureMap
+}
+
 func (l *XMLDecoder) getType(ePackage EPackage, name string) EClassifier {
 	if l.extendedMetaData != nil {
 		return l.extendedMetaData.GetType(ePackage, name)
@@ -779,6 +976,76 @@ func (l *XMLDecoder) getType(ePackage EPackage, name string) EClassifier {
 	return ePackage.GetEClassifier(name)
 }
 
+// getFactoryForQName returns the factory of the package of a qualified type name
+func (l *XMLDecoder) getFactoryForQName(qname string) EFactory {
+	prefix := ""
+	if index := strings.Index(qname, ":"); index > 0 {
+		prefix = qname[:index]
+	}
+	space, _ := l.namespaces.getURI(prefix)
+	return l.getFactoryForSpace(space)
+}
+
+func (l *XMLDecoder) getExtension(eObject EObject) *XMLExtension {
+	extensions := l.resource.GetEObjectToExtensionMap()
+	extension := extensions[eObject]
+	if extension == nil {
+		extension = newXMLExtension()
+		extensions[eObject] = extension
+	}
+	return extension
+}
+
+// getExtensionQName returns the qualified name of name, recording its namespace in extension
+func (l *XMLDecoder) getExtensionQName(extension *XMLExtension, name xml.Name) string {
+	switch name.Space {
+	case "":
+		return name.Local
+	case xmlNS:
+		return xmlNS + ":" + name.Local
+	case xmlURI:
+		return xmlPrefix + ":" + name.Local
+	}
+	prefix, ok := l.namespaces.getPrefix(name.Space)
+	if !ok {
+		// undeclared prefix
+		return name.Space + ":" + name.Local
+	}
+	extension.Namespaces[prefix] = name.Space
+	if len(prefix) == 0 {
+		return name.Local
+	}
+	return prefix + ":" + name.Local
+}
+
+func (l *XMLDecoder) newExtensionElement(space string, local string) *XMLExtensionElement {
+	extension := l.unknownExtension
+	element := &XMLExtensionElement{Name: l.getExtensionQName(extension, xml.Name{Space: space, Local: local})}
+	for _, attr := range l.attributes {
+		element.Attributes = append(element.Attributes, XMLExtensionAttribute{Name: l.getExtensionQName(extension, attr.Name), Value: attr.Value})
+	}
+	return element
+}
+
+// recordUnknownElement starts recording of an unknown element of eObject
+func (l *XMLDecoder) recordUnknownElement(eObject EObject, space string, local string) {
+	l.unknownExtension = l.getExtension(eObject)
+	element := l.newExtensionElement(space, local)
+	position := l.lastFeatures[eObject]
+	element.Feature = position.feature
+	element.Index = position.index
+	l.unknownExtension.Elements = append(l.unknownExtension.Elements, element)
+	l.unknownElements = append(l.unknownElements, element)
+}
+
+// startUnknownElement records a child element of the current unknown element
+func (l *XMLDecoder) startUnknownElement(space string, local string) {
+	parent := l.unknownElements[len(l.unknownElements)-1]
+	element := l.newExtensionElement(space, local)
+	parent.Contents = append(parent.Contents, element)
+	l.unknownElements = append(l.unknownElements, element)
+}
+
 func (l *XMLDecoder) handleUnknownFeature(name string) {
 	l.error(NewEDiagnosticImpl("Feature "+name+" not found", l.resource.GetURI().String(), int(l.decoder.InputOffset()), 0))
 }
@@ -792,12 +1059,35 @@ func (l *XMLDecoder) error(diagnostic EDiagnostic) {
 }
 
 func (l *XMLDecoder) text(data string) {
+	if len(l.unknownElements) > 0 {
+		element := l.unknownElements[len(l.unknownElements)-1]
+		if last := len(element.Contents) - 1; last >= 0 {
+			if text, isText := element.Contents[last].(string); isText {
+				element.Contents[last] = text + data
+				return
+			}
+		}
+		element.Contents = append(element.Contents, data)
+		return
+	}
 	if l.textBuilder != nil {
 		l.textBuilder.WriteString(data)
+	} else if mixed := l.getMixedFeatureMap(); mixed != nil {
+		textFeature := GetXMLTypeTextFeature()
+		if last := mixed.Size() - 1; last >= 0 && mixed.GetEStructuralFeature(last) == textFeature {
+			mixed.SetValue(last, mixed.GetValue(last).(string)+data)
+		} else {
+			mixed.AddValue(textFeature, data)
+		}
 	}
 }
 
 func (l *XMLDecoder) comment(comment string) {
+	if len(l.unknownElements) == 0 && l.textBuilder == nil {
+		if mixed := l.getMixedFeatureMap(); mixed != nil {
+			mixed.AddValue(GetXMLTypeCommentFeature(), comment)
+		}
+	}
 }
 
 func (l *XMLDecoder) processingInstruction(procInst xml.ProcInst) {
@@ -398,3 +398,259 @@ func BenchmarkXMLDecoderLibraryComplexBig(b *testing.B) {
 		require.True(b, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
 	}
 }
+
+func TestXMLDecoderRecordUnknownFeature(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	// without option, unknown features are errors
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.simple.unknown.xml"))
+	require.NotNil(t, eResource)
+	assert.False(t, eResource.GetErrors().Empty())
+
+	// with option, unknown features are recorded
+	eResource = xmlProcessor.LoadWithOptions(NewURI("testdata/library.simple.unknown.xml"), map[string]any{XML_OPTION_RECORD_UNKNOWN_FEATURE: true})
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	eBooksReference := eLibraryClass.GetEStructuralFeatureFromName("books")
+	require.NotNil(t, eBooksReference)
+	eLibrary, _ := eResource.GetContents().Get(0).(EObject)
+	require.NotNil(t, eLibrary)
+	eBooks, _ := eLibrary.EGet(eBooksReference).(EList)
+	require.NotNil(t, eBooks)
+	assert.Equal(t, 2, eBooks.Size())
+
+	extensions := eResource.GetEObjectToExtensionMap()
+	require.Len(t, extensions, 2)
+	eLibraryExtension := extensions[eLibrary]
+	require.NotNil(t, eLibraryExtension)
+	assert.Equal(t, []XMLExtensionAttribute{{Name: "ext:rating", Value: "5"}, {Name: "address", Value: "Main Street"}}, eLibraryExtension.Attributes)
+	assert.Equal(t, map[string]string{"ext": "http://www.masagroup.net/ext", "other": "http://www.masagroup.net/other"}, eLibraryExtension.Namespaces)
+	require.Len(t, eLibraryExtension.Elements, 3)
+	assert.Equal(t, &XMLExtensionElement{Name: "ext:notes", Attributes: []XMLExtensionAttribute{{Name: "lang", Value: "en"}}, Contents: []any{"First & only"}}, eLibraryExtension.Elements[0])
+	assert.Equal(t, &XMLExtensionElement{Name: "extra", Attributes: []XMLExtensionAttribute{{Name: "kind", Value: "new"}}, Feature: eBooksReference, Index: 1}, eLibraryExtension.Elements[1])
+	assert.Equal(t, &XMLExtensionElement{Name: "other:data", Attributes: []XMLExtensionAttribute{{Name: "xmlns:other", Value: "http://www.masagroup.net/other"}}, Feature: eBooksReference, Index: 2}, eLibraryExtension.Elements[2])
+
+	eBookExtension := extensions[eBooks.Get(0).(EObject)]
+	require.NotNil(t, eBookExtension)
+	assert.Equal(t, []XMLExtensionAttribute{{Name: "pages", Value: "10"}}, eBookExtension.Attributes)
+	require.Len(t, eBookExtension.Elements, 1)
+	assert.Equal(t, &XMLExtensionElement{
+		Name: "ext:review",
+		Contents: []any{
+			&XMLExtensionElement{Name: "ext:author", Attributes: []XMLExtensionAttribute{{Name: "name", Value: "A"}}},
+			"Great",
+			&XMLExtensionElement{Name: "ext:score", Contents: []any{"4"}},
+		},
+	}, eBookExtension.Elements[0])
+}
+
+func assertLibraryEncoding(t *testing.T, ePackage EPackage, eResource EResource) {
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+	require.Equal(t, 1, eResource.GetContents().Size())
+
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	eBookClass, _ := ePackage.GetEClassifier("Book").(EClass)
+	require.NotNil(t, eBookClass)
+
+	eLibrary, _ := eResource.GetContents().Get(0).(EObject)
+	require.NotNil(t, eLibrary)
+	assert.Equal(t, "Propriétaire", eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("owner")))
+	assert.Equal(t, "Orléans", eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("location")))
+	eBooks, _ := eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("books")).(EList)
+	require.NotNil(t, eBooks)
+	require.Equal(t, 2, eBooks.Size())
+	eBookName := eBookClass.GetEStructuralFeatureFromName("name")
+	assert.Equal(t, "Crépuscule", eBooks.Get(0).(EObject).EGet(eBookName))
+	assert.Equal(t, "Garçon", eBooks.Get(1).(EObject).EGet(eBookName))
+}
+
+func TestXMLDecoderEncodingLatin1(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.simple.latin1.xml"))
+	assertLibraryEncoding(t, ePackage, eResource)
+}
+
+func TestXMLDecoderEncodingLabels(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	// labels which are not IANA names
+	content, err := os.ReadFile("testdata/library.simple.latin1.xml")
+	require.Nil(t, err)
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	for _, label := range []string{"cp1252", "iso8859-1", "latin1"} {
+		labelContent := bytes.Replace(content, []byte(`encoding="ISO-8859-1"`), []byte(`encoding="`+label+`"`), 1)
+		eResource := xmlProcessor.LoadWithReader(bytes.NewReader(labelContent), nil)
+		assertLibraryEncoding(t, ePackage, eResource)
+	}
+
+	utf8Content := `<?xml version="1.0" encoding="utf8"?><lib:Library xmlns:lib="http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0" owner="Propriétaire"/>`
+	eResource := xmlProcessor.LoadWithReader(bytes.NewReader([]byte(utf8Content)), nil)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	assert.Equal(t, "Propriétaire", eResource.GetContents().Get(0).(EObject).EGet(eLibraryClass.GetEStructuralFeatureFromName("owner")))
+}
+
+func TestXMLDecoderEncodingUTF16(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	// big endian with byte order mark
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.simple.utf16.xml"))
+	assertLibraryEncoding(t, ePackage, eResource)
+
+	// little endian without byte order mark
+	content, err := os.ReadFile("testdata/library.simple.utf16.xml")
+	require.Nil(t, err)
+	content = content[2:]
+	for i := 0; i < len(content); i += 2 {
+		content[i], content[i+1] = content[i+1], content[i]
+	}
+	eResource = xmlProcessor.LoadWithReader(bytes.NewReader(content), nil)
+	assertLibraryEncoding(t, ePackage, eResource)
+}
+
+func TestXMLDecoderEncodingUnsupported(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	content := `<?xml version="1.0" encoding="unknown"?><Library xmlns="http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0"/>`
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.LoadWithReader(bytes.NewReader([]byte(content)), nil)
+	require.NotNil(t, eResource)
+	require.False(t, eResource.GetErrors().Empty())
+	assert.Contains(t, eResource.GetErrors().Get(0).(EDiagnostic).GetMessage(), "unsupported encoding 'unknown'")
+}
+
+func TestXMLDecoderFeatureMap(t *testing.T) {
+	m := loadFeatureMapModel(t)
+	extPackage := loadPackage("featuremap.extension.ecore")
+	require.NotNil(t, extPackage)
+	extDocumentRoot := extPackage.GetEClassifier("DocumentRoot").(EClass)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{m.ePackage, extPackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/featuremap.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	documentRoot, _ := eResource.GetContents().Get(0).(EObject)
+	require.NotNil(t, documentRoot)
+	document, _ := documentRoot.EGet(documentRoot.EClass().GetEStructuralFeatureFromName("document")).(EObject)
+	require.NotNil(t, document)
+
+	// mixed content
+	paragraphs := document.EGet(m.paragraphs).(EList)
+	require.Equal(t, 2, paragraphs.Size())
+	mixed := paragraphs.Get(0).(EObject).EGet(m.paragraphMixed).(EFeatureMap)
+	textFeature := GetXMLTypeTextFeature()
+	expected := []EFeatureMapEntry{
+		NewEFeatureMapEntry(textFeature, "Hello "),
+		NewEFeatureMapEntry(m.paragraphBold, "bold"),
+		NewEFeatureMapEntry(textFeature, " & "),
+		NewEFeatureMapEntry(m.paragraphItalic, "italic"),
+		NewEFeatureMapEntry(textFeature, " world"),
+		NewEFeatureMapEntry(GetXMLTypeCommentFeature(), " note "),
+	}
+	require.Equal(t, len(expected), mixed.Size())
+	for i, entry := range expected {
+		assert.Equal(t, entry.GetEStructuralFeature(), mixed.GetEStructuralFeature(i))
+		assert.Equal(t, entry.GetValue(), mixed.GetValue(i))
+	}
+	assert.Equal(t, []any{"bold"}, paragraphs.Get(0).(EObject).EGet(m.paragraphBold).(EList).ToArray())
+
+	// substitution group
+	shapes := document.EGet(m.shape).(EList)
+	require.Equal(t, 3, shapes.Size())
+	names := []any{}
+	for it := shapes.Iterator(); it.HasNext(); {
+		names = append(names, it.Next().(EObject).EGet(m.shapeName))
+	}
+	assert.Equal(t, []any{"c1", "s1", "c2"}, names)
+	shapeGroup := document.EGet(m.shapeGroup).(EFeatureMap)
+	assert.Equal(t, m.circleElement, shapeGroup.GetEStructuralFeature(0))
+	assert.Equal(t, m.squareElement, shapeGroup.GetEStructuralFeature(1))
+
+	// wildcards
+	anyElements := document.EGet(m.documentAny).(EFeatureMap)
+	require.Equal(t, 1, anyElements.Size())
+	assert.Equal(t, extDocumentRoot.GetEStructuralFeatureFromName("tag"), anyElements.GetEStructuralFeature(0))
+	assert.Equal(t, "red", anyElements.GetValue(0))
+	anyAttribute := document.EGet(m.documentAnyAttrs).(EFeatureMap)
+	require.Equal(t, 1, anyAttribute.Size())
+	assert.Equal(t, extDocumentRoot.GetEStructuralFeatureFromName("lang"), anyAttribute.GetEStructuralFeature(0))
+	assert.Equal(t, "en", anyAttribute.GetValue(0))
+}
+
+func TestXMLDecoderSchemaLocation(t *testing.T) {
+	resourceSet := NewEResourceSetImpl()
+	eResource := resourceSet.GetResource(NewURI("testdata/library.simple.schemalocation.xml"), true)
+	require.NotNil(t, eResource)
+	require.True(t, eResource.IsLoaded())
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// package is loaded through the resource set and registered in its registry
+	ePackage := resourceSet.GetPackageRegistry().GetPackage("http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0")
+	require.NotNil(t, ePackage)
+	assert.Equal(t, resourceSet, ePackage.EResource().GetResourceSet())
+	assert.Nil(t, GetPackageRegistry().GetPackage("http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0"))
+
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	eLibrary := eResource.GetContents().Get(0).(EObject)
+	assert.Equal(t, eLibraryClass, eLibrary.EClass())
+	assert.Equal(t, "Owner", eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("owner")))
+	assert.Equal(t, 2, eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("books")).(EList).Size())
+}
+
+func TestXMLDecoderSchemaLocationNoResourceSet(t *testing.T) {
+	eResource := NewEResourceImpl()
+	eResource.SetURI(NewURI("testdata/library.simple.schemalocation.xml"))
+	eResource.Load()
+	require.True(t, eResource.IsLoaded())
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+	eLibrary := eResource.GetContents().Get(0).(EObject)
+	assert.Equal(t, "Library", eLibrary.EClass().GetName())
+}
+
+func TestXMLDecoderContainmentProxy(t *testing.T) {
+	m := loadComponentsModel(t)
+	resourceSet := NewEResourceSetImpl()
+	resourceSet.GetPackageRegistry().RegisterPackage(m.ePackage)
+	eResource := resourceSet.GetResource(NewURI("testdata/components.system.xml"), true)
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+	system := eResource.GetContents().Get(0).(EObject)
+
+	// contained objects of other resources are proxies until they are accessed
+	components := system.EGet(m.components).(EObjectList)
+	proxy := components.GetUnResolvedList().Get(1).(EObject)
+	assert.True(t, proxy.EIsProxy())
+	assert.Equal(t, 1, resourceSet.GetResources().Size())
+
+	c2 := components.Get(1).(EObject)
+	assert.False(t, c2.EIsProxy())
+	assert.Equal(t, "c2", c2.EGet(m.componentName))
+	assert.Equal(t, system, c2.EContainer())
+	assert.Equal(t, m.components, c2.EContainmentFeature())
+	assert.Equal(t, "testdata/components.component.xml", c2.EResource().GetURI().String())
+	assert.Equal(t, eResource, components.Get(0).(EObject).EResource())
+
+	main := system.EGet(m.main).(EObject)
+	assert.False(t, main.EIsProxy())
+	assert.Equal(t, "main", main.EGet(m.componentName))
+	assert.Equal(t, system, main.EContainer())
+	assert.Equal(t, "testdata/components.main.xml", main.EResource().GetURI().String())
+	assert.Equal(t, 3, resourceSet.GetResources().Size())
+}
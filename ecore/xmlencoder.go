@@ -12,7 +12,9 @@ package ecore
 import (
 	"fmt"
 	"io"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
@@ -63,6 +65,9 @@ type XMLEncoder struct {
 	uriToPrefixes    map[string][]string
 	packages         map[EPackage]string
 	featureKinds     map[EStructuralFeature]xmlSaveFeatureKind
+	extensions       map[EObject]*XMLExtension
+	savedExtensions  map[*XMLExtensionElement]struct{}
+	extensionRenames map[*XMLExtension]map[string]string
 	extendedMetaData *ExtendedMetaData
 	str              *xmlString
 	errorFn          func(diagnostic EDiagnostic)
@@ -70,6 +75,8 @@ type XMLEncoder struct {
 	xmlVersion       string
 	encoding         string
 	keepDefaults     bool
+	declareXML       bool
+	canonical        bool
 }
 
 func NewXMLEncoder(resource EResource, w io.Writer, options map[string]any) *XMLEncoder {
@@ -84,13 +91,27 @@ func NewXMLEncoder(resource EResource, w io.Writer, options map[string]any) *XML
 	s.uriToPrefixes = make(map[string][]string)
 	s.prefixesToURI = make(map[string]string)
 	s.featureKinds = make(map[EStructuralFeature]xmlSaveFeatureKind)
+	s.declareXML = true
 	if options != nil {
 		s.idAttributeName, _ = options[XML_OPTION_ID_ATTRIBUTE_NAME].(string)
 		s.roots, _ = options[XML_OPTION_ROOT_OBJECTS].(EList)
 		if extendedMetaData := options[XML_OPTION_EXTENDED_META_DATA]; extendedMetaData != nil {
 			s.extendedMetaData = extendedMetaData.(*ExtendedMetaData)
 		}
+		if indent, isInt := options[XML_OPTION_INDENT].(int); isInt && indent >= 0 {
+			s.str.indentation = strings.Repeat(" ", indent)
+		}
+		if lineWidth, isInt := options[XML_OPTION_LINE_WIDTH].(int); isInt && lineWidth > 0 {
+			s.str.lineWidth = lineWidth
+		}
+		if encoding, isString := options[XML_OPTION_ENCODING].(string); isString && len(encoding) > 0 {
+			s.encoding = encoding
+		}
+		s.declareXML = options[XML_OPTION_DECLARE_XML] != false
+		s.canonical = options[XML_OPTION_CANONICAL] == true
+		s.str.sortAttributes = s.canonical || options[XML_OPTION_SORT_ATTRIBUTES] == true
 	}
+	s.str.firstAttributes = []string{"xsi:type", s.idAttributeName}
 	if s.extendedMetaData == nil {
 		s.extendedMetaData = NewExtendedMetaData()
 	}
@@ -130,6 +151,9 @@ func (s *XMLEncoder) EncodeObject(eObject EObject) (err error) {
 }
 
 func (s *XMLEncoder) encodeTopObject(eObject EObject) {
+	s.extensions = s.resource.GetEObjectToExtensionMap()
+	s.savedExtensions = map[*XMLExtensionElement]struct{}{}
+	s.extensionRenames = map[*XMLExtension]map[string]string{}
 	s.saveHeader()
 
 	// initialize prefixes if any in top
@@ -143,17 +167,39 @@ func (s *XMLEncoder) encodeTopObject(eObject EObject) {
 
 	s.saveTopObject(eObject)
 
-	// namespaces
+	// namespaces declarations are saved before other attributes of the first element
 	s.str.resetToFirstElementMark()
+	highWaterMarkName, highWaterMark := s.getHighWaterMark()
 	s.interfaces.(xmlEncoderInternal).saveNamespaces()
+	if len(highWaterMarkName) > 0 {
+		s.str.addAttribute(highWaterMarkName, strconv.FormatInt(highWaterMark, 10))
+	}
 
 	// write result
-	if err := s.str.write(s.w); err != nil {
+	if err := s.write(); err != nil {
 		s.error(NewEDiagnosticImpl(err.Error(), s.resource.GetURI().String(), 0, 0))
 	}
 }
 
+// write writes result converted to the document encoding
+func (s *XMLEncoder) write() error {
+	if isUTF8(s.encoding) {
+		return s.str.write(s.w)
+	}
+	w, err := newXMLCharsetWriter(s.encoding, s.w)
+	if err != nil {
+		return err
+	}
+	if err := s.str.write(w); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
 func (s *XMLEncoder) saveHeader() {
+	if !s.declareXML {
+		return
+	}
 	s.str.add(fmt.Sprintf("<?xml version=\"%v\" encoding=\"%v\"?>", s.xmlVersion, s.encoding))
 	s.str.addLine()
 }
@@ -225,6 +271,15 @@ func (s *XMLEncoder) saveElementID(eObject EObject) {
 	}
 }
 
+// getHighWaterMark returns the name of the ecore:highWaterMark attribute of the first element and the high-water mark
+// of the id manager if it is saved. The ecore namespace is declared before namespaces declarations are saved
+func (s *XMLEncoder) getHighWaterMark() (string, int64) {
+	if highWaterMark, isSequential := getIDHighWaterMark(s.resource.GetObjectIDManager()); len(s.idAttributeName) > 0 && isSequential {
+		return s.getPrefix(GetPackage(), true) + ":" + highWaterMarkAttrib, highWaterMark
+	}
+	return "", -1
+}
+
 func (s *XMLEncoder) saveFeatures(eObject EObject, attributesOnly bool) bool {
 	eClass := eObject.EClass()
 	eAllFeatures := eClass.GetEAllStructuralFeatures()
@@ -258,6 +313,10 @@ func (s *XMLEncoder) saveFeatures(eObject EObject, attributesOnly bool) bool {
 					s.saveManyEmpty(eObject, eFeature)
 					continue
 				}
+			case xsfkAttributeFeatureMap:
+				s.saveAttributeFeatureMap(eObject, eFeature)
+				continue
+			case xsfkElementFeatureMap:
 			case xsfkObjectContainSingleUnsettable:
 			case xsfkObjectContainSingle:
 			case xsfkObjectContainMany:
@@ -318,10 +377,17 @@ func (s *XMLEncoder) saveFeatures(eObject EObject, attributesOnly bool) bool {
 			elementCount++
 		}
 	}
-	if elementFeatures == nil {
+	extension := s.extensions[eObject]
+	if extension != nil {
+		s.saveExtensionAttributes(extension)
+	}
+	if elementFeatures == nil && (attributesOnly || extension == nil || len(extension.Elements) == 0) {
 		s.str.endEmptyElement()
 		return false
 	}
+	if extension != nil {
+		s.saveExtensionElements(extension, func(eFeature EStructuralFeature, index int) bool { return eFeature == nil })
+	}
 	for i := 0; i < elementCount; i++ {
 		eFeature := eAllFeatures.Get(elementFeatures[i]).(EStructuralFeature)
 		kind := s.featureKinds[eFeature]
@@ -354,13 +420,172 @@ func (s *XMLEncoder) saveFeatures(eObject EObject, attributesOnly bool) bool {
 			fallthrough
 		case xsfkObjectHrefMany:
 			s.saveHRefMany(eObject, eFeature)
+		case xsfkElementFeatureMap:
+			s.saveElementFeatureMap(eObject, eFeature)
+		}
+		if extension != nil {
+			s.saveExtensionElements(extension, func(f EStructuralFeature, index int) bool { return f == eFeature })
 		}
 	}
+	if extension != nil {
+		// elements following features which are not saved as elements
+		s.saveExtensionElements(extension, func(EStructuralFeature, int) bool { return true })
+	}
 
 	s.str.endElement()
 	return true
 }
 
+func (s *XMLEncoder) saveExtensionAttributes(extension *XMLExtension) {
+	renames := s.getExtensionRenames(extension)
+	for _, attribute := range extension.Attributes {
+		s.str.addAttribute(renameExtensionQName(attribute.Name, renames, false), html.EscapeString(attribute.Value))
+	}
+}
+
+// saveExtensionElements saves unknown elements of extension whose position is accepted by isAt
+func (s *XMLEncoder) saveExtensionElements(extension *XMLExtension, isAt func(EStructuralFeature, int) bool) {
+	for _, element := range extension.Elements {
+		if _, isSaved := s.savedExtensions[element]; !isSaved && isAt(element.Feature, element.Index) {
+			s.savedExtensions[element] = struct{}{}
+			s.saveExtensionElement(element, s.getExtensionRenames(extension))
+		}
+	}
+}
+
+// saveExtensionElementsAt saves unknown elements of eObject between elements of eFeature
+func (s *XMLEncoder) saveExtensionElementsAt(eObject EObject, eFeature EStructuralFeature, index int) {
+	if extension := s.extensions[eObject]; extension != nil && index > 0 {
+		s.saveExtensionElements(extension, func(f EStructuralFeature, i int) bool { return f == eFeature && i == index })
+	}
+}
+
+func (s *XMLEncoder) saveExtensionElement(element *XMLExtensionElement, renames map[string]string) {
+	s.str.startElement(renameExtensionQName(element.Name, renames, true))
+	for _, attribute := range element.Attributes {
+		s.str.addAttribute(renameExtensionQName(attribute.Name, renames, false), html.EscapeString(attribute.Value))
+	}
+	var text strings.Builder
+	hasElements := false
+	for _, content := range element.Contents {
+		switch content := content.(type) {
+		case string:
+			text.WriteString(content)
+		case *XMLExtensionElement:
+			hasElements = true
+		}
+	}
+	if !hasElements {
+		if text.Len() > 0 {
+			s.str.endContentElement(html.EscapeString(text.String()))
+		} else {
+			s.str.endEmptyElement()
+		}
+		return
+	}
+	for _, content := range element.Contents {
+		switch content := content.(type) {
+		case string:
+			// whitespaces between elements are not significant
+			if len(strings.TrimSpace(content)) > 0 {
+				s.str.addText(html.EscapeString(content))
+			}
+		case *XMLExtensionElement:
+			s.saveExtensionElement(content, renames)
+		}
+	}
+	s.str.endElement()
+}
+
+// getExtensionRenames declares namespaces of extension once and returns its renamed prefixes
+func (s *XMLEncoder) getExtensionRenames(extension *XMLExtension) map[string]string {
+	renames, isDeclared := s.extensionRenames[extension]
+	if !isDeclared {
+		renames = s.saveExtensionNamespaces(extension)
+		s.extensionRenames[extension] = renames
+	}
+	return renames
+}
+
+// saveExtensionNamespaces declares namespaces of extension which are not already declared.
+// Prefixes already bound to another namespace and the default namespace are renamed: it returns their new prefixes
+func (s *XMLEncoder) saveExtensionNamespaces(extension *XMLExtension) map[string]string {
+	prefixes := make([]string, 0, len(extension.Namespaces))
+	for prefix := range extension.Namespaces {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	var renames map[string]string
+	for _, prefix := range prefixes {
+		nsURI := extension.Namespaces[prefix]
+		if uri, exists := s.prefixesToURI[prefix]; !exists && len(prefix) > 0 {
+			s.prefixesToURI[prefix] = nsURI
+			s.uriToPrefixes[nsURI] = append(s.uriToPrefixes[nsURI], prefix)
+		} else if uri != nsURI || len(prefix) == 0 {
+			// prefix is bound to another namespace or is the default one which would apply to known elements
+			if renames == nil {
+				renames = map[string]string{}
+			}
+			renames[prefix] = s.getExtensionPrefix(prefix, nsURI)
+		}
+	}
+	return renames
+}
+
+// getExtensionPrefix returns a prefix bound to nsURI, declaring a new one derived from prefix if needed
+func (s *XMLEncoder) getExtensionPrefix(prefix string, nsURI string) string {
+	prefixes := slices.Clone(s.uriToPrefixes[nsURI])
+	sort.Strings(prefixes)
+	for _, nsPrefix := range prefixes {
+		if len(nsPrefix) > 0 {
+			return nsPrefix
+		}
+	}
+	if len(prefix) == 0 {
+		prefix = "_"
+	}
+	nsPrefix := prefix
+	for index := 1; ; index++ {
+		if _, exists := s.prefixesToURI[nsPrefix]; !exists {
+			break
+		}
+		nsPrefix = prefix + "_" + fmt.Sprintf("%d", index)
+	}
+	s.prefixesToURI[nsPrefix] = nsURI
+	s.uriToPrefixes[nsURI] = append(s.uriToPrefixes[nsURI], nsPrefix)
+	return nsPrefix
+}
+
+// renameExtensionQName returns the qualified name of an unknown element or attribute with renamed prefixes.
+// Names without prefix are in the default namespace for elements and in no namespace for attributes
+func renameExtensionQName(name string, renames map[string]string, isElement bool) string {
+	if len(renames) == 0 {
+		return name
+	}
+	prefix, local, hasPrefix := strings.Cut(name, ":")
+	switch {
+	case !hasPrefix && isElement:
+		if nsPrefix, isRenamed := renames[""]; isRenamed {
+			return nsPrefix + ":" + name
+		}
+	case !hasPrefix && name == xmlNS:
+		// default namespace declaration
+		if nsPrefix, isRenamed := renames[""]; isRenamed {
+			return xmlNS + ":" + nsPrefix
+		}
+	case hasPrefix && prefix == xmlNS && !isElement:
+		// namespace declaration
+		if nsPrefix, isRenamed := renames[local]; isRenamed {
+			return xmlNS + ":" + nsPrefix
+		}
+	case hasPrefix:
+		if nsPrefix, isRenamed := renames[prefix]; isRenamed {
+			return nsPrefix + ":" + local
+		}
+	}
+	return name
+}
+
 func (s *XMLEncoder) saveDataTypeSingle(eObject EObject, eFeature EStructuralFeature) {
 	val := eObject.EGetResolve(eFeature, false)
 	str, ok := s.getDataType(val, eFeature, true)
@@ -375,8 +600,9 @@ func (s *XMLEncoder) saveDataTypeMany(eObject EObject, eFeature EStructuralFeatu
 	p := d.GetEPackage()
 	f := p.GetEFactoryInstance()
 	name := s.getFeatureQName(eFeature)
-	for it := l.Iterator(); it.HasNext(); {
+	for i, it := 0, l.Iterator(); it.HasNext(); i++ {
 		value := it.Next()
+		s.saveExtensionElementsAt(eObject, eFeature, i)
 		if value == nil {
 			s.str.startElement(name)
 			s.str.addAttribute("xsi:nil", "true")
@@ -390,6 +616,49 @@ func (s *XMLEncoder) saveDataTypeMany(eObject EObject, eFeature EStructuralFeatu
 	}
 }
 
+// saveAttributeFeatureMap saves the entries of an attribute wildcard feature map as attributes
+func (s *XMLEncoder) saveAttributeFeatureMap(eObject EObject, eFeature EStructuralFeature) {
+	featureMap := eObject.EGetResolve(eFeature, false).(EFeatureMap)
+	for i := 0; i < featureMap.Size(); i++ {
+		entryFeature := featureMap.GetEStructuralFeature(i)
+		if str, ok := s.getDataType(featureMap.GetValue(i), entryFeature, true); ok {
+			s.str.addAttribute(s.getAttributeQName(entryFeature), html.EscapeString(str))
+		}
+	}
+}
+
+// saveElementFeatureMap saves the entries of a feature map as elements, text and comments in the map order
+func (s *XMLEncoder) saveElementFeatureMap(eObject EObject, eFeature EStructuralFeature) {
+	if s.extendedMetaData != nil && s.extendedMetaData.GetMixedFeature(eObject.EClass()) == eFeature {
+		s.str.startMixed()
+	}
+	featureMap := eObject.EGetResolve(eFeature, false).(EFeatureMap)
+	for i := 0; i < featureMap.Size(); i++ {
+		entryFeature := featureMap.GetEStructuralFeature(i)
+		value := featureMap.GetValue(i)
+		switch {
+		case entryFeature == GetXMLTypeTextFeature():
+			s.str.addText(html.EscapeString(value.(string)))
+		case entryFeature == GetXMLTypeCommentFeature():
+			s.str.addComment(value.(string))
+		case IsContains(entryFeature):
+			if value, _ := value.(EObjectInternal); value != nil {
+				s.saveEObjectInternal(value, entryFeature)
+			}
+		default:
+			if _, isReference := entryFeature.(EReference); isReference {
+				if value, _ := value.(EObject); value != nil {
+					s.saveHRef(value, entryFeature)
+				}
+			} else if str, ok := s.getDataType(value, entryFeature, false); ok {
+				s.str.addContent(s.getFeatureQName(entryFeature), html.EscapeString(str))
+			} else {
+				s.saveNil(eObject, entryFeature)
+			}
+		}
+	}
+}
+
 func (s *XMLEncoder) saveManyEmpty(_ EObject, eFeature EStructuralFeature) {
 	s.str.addAttribute(s.getFeatureQName(eFeature), "")
 }
@@ -445,8 +714,9 @@ func (s *XMLEncoder) saveContainedSingle(eObject EObject, eFeature EStructuralFe
 
 func (s *XMLEncoder) saveContainedMany(eObject EObject, eFeature EStructuralFeature) {
 	l := eObject.EGetResolve(eFeature, false).(EList)
-	for it := l.Iterator(); it.HasNext(); {
+	for i, it := 0, l.Iterator(); it.HasNext(); i++ {
 		value, _ := it.Next().(EObjectInternal)
+		s.saveExtensionElementsAt(eObject, eFeature, i)
 		if value != nil {
 			s.saveEObjectInternal(value, eFeature)
 		}
@@ -487,8 +757,9 @@ func (s *XMLEncoder) saveHRefSingle(eObject EObject, eFeature EStructuralFeature
 
 func (s *XMLEncoder) saveHRefMany(eObject EObject, eFeature EStructuralFeature) {
 	l := eObject.EGetResolve(eFeature, false).(EList)
-	for it := l.Iterator(); it.HasNext(); {
+	for i, it := 0, l.Iterator(); it.HasNext(); i++ {
 		value, _ := it.Next().(EObject)
+		s.saveExtensionElementsAt(eObject, eFeature, i)
 		if value != nil {
 			s.saveHRef(value, eFeature)
 		}
@@ -600,6 +871,11 @@ func (s *XMLEncoder) getSaveFeatureKind(f EStructuralFeature) xmlSaveFeatureKind
 				return xsfkObjectHrefSingle
 			}
 		}
+	} else if IsFeatureMap(f) {
+		if s.extendedMetaData != nil && s.extendedMetaData.GetFeatureKind(f) == ATTRIBUTE_WILDCARD_FEATURE {
+			return xsfkAttributeFeatureMap
+		}
+		return xsfkElementFeatureMap
 	} else {
 		// Attribute
 		d := f.GetEType().(EDataType)
@@ -680,6 +956,18 @@ func (s *XMLEncoder) getFeatureQName(eFeature EStructuralFeature) string {
 	}
 }
 
+// getAttributeQName returns the qualified name of an attribute which has a prefix if it has a namespace
+func (s *XMLEncoder) getAttributeQName(eFeature EStructuralFeature) string {
+	if s.extendedMetaData != nil {
+		name := s.extendedMetaData.GetName(eFeature)
+		if ePackage := s.getPackageForSpace(s.extendedMetaData.GetNamespace(eFeature)); ePackage != nil {
+			return s.getElementQName(ePackage, name, true)
+		}
+		return name
+	}
+	return eFeature.GetName()
+}
+
 func (s *XMLEncoder) getElementQName(ePackage EPackage, name string, mustHavePrefix bool) string {
 	nsPrefix := s.getPrefix(ePackage, mustHavePrefix)
 	if nsPrefix == "" {
@@ -704,6 +992,11 @@ func (s *XMLEncoder) getPrefix(ePackage EPackage, mustHavePrefix bool) string {
 		nsURI := ePackage.GetNsURI()
 		found := false
 		prefixes := s.uriToPrefixes[nsURI]
+		if s.canonical {
+			// prefix choice must not depend on prefix map order
+			prefixes = slices.Clone(prefixes)
+			sort.Strings(prefixes)
+		}
 		for _, prefix := range prefixes {
 			nsPrefix = prefix
 			if !mustHavePrefix || len(nsPrefix) > 0 {
@@ -719,7 +1012,7 @@ func (s *XMLEncoder) getPrefix(ePackage EPackage, mustHavePrefix bool) string {
 
 			if uri, exists := s.prefixesToURI[nsPrefix]; exists && uri != nsURI {
 				index := 1
-				for _, exists = s.prefixesToURI[nsPrefix+"_"+fmt.Sprintf("%d", index)]; exists; {
+				for _, exists = s.prefixesToURI[nsPrefix+"_"+fmt.Sprintf("%d", index)]; exists; _, exists = s.prefixesToURI[nsPrefix+"_"+fmt.Sprintf("%d", index)] {
 					index++
 				}
 				nsPrefix += "_" + fmt.Sprintf("%d", index)
@@ -11,6 +11,7 @@ package ecore
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -401,3 +402,193 @@ func BenchmarkXMLEncoderAllTypes(b *testing.B) {
 	eResource.GetContents().Add(eObject)
 	eResource.Save()
 }
+
+func TestXMLEncoderRecordUnknownFeature(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.LoadWithOptions(NewURI("testdata/library.simple.unknown.xml"), map[string]any{XML_OPTION_RECORD_UNKNOWN_FEATURE: true})
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	result := xmlProcessor.SaveToString(eResource, nil)
+	bytes, err := os.ReadFile("testdata/library.simple.unknown.saved.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(result, "\r\n", "\n"))
+}
+
+func TestXMLEncoderRecordUnknownFeature_PrefixConflict(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	// 'lib' prefix of the package is bound to the extension namespace
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<l:Library xmlns:l="http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0" xmlns:lib="http://www.masagroup.net/ext" owner="Owner" lib:rating="5">
+  <lib:notes>First</lib:notes>
+  <extra xmlns="http://www.masagroup.net/other"/>
+</l:Library>
+`
+	eResource := xmlProcessor.LoadWithReader(strings.NewReader(content), map[string]any{XML_OPTION_RECORD_UNKNOWN_FEATURE: true})
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	result := xmlProcessor.SaveToString(eResource, nil)
+	assert.Contains(t, result, `xmlns:lib="http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0"`)
+	assert.Contains(t, result, `xmlns:lib_1="http://www.masagroup.net/ext"`)
+	assert.Contains(t, result, `lib_1:rating="5"`)
+	assert.Contains(t, result, `<lib_1:notes>First</lib_1:notes>`)
+	assert.Contains(t, result, `<_:extra xmlns:_="http://www.masagroup.net/other"/>`)
+
+	// saved content is loaded back with the same extension
+	eNewResource := xmlProcessor.LoadWithReader(strings.NewReader(result), map[string]any{XML_OPTION_RECORD_UNKNOWN_FEATURE: true})
+	require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+	assert.Equal(t, result, xmlProcessor.SaveToString(eNewResource, nil))
+}
+
+func TestXMLEncoderFormattingOptions(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.simple.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	result := xmlProcessor.SaveToString(eResource, map[string]any{XML_OPTION_INDENT: 4, XML_OPTION_LINE_WIDTH: 20, XML_OPTION_DECLARE_XML: false})
+	bytes, err := os.ReadFile("testdata/library.simple.formatted.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(result, "\r\n", "\n"))
+}
+
+func TestXMLEncoderCanonical(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	eResourceSet := NewEResourceSetImpl()
+	eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eResource := eResourceSet.CreateResource(NewURI("testdata/library.simple.xml"))
+	require.NotNil(t, eResource)
+	eResource.SetObjectIDManager(NewIncrementalIDManager())
+	eResource.Load()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	var strbuff strings.Builder
+	eResource.SaveWithWriter(&strbuff, map[string]any{XML_OPTION_CANONICAL: true, XML_OPTION_ID_ATTRIBUTE_NAME: "id"})
+
+	bytes, err := os.ReadFile("testdata/library.simple.canonical.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(strbuff.String(), "\r\n", "\n"))
+}
+
+func TestXMLEncoderCanonicalPrefix(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+	nsURI := ePackage.GetNsURI()
+	eResource := NewEResourceImpl()
+
+	// first prefix of the map
+	encoder := NewXMLEncoder(eResource, nil, nil)
+	encoder.uriToPrefixes[nsURI] = []string{"b", "a"}
+	assert.Equal(t, "b", encoder.getPrefix(ePackage, false))
+
+	// sorted first prefix
+	encoder = NewXMLEncoder(eResource, nil, map[string]any{XML_OPTION_CANONICAL: true})
+	encoder.uriToPrefixes[nsURI] = []string{"b", "a"}
+	assert.Equal(t, "a", encoder.getPrefix(ePackage, false))
+}
+
+func TestXMLEncoderPrefixConflict(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	encoder := NewXMLEncoder(NewEResourceImpl(), nil, nil)
+	encoder.prefixesToURI["lib"] = "http://lib"
+	encoder.prefixesToURI["lib_1"] = "http://lib_1"
+	assert.Equal(t, "lib_2", encoder.getPrefix(ePackage, false))
+	assert.Equal(t, ePackage.GetNsURI(), encoder.prefixesToURI["lib_2"])
+}
+
+func TestXMLEncoderEncoding(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	for encoding, path := range map[string]string{"ISO-8859-1": "testdata/library.simple.latin1.xml", "UTF-16": "testdata/library.simple.utf16.xml"} {
+		eResource := xmlProcessor.Load(NewURI(path))
+		require.NotNil(t, eResource)
+		require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+		result := xmlProcessor.SaveToString(eResource, map[string]any{XML_OPTION_ENCODING: encoding})
+		bytes, err := os.ReadFile(path)
+		assert.Nil(t, err)
+		assert.Equal(t, string(bytes), result, encoding)
+	}
+}
+
+func TestXMLEncoderEncodingUnsupportedCharacter(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/library.simple.latin1.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	eLibrary, _ := eResource.GetContents().Get(0).(EObject)
+	require.NotNil(t, eLibrary)
+	eLibrary.ESet(eLibraryClass.GetEStructuralFeatureFromName("owner"), "5€")
+
+	var strbuff strings.Builder
+	eResource.SaveWithWriter(&strbuff, map[string]any{XML_OPTION_ENCODING: "ISO-8859-1"})
+	require.False(t, eResource.GetErrors().Empty())
+	assert.Equal(t, "character '€' cannot be represented in encoding 'ISO-8859-1'", eResource.GetErrors().Get(0).(EDiagnostic).GetMessage())
+
+	eResource.GetErrors().Clear()
+	eResource.SaveWithWriter(&strbuff, map[string]any{XML_OPTION_ENCODING: "unknown"})
+	require.False(t, eResource.GetErrors().Empty())
+	assert.Equal(t, "unsupported encoding 'unknown'", eResource.GetErrors().Get(0).(EDiagnostic).GetMessage())
+}
+
+func TestXMLEncoderFeatureMap(t *testing.T) {
+	fmPackage := loadPackage("featuremap.ecore")
+	require.NotNil(t, fmPackage)
+	extPackage := loadPackage("featuremap.extension.ecore")
+	require.NotNil(t, extPackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{fmPackage, extPackage}))
+	eResource := xmlProcessor.Load(NewURI("testdata/featuremap.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// features, text and comments are saved in their original order
+	var strbuff strings.Builder
+	xmlProcessor.SaveWithWriter(&strbuff, eResource, nil)
+	bytes, err := os.ReadFile("testdata/featuremap.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(strbuff.String(), "\r\n", "\n"))
+}
+
+func TestXMLEncoderContainmentProxy(t *testing.T) {
+	m := loadComponentsModel(t)
+	dir := t.TempDir()
+	resourceSet := NewEResourceSetImpl()
+	_, resources := m.newSystem(resourceSet, dir, "xml")
+	for _, resource := range resources {
+		resource.Save()
+		require.True(t, resource.GetErrors().Empty(), diagnosticError(resource.GetErrors()))
+	}
+
+	// contained objects in other resources are saved as href
+	for _, name := range []string{"system", "component", "main"} {
+		fileName := "components." + name + ".xml"
+		expected, err := os.ReadFile(filepath.Join("testdata", fileName))
+		require.Nil(t, err)
+		actual, err := os.ReadFile(filepath.Join(dir, fileName))
+		require.Nil(t, err)
+		assert.Equal(t, strings.ReplaceAll(string(expected), "\r\n", "\n"), string(actual), fileName)
+	}
+}
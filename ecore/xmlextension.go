@@ -0,0 +1,43 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+// XMLExtensionAttribute is an attribute unknown to the metamodel
+type XMLExtensionAttribute struct {
+	// Name is the qualified name of the attribute
+	Name  string
+	Value string
+}
+
+// XMLExtensionElement is an element unknown to the metamodel
+type XMLExtensionElement struct {
+	// Name is the qualified name of the element
+	Name       string
+	Attributes []XMLExtensionAttribute
+	// Contents are the child elements (*XMLExtensionElement) and texts (string) of the element
+	Contents []any
+	// Feature is the known feature whose element precedes the element or nil if it is the first one
+	Feature EStructuralFeature
+	// Index is the number of elements of Feature preceding the element
+	Index int
+}
+
+// XMLExtension is the content of an object which is unknown to its class.
+// It is recorded when decoding with XML_OPTION_RECORD_UNKNOWN_FEATURE and written back by xml encoders
+type XMLExtension struct {
+	Attributes []XMLExtensionAttribute
+	Elements   []*XMLExtensionElement
+	// Namespaces maps prefixes used by the extension to their uri
+	Namespaces map[string]string
+}
+
+func newXMLExtension() *XMLExtension {
+	return &XMLExtension{Namespaces: map[string]string{}}
+}
@@ -2,6 +2,7 @@ package ecore
 
 import (
 	"io"
+	"sort"
 	"strings"
 )
 
@@ -10,6 +11,11 @@ type xmlStringSegment struct {
 	lineWidth int
 }
 
+type xmlAttribute struct {
+	name  string
+	value string
+}
+
 type xmlString struct {
 	currentSegment     *xmlStringSegment
 	firstElementMark   *xmlStringSegment
@@ -17,9 +23,13 @@ type xmlString struct {
 	segments           []*xmlStringSegment
 	indents            []string
 	elementNames       []string
+	attributes         []xmlAttribute
+	firstAttributes    []string
 	lineWidth          int
 	depth              int
+	mixedDepth         int
 	lastElementIsStart bool
+	sortAttributes     bool
 }
 
 const MaxInt = int(^uint(0) >> 1)
@@ -31,7 +41,7 @@ func newXmlString() *xmlString {
 		currentSegment:     segment,
 		lineWidth:          MaxInt,
 		depth:              0,
-		indentation:        "    ",
+		indentation:        "  ",
 		indents:            []string{""},
 		lastElementIsStart: false,
 	}
@@ -55,10 +65,28 @@ func (s *xmlString) add(newString string) {
 }
 
 func (s *xmlString) addLine() {
+	if s.mixedDepth > 0 {
+		// no line break in mixed content
+		return
+	}
 	s.add("\n")
 	s.currentSegment.lineWidth = 0
 }
 
+// startMixed starts the mixed content of current element where text is significant
+func (s *xmlString) startMixed() {
+	if s.mixedDepth == 0 {
+		s.mixedDepth = s.depth
+	}
+}
+
+// endMixed ends the mixed content if its element is closed
+func (s *xmlString) endMixed() {
+	if s.depth < s.mixedDepth {
+		s.mixedDepth = 0
+	}
+}
+
 func (s *xmlString) startElement(name string) {
 	if s.lastElementIsStart {
 		s.closeStartElement()
@@ -77,6 +105,7 @@ func (s *xmlString) startElement(name string) {
 }
 
 func (s *xmlString) closeStartElement() {
+	s.flushAttributes()
 	s.add(">")
 	s.addLine()
 	s.lastElementIsStart = false
@@ -92,14 +121,52 @@ func (s *xmlString) endElement() {
 			s.add("</")
 			s.add(name)
 			s.add(">")
+			s.endMixed()
 			s.addLine()
 		}
 	}
 }
 
+// endContentElement ends current element with a text content
+func (s *xmlString) endContentElement(content string) {
+	s.flushAttributes()
+	s.add(">")
+	s.add(content)
+	s.add("</")
+	s.add(s.removeLast())
+	s.add(">")
+	s.endMixed()
+	s.addLine()
+	s.lastElementIsStart = false
+}
+
+// addText adds a text content to current element
+func (s *xmlString) addText(content string) {
+	if s.lastElementIsStart {
+		s.closeStartElement()
+	}
+	s.add(s.getElementIndentWithExtra(1))
+	s.add(content)
+	s.addLine()
+}
+
+// addComment adds a comment to current element
+func (s *xmlString) addComment(content string) {
+	if s.lastElementIsStart {
+		s.closeStartElement()
+	}
+	s.add(s.getElementIndentWithExtra(1))
+	s.add("<!--")
+	s.add(content)
+	s.add("-->")
+	s.addLine()
+}
+
 func (s *xmlString) endEmptyElement() {
+	s.flushAttributes()
 	s.removeLast()
 	s.add("/>")
+	s.endMixed()
 	s.addLine()
 	s.lastElementIsStart = false
 }
@@ -115,13 +182,48 @@ func (s *xmlString) removeLast() string {
 }
 
 func (s *xmlString) addAttribute(name string, value string) {
+	if s.sortAttributes && s.lastElementIsStart {
+		// attributes of current element are sorted when it is closed
+		s.attributes = append(s.attributes, xmlAttribute{name: name, value: value})
+		return
+	}
 	s.startAttribute(name)
 	s.addAttributeContent(value)
 	s.endAttribute()
 }
 
+// getAttributeRank returns the rank of an attribute which must be saved before the others
+func (s *xmlString) getAttributeRank(name string) int {
+	for i, first := range s.firstAttributes {
+		if first == name {
+			return i
+		}
+	}
+	return len(s.firstAttributes)
+}
+
+// flushAttributes saves sorted attributes of current element
+func (s *xmlString) flushAttributes() {
+	if len(s.attributes) == 0 {
+		return
+	}
+	sort.SliceStable(s.attributes, func(i, j int) bool {
+		ri, rj := s.getAttributeRank(s.attributes[i].name), s.getAttributeRank(s.attributes[j].name)
+		if ri != rj {
+			return ri < rj
+		}
+		return s.attributes[i].name < s.attributes[j].name
+	})
+	for _, attribute := range s.attributes {
+		s.startAttribute(attribute.name)
+		s.addAttributeContent(attribute.value)
+		s.endAttribute()
+	}
+	s.attributes = s.attributes[:0]
+}
+
 func (s *xmlString) startAttribute(name string) {
-	if s.currentSegment.lineWidth > s.lineWidth {
+	if s.currentSegment.lineWidth > s.lineWidth && s.mixedDepth == 0 {
 		s.addLine()
 		s.add(s.getAttributeIndent())
 	} else {
@@ -183,9 +285,13 @@ func (s *xmlString) getElementIndent() string {
 }
 
 func (s *xmlString) getElementIndentWithExtra(extra int) string {
+	if s.mixedDepth > 0 {
+		// no indentation in mixed content
+		return ""
+	}
 	nesting := s.depth + extra - 1
 	for i := len(s.indents) - 1; i < nesting; i++ {
-		s.indents = append(s.indents, s.indents[i]+"  ")
+		s.indents = append(s.indents, s.indents[i]+s.indentation)
 	}
 	return s.indents[nesting]
 }
@@ -193,14 +299,14 @@ func (s *xmlString) getElementIndentWithExtra(extra int) string {
 func (s *xmlString) getAttributeIndent() string {
 	nesting := s.depth + 1
 	for i := len(s.indents) - 1; i < nesting; i++ {
-		s.indents = append(s.indents, s.indents[i]+"  ")
+		s.indents = append(s.indents, s.indents[i]+s.indentation)
 	}
 	return s.indents[nesting]
 }
 
 func (s *xmlString) mark() *xmlStringSegment {
 	r := s.currentSegment
-	s.currentSegment = &xmlStringSegment{}
+	s.currentSegment = &xmlStringSegment{lineWidth: r.lineWidth}
 	s.segments = append(s.segments, s.currentSegment)
 	return r
 }
@@ -0,0 +1,70 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import "sync"
+
+const xmlTypeURI = "http://www.eclipse.org/emf/2003/XMLType"
+
+type xmlTypePackage struct {
+	ePackage EPackage
+	text     EAttribute
+	comment  EAttribute
+}
+
+var xmlTypeInstance *xmlTypePackage
+
+var xmlTypeOnce sync.Once
+
+func getXMLType() *xmlTypePackage {
+	xmlTypeOnce.Do(func() {
+		factory := GetFactory()
+		newAttribute := func(name string) EAttribute {
+			eAttribute := factory.CreateEAttribute()
+			eAttribute.SetName(name)
+			eAttribute.SetEType(GetPackage().GetEString())
+			eAttribute.SetUpperBound(UNBOUNDED_MULTIPLICITY)
+			eAttribute.SetTransient(true)
+			eAttribute.SetDerived(true)
+			eAttribute.SetVolatile(true)
+			return eAttribute
+		}
+		t := &xmlTypePackage{
+			ePackage: factory.CreateEPackage(),
+			text:     newAttribute("text"),
+			comment:  newAttribute("comment"),
+		}
+		t.ePackage.SetName("type")
+		t.ePackage.SetNsPrefix("xml")
+		t.ePackage.SetNsURI(xmlTypeURI)
+		eClass := factory.CreateEClass()
+		eClass.SetName("XMLTypeDocumentRoot")
+		eClass.GetEStructuralFeatures().Add(t.comment)
+		eClass.GetEStructuralFeatures().Add(t.text)
+		t.ePackage.GetEClassifiers().Add(eClass)
+		xmlTypeInstance = t
+	})
+	return xmlTypeInstance
+}
+
+// GetXMLTypePackage returns the package of the features of the text and comments of mixed contents
+func GetXMLTypePackage() EPackage {
+	return getXMLType().ePackage
+}
+
+// GetXMLTypeTextFeature returns the feature of the text entries of mixed contents feature maps
+func GetXMLTypeTextFeature() EAttribute {
+	return getXMLType().text
+}
+
+// GetXMLTypeCommentFeature returns the feature of the comment entries of mixed contents feature maps
+func GetXMLTypeCommentFeature() EAttribute {
+	return getXMLType().comment
+}
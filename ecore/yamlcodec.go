@@ -0,0 +1,60 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"io"
+)
+
+const (
+	YAML_OPTION_ID_ATTRIBUTE_NAME = "ID_ATTRIBUTE" // if set, save and load id attribute of the object with this name
+	YAML_OPTION_INDENT            = "INDENT"       // indentation width ( int )
+)
+
+type YAMLCodec struct {
+}
+
+func (yc *YAMLCodec) NewEncoder(resource EResource, w io.Writer, options map[string]any) EEncoder {
+	return NewYAMLEncoder(resource, w, options)
+}
+func (yc *YAMLCodec) NewDecoder(resource EResource, r io.Reader, options map[string]any) EDecoder {
+	return NewYAMLDecoder(resource, r, options)
+}
+
+type yamlComments struct {
+	head string
+	line string
+	foot string
+}
+
+func (c *yamlComments) isEmpty() bool {
+	return len(c.head) == 0 && len(c.line) == 0 && len(c.foot) == 0
+}
+
+// yamlCommentsAdapter keeps the comments of a decoded object so that
+// they are written back when the object is encoded again
+type yamlCommentsAdapter struct {
+	AbstractEAdapter
+	object yamlComments
+	keys   map[string]yamlComments // comments of mapping keys
+	values map[string]yamlComments // comments of data values
+}
+
+func (a *yamlCommentsAdapter) NotifyChanged(notification ENotification) {
+}
+
+func getYAMLCommentsAdapter(eObject EObject) *yamlCommentsAdapter {
+	for it := eObject.EAdapters().Iterator(); it.HasNext(); {
+		if adapter, _ := it.Next().(*yamlCommentsAdapter); adapter != nil {
+			return adapter
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package ecore
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLCodec_NewEncoder(t *testing.T) {
+	mockResource := NewMockEResource(t)
+	codec := &YAMLCodec{}
+	require.NotNil(t, codec.NewEncoder(mockResource, nil, nil))
+}
+
+func TestYAMLCodec_NewDecoder(t *testing.T) {
+	mockResource := NewMockEResource(t)
+	codec := &YAMLCodec{}
+	require.NotNil(t, codec.NewDecoder(mockResource, nil, nil))
+}
+
+func TestYAMLCodec_Registry(t *testing.T) {
+	extensionToCodecs := GetCodecRegistry().GetExtensionToCodecMap()
+	assert.IsType(t, &YAMLCodec{}, extensionToCodecs["yaml"])
+	assert.IsType(t, &YAMLCodec{}, extensionToCodecs["yml"])
+}
+
+func TestYAMLEncoder_EncodeResourceComplex(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(CreateFileURI("testdata/library.complex.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	var buffer bytes.Buffer
+	NewYAMLEncoder(eResource, &buffer, nil).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	//os.WriteFile("testdata/library.complex.yaml", buffer.Bytes(), 0644)
+
+	bytes, err := os.ReadFile("testdata/library.complex.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, strings.ReplaceAll(string(bytes), "\r\n", "\n"), strings.ReplaceAll(buffer.String(), "\r\n", "\n"))
+}
+
+func TestYAMLCodec_EncodeDecodeResource(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+
+	xmlProcessor := NewXMLProcessor(XMLProcessorPackages([]EPackage{ePackage}))
+	eResource := xmlProcessor.Load(CreateFileURI("testdata/library.complex.xml"))
+	require.NotNil(t, eResource)
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// encode
+	var buffer bytes.Buffer
+	codec := &YAMLCodec{}
+	codec.NewEncoder(eResource, &buffer, nil).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	// decode
+	eNewResourceSet := NewEResourceSetImpl()
+	eNewResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eNewResource := eNewResourceSet.CreateResource(CreateFileURI("testdata/library.complex.xml"))
+	codec.NewDecoder(eNewResource, &buffer, nil).DecodeResource()
+	require.True(t, eNewResource.GetErrors().Empty(), diagnosticError(eNewResource.GetErrors()))
+	require.Equal(t, 1, eNewResource.GetContents().Size())
+
+	// document roots differ by their transient namespaces maps
+	eDocumentRootClass, _ := ePackage.GetEClassifier("DocumentRoot").(EClass)
+	require.NotNil(t, eDocumentRootClass)
+	eLibraryFeature := eDocumentRootClass.GetEStructuralFeatureFromName("library")
+	eLibrary, _ := eResource.GetContents().Get(0).(EObject).EGet(eLibraryFeature).(EObject)
+	require.NotNil(t, eLibrary)
+	eNewLibrary, _ := eNewResource.GetContents().Get(0).(EObject).EGet(eLibraryFeature).(EObject)
+	require.NotNil(t, eNewLibrary)
+	assert.True(t, Equals(eLibrary, eNewLibrary))
+}
+
+func TestYAMLCodec_EncodeDecodeObject_WithExternalReferences(t *testing.T) {
+	eResourceSet := NewEResourceSetImpl()
+	codecOptions := map[string]any{YAML_OPTION_ID_ATTRIBUTE_NAME: "id"}
+	_, eShopPackage := loadTestPackage(t, eResourceSet, NewURI("testdata/shop.ecore"))
+	require.NotNil(t, eShopPackage)
+	_, eShopModel := loadTestModel(t, eResourceSet, NewURI("testdata/shop.xml"))
+	require.NotNil(t, eShopModel)
+	_, eOrdersPackage := loadTestPackage(t, eResourceSet, NewURI("testdata/orders.ecore"))
+	require.NotNil(t, eOrdersPackage)
+	eOrdersModelResource, eOrdersModel := loadTestModel(t, eResourceSet, NewURI("testdata/orders.xml"))
+	require.NotNil(t, eOrdersModel)
+	ResolveAll(eOrdersModel)
+
+	// encode orders
+	var buffer bytes.Buffer
+	require.NoError(t, NewYAMLEncoder(eOrdersModelResource, &buffer, codecOptions).EncodeObject(eOrdersModel))
+	eResourceSet.GetResources().Remove(eOrdersModelResource)
+
+	// decode orders
+	eNewResource := NewEResourceImpl()
+	eNewResource.SetObjectIDManager(NewIncrementalIDManager())
+	eNewResource.SetURI(NewURI("testdata/orders.xml"))
+	eResourceSet.GetResources().Add(eNewResource)
+	eNewOrders, err := NewYAMLDecoder(eNewResource, &buffer, codecOptions).DecodeObject()
+	require.NoError(t, err)
+	require.NotNil(t, eNewOrders)
+	assert.Equal(t, eOrdersModelResource.GetObjectIDManager().GetID(eOrdersModel), eNewResource.GetObjectIDManager().GetID(eNewOrders))
+
+	eOrdersClass, _ := eOrdersPackage.GetEClassifier("Orders").(EClass)
+	require.NotNil(t, eOrdersClass)
+	eOrderClass, _ := eOrdersPackage.GetEClassifier("Order").(EClass)
+	require.NotNil(t, eOrderClass)
+	eOrder, _ := eNewOrders.EGet(eOrdersClass.GetEStructuralFeatureFromName("order")).(EList).Get(0).(EObject)
+	require.NotNil(t, eOrder)
+
+	// product is a proxy to the shop resource
+	eNewResource.GetContents().Add(eNewOrders)
+	eProduct, _ := eOrder.EGet(eOrderClass.GetEStructuralFeatureFromName("product")).(EObject)
+	require.NotNil(t, eProduct)
+	assert.False(t, eProduct.EIsProxy())
+}
+
+func TestYAMLCodec_Comments(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+
+	source := `# library of the city
+eClass: http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0#//Library
+location: City Library # official name
+books:
+  # first book
+  - eClass: http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0#//Book
+    name: Dune
+`
+	eResourceSet := NewEResourceSetImpl()
+	eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eResource := eResourceSet.CreateResource(NewURI("testdata/library.yaml"))
+	NewYAMLDecoder(eResource, strings.NewReader(source), nil).DecodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+	require.Equal(t, 1, eResource.GetContents().Size())
+
+	var buffer bytes.Buffer
+	NewYAMLEncoder(eResource, &buffer, nil).EncodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+	assert.Contains(t, buffer.String(), "# library of the city")
+	assert.Contains(t, buffer.String(), "# first book")
+	assert.Contains(t, buffer.String(), "location: City Library # official name")
+}
+
+func TestYAMLDecoder_Errors(t *testing.T) {
+	ePackage := loadPackage("library.simple.ecore")
+	require.NotNil(t, ePackage)
+	eResourceSet := NewEResourceSetImpl()
+	eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+
+	// unknown feature
+	eResource := eResourceSet.CreateResource(NewURI("testdata/library.yaml"))
+	source := `eClass: http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0#//Library
+location: City Library
+unknown: value
+`
+	NewYAMLDecoder(eResource, strings.NewReader(source), nil).DecodeResource()
+	require.Equal(t, 1, eResource.GetErrors().Size())
+	diagnostic := eResource.GetErrors().Get(0).(EDiagnostic)
+	assert.Equal(t, 3, diagnostic.GetLine())
+	assert.Equal(t, 1, diagnostic.GetColumn())
+
+	// syntax error
+	eResource = eResourceSet.CreateResource(NewURI("testdata/invalid.yaml"))
+	source = `eClass: http:///org/eclipse/emf/examples/library/library.simple.ecore/1.0.0#//Library
+location: [City Library
+`
+	NewYAMLDecoder(eResource, strings.NewReader(source), nil).DecodeResource()
+	require.Equal(t, 1, eResource.GetErrors().Size())
+	diagnostic = eResource.GetErrors().Get(0).(EDiagnostic)
+	assert.NotEqual(t, 0, diagnostic.GetLine())
+
+	// unknown class
+	_, err := NewYAMLDecoder(eResource, strings.NewReader("eClass: unknown#//Library\n"), nil).DecodeObject()
+	assert.Error(t, err)
+}
+
+func TestYAMLDecoder_Aliases(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+	eResourceSet := NewEResourceSetImpl()
+	eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eResource := eResourceSet.CreateResource(NewURI("testdata/library.yaml"))
+	source := `eClass: http:///org/eclipse/emf/examples/library/library.ecore/1.0.0#//Library
+name: City Library
+writers:
+  - &herbert
+    eClass: http:///org/eclipse/emf/examples/library/library.ecore/1.0.0#//Writer
+    firstName: Frank
+    lastName: Herbert
+books:
+  - eClass: http:///org/eclipse/emf/examples/library/library.ecore/1.0.0#//Book
+    title: Dune
+    author: *herbert
+`
+	NewYAMLDecoder(eResource, strings.NewReader(source), nil).DecodeResource()
+	require.True(t, eResource.GetErrors().Empty(), diagnosticError(eResource.GetErrors()))
+
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	eBookClass, _ := ePackage.GetEClassifier("Book").(EClass)
+	require.NotNil(t, eBookClass)
+	eLibrary := eResource.GetContents().Get(0).(EObject)
+	eWriter := eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("writers")).(EList).Get(0)
+	eBook := eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("books")).(EList).Get(0).(EObject)
+	assert.Equal(t, eWriter, eBook.EGet(eBookClass.GetEStructuralFeatureFromName("author")))
+}
+
+func TestYAMLDecoder_AliasContainment(t *testing.T) {
+	ePackage := loadPackage("library.complex.ecore")
+	require.NotNil(t, ePackage)
+	eResourceSet := NewEResourceSetImpl()
+	eResourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+	eResource := eResourceSet.CreateResource(NewURI("testdata/library.yaml"))
+	source := `eClass: http:///org/eclipse/emf/examples/library/library.ecore/1.0.0#//Library
+name: City Library
+writers:
+  - &herbert
+    eClass: http:///org/eclipse/emf/examples/library/library.ecore/1.0.0#//Writer
+    firstName: Frank
+    lastName: Herbert
+branches:
+  - eClass: http:///org/eclipse/emf/examples/library/library.ecore/1.0.0#//Library
+    name: Branch
+    writers:
+      - *herbert
+`
+	NewYAMLDecoder(eResource, strings.NewReader(source), nil).DecodeResource()
+	require.Equal(t, 1, eResource.GetErrors().Size())
+
+	// aliased writer stays in its container
+	eLibraryClass, _ := ePackage.GetEClassifier("Library").(EClass)
+	require.NotNil(t, eLibraryClass)
+	eLibrary := eResource.GetContents().Get(0).(EObject)
+	eWriters := eLibrary.EGet(eLibraryClass.GetEStructuralFeatureFromName("writers")).(EList)
+	require.Equal(t, 1, eWriters.Size())
+	assert.Equal(t, eLibrary, eWriters.Get(0).(EObject).EContainer())
+}
+
+func TestYAMLDecoder_DecodeObjectNoResource(t *testing.T) {
+	// object from the global package registry
+	eObject, err := NewYAMLDecoder(nil, strings.NewReader("eClass: http://www.eclipse.org/emf/2002/Ecore#//EClass\nname: MyClass\n"), nil).DecodeObject()
+	require.NoError(t, err)
+	eClass, _ := eObject.(EClass)
+	require.NotNil(t, eClass)
+	assert.Equal(t, "MyClass", eClass.GetName())
+
+	// unknown package
+	_, err = NewYAMLDecoder(nil, strings.NewReader("eClass: unknown#//Library\n"), nil).DecodeObject()
+	assert.Error(t, err)
+
+	// syntax error
+	_, err = NewYAMLDecoder(nil, strings.NewReader("name: [unclosed\n"), nil).DecodeObject()
+	assert.Error(t, err)
+}
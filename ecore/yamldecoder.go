@@ -0,0 +1,395 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type yamlReference struct {
+	eObject  EObject
+	eFeature EStructuralFeature
+	eClass   EClass
+	uri      string
+	node     *yaml.Node
+}
+
+var yamlErrorLineRegexp = regexp.MustCompile(`line (\d+)`)
+
+// YAMLDecoder reads resources written by the YAMLEncoder.
+// Cross references are either {eClass, eRef} mappings or aliases of anchored objects.
+type YAMLDecoder struct {
+	resource        EResource
+	r               io.Reader
+	featureKinds    map[EStructuralFeature]jsonFeatureKind
+	classes         map[string]EClass
+	nodeToObject    map[*yaml.Node]EObject
+	references      []yamlReference
+	errorFn         func(diagnostic EDiagnostic)
+	idAttributeName string
+}
+
+func NewYAMLDecoder(resource EResource, r io.Reader, options map[string]any) *YAMLDecoder {
+	d := &YAMLDecoder{
+		resource:     resource,
+		r:            r,
+		featureKinds: map[EStructuralFeature]jsonFeatureKind{},
+		classes:      map[string]EClass{},
+		nodeToObject: map[*yaml.Node]EObject{},
+	}
+	if options != nil {
+		d.idAttributeName, _ = options[YAML_OPTION_ID_ATTRIBUTE_NAME].(string)
+	}
+	return d
+}
+
+func (d *YAMLDecoder) DecodeResource() {
+	d.errorFn = func(diagnostic EDiagnostic) {
+		d.resource.GetErrors().Add(diagnostic)
+	}
+	objects := []any{}
+	decoder := yaml.NewDecoder(d.r)
+	for {
+		document := &yaml.Node{}
+		if err := decoder.Decode(document); err != nil {
+			if !errors.Is(err, io.EOF) {
+				d.errorFn(d.newSyntaxError(err))
+			}
+			break
+		}
+		if eObject := d.decodeDocument(document); eObject != nil {
+			objects = append(objects, eObject)
+		}
+	}
+	// add objects to resource
+	d.resource.GetContents().AddAll(NewImmutableEList(objects))
+	// resolve references once all objects are in the resource
+	d.resolveReferences()
+}
+
+func (d *YAMLDecoder) DecodeObject() (eObject EObject, err error) {
+	d.errorFn = func(diagnostic EDiagnostic) {
+		if err == nil {
+			err = diagnostic
+		}
+	}
+	document := &yaml.Node{}
+	if e := yaml.NewDecoder(d.r).Decode(document); e != nil {
+		return nil, d.newSyntaxError(e)
+	}
+	eObject = d.decodeDocument(document)
+	d.resolveReferences()
+	return
+}
+
+func (d *YAMLDecoder) decodeDocument(document *yaml.Node) EObject {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		d.error(document, "invalid yaml document")
+		return nil
+	}
+	node := document.Content[0]
+	eObject := d.decodeObject(node)
+	if eObject != nil && (len(document.HeadComment) > 0 || len(document.FootComment) > 0) {
+		comments := d.getCommentsAdapter(eObject)
+		comments.object.head = joinYAMLComments(document.HeadComment, comments.object.head)
+		comments.object.foot = joinYAMLComments(comments.object.foot, document.FootComment)
+	}
+	return eObject
+}
+
+func (d *YAMLDecoder) decodeObject(node *yaml.Node) EObject {
+	if node.Kind == yaml.AliasNode {
+		// an alias refers to an object contained elsewhere : it can't be contained again
+		d.error(node, "alias can only be the value of a reference")
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		d.error(node, "object must be a mapping")
+		return nil
+	}
+	eClass := d.getClass(node)
+	if eClass == nil {
+		return nil
+	}
+	eObject := eClass.GetEPackage().GetEFactoryInstance().Create(eClass)
+	d.nodeToObject[node] = eObject
+
+	// comments
+	comments := &yamlCommentsAdapter{
+		object: yamlComments{head: node.HeadComment, line: node.LineComment, foot: node.FootComment},
+		keys:   map[string]yamlComments{},
+		values: map[string]yamlComments{},
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valueNode := node.Content[i+1]
+		if c := (yamlComments{head: keyNode.HeadComment, line: keyNode.LineComment, foot: keyNode.FootComment}); !c.isEmpty() {
+			comments.keys[keyNode.Value] = c
+		}
+		// comments of objects are kept by their own adapter
+		if valueNode.Kind != yaml.MappingNode {
+			if c := (yamlComments{head: valueNode.HeadComment, line: valueNode.LineComment, foot: valueNode.FootComment}); !c.isEmpty() {
+				comments.values[keyNode.Value] = c
+			}
+		}
+	}
+	if !comments.object.isEmpty() || len(comments.keys) > 0 || len(comments.values) > 0 {
+		eObject.EAdapters().Add(comments)
+	}
+
+	// features
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valueNode := node.Content[i+1]
+		key := keyNode.Value
+		if key == "eClass" {
+			continue
+		} else if len(d.idAttributeName) > 0 && key == d.idAttributeName {
+			if d.resource != nil {
+				if idManager := d.resource.GetObjectIDManager(); idManager != nil {
+					if err := idManager.SetID(eObject, valueNode.Value); err != nil {
+						d.error(valueNode, err.Error())
+					}
+				}
+			}
+			continue
+		}
+		eFeature := eClass.GetEStructuralFeatureFromName(key)
+		if eFeature == nil {
+			d.error(keyNode, fmt.Sprintf("unknown feature '%v' in class '%v'", key, eClass.GetName()))
+			continue
+		}
+		d.decodeFeatureValue(eObject, eFeature, valueNode)
+	}
+	return eObject
+}
+
+func (d *YAMLDecoder) decodeFeatureValue(eObject EObject, eFeature EStructuralFeature, node *yaml.Node) {
+	// compute feature kind
+	kind, ok := d.featureKinds[eFeature]
+	if !ok {
+		kind = getJSONCodecFeatureKind(eFeature)
+		d.featureKinds[eFeature] = kind
+	}
+
+	switch kind {
+	case jfkTransient:
+		d.error(node, fmt.Sprintf("feature '%v' is transient", eFeature.GetName()))
+	case jfkData:
+		if node.Kind != yaml.ScalarNode {
+			d.error(node, fmt.Sprintf("feature '%v' value must be a scalar", eFeature.GetName()))
+			return
+		}
+		if value, ok := d.getData(node, eFeature); ok {
+			eObject.ESet(eFeature, value)
+		}
+	case jfkDataList:
+		if node.Kind != yaml.SequenceNode {
+			d.error(node, fmt.Sprintf("feature '%v' value must be a sequence", eFeature.GetName()))
+			return
+		}
+		values := []any{}
+		for _, child := range node.Content {
+			if value, ok := d.getData(child, eFeature); ok {
+				values = append(values, value)
+			}
+		}
+		eObject.EGetResolve(eFeature, false).(EList).AddAll(NewBasicEList(values))
+	case jfkObject:
+		if eChild := d.decodeObject(node); eChild != nil {
+			eObject.ESet(eFeature, eChild)
+		}
+	case jfkObjectList:
+		if node.Kind != yaml.SequenceNode {
+			d.error(node, fmt.Sprintf("feature '%v' value must be a sequence", eFeature.GetName()))
+			return
+		}
+		l := eObject.EGetResolve(eFeature, false).(EList)
+		for _, child := range node.Content {
+			if eChild := d.decodeObject(child); eChild != nil {
+				l.Add(eChild)
+			}
+		}
+	case jfkObjectReference:
+		d.decodeObjectReference(eObject, eFeature, node)
+	case jfkObjectReferenceList:
+		if node.Kind != yaml.SequenceNode {
+			d.error(node, fmt.Sprintf("feature '%v' value must be a sequence", eFeature.GetName()))
+			return
+		}
+		for _, child := range node.Content {
+			d.decodeObjectReference(eObject, eFeature, child)
+		}
+	}
+}
+
+func (d *YAMLDecoder) decodeObjectReference(eObject EObject, eFeature EStructuralFeature, node *yaml.Node) {
+	reference := yamlReference{eObject: eObject, eFeature: eFeature, node: node}
+	switch node.Kind {
+	case yaml.AliasNode:
+		// resolved once all objects are decoded
+	case yaml.MappingNode:
+		reference.eClass = d.getClass(node)
+		if reference.eClass == nil {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "eRef" {
+				reference.uri = node.Content[i+1].Value
+			}
+		}
+		if len(reference.uri) == 0 {
+			d.error(node, fmt.Sprintf("reference of feature '%v' has no 'eRef'", eFeature.GetName()))
+			return
+		}
+	default:
+		d.error(node, fmt.Sprintf("feature '%v' value must be a reference or an alias", eFeature.GetName()))
+		return
+	}
+	d.references = append(d.references, reference)
+}
+
+func (d *YAMLDecoder) resolveReferences() {
+	var resourceURI *URI
+	if d.resource != nil {
+		resourceURI = d.resource.GetURI()
+	}
+	for _, reference := range d.references {
+		var eTarget EObject
+		if reference.node.Kind == yaml.AliasNode {
+			if eTarget = d.nodeToObject[reference.node.Alias]; eTarget == nil {
+				d.error(reference.node, fmt.Sprintf("unable to resolve alias '%v'", reference.node.Value))
+				continue
+			}
+		} else {
+			uri := NewURI(reference.uri)
+			if uri.TrimFragment().IsEmpty() {
+				// reference to an object of this resource
+				eTarget = d.resource.GetEObject(uri.Fragment())
+				uri = NewURIBuilder(resourceURI).SetFragment(uri.Fragment()).URI()
+			} else if resourceURI != nil {
+				uri = resourceURI.Resolve(uri)
+			}
+			if eTarget == nil {
+				// proxy to an object of an other resource
+				eProxy := reference.eClass.GetEPackage().GetEFactoryInstance().Create(reference.eClass).(EObjectInternal)
+				eProxy.ESetProxyURI(uri)
+				eTarget = eProxy
+			}
+		}
+		if reference.eFeature.IsMany() {
+			reference.eObject.EGetResolve(reference.eFeature, false).(EList).Add(eTarget)
+		} else {
+			reference.eObject.ESet(reference.eFeature, eTarget)
+		}
+	}
+	d.references = nil
+}
+
+func (d *YAMLDecoder) getClass(node *yaml.Node) EClass {
+	var className string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "eClass" {
+			className = node.Content[i+1].Value
+			break
+		}
+	}
+	if len(className) == 0 {
+		d.error(node, "object has no 'eClass'")
+		return nil
+	}
+	if eClass := d.classes[className]; eClass != nil {
+		return eClass
+	}
+	index := strings.LastIndex(className, "#//")
+	if index == -1 {
+		d.error(node, fmt.Sprintf("invalid class name '%v'", className))
+		return nil
+	}
+	nsURI := className[:index]
+	name := className[index+3:]
+	packageRegistry := GetPackageRegistry()
+	if d.resource != nil {
+		if resourceSet := d.resource.GetResourceSet(); resourceSet != nil {
+			packageRegistry = resourceSet.GetPackageRegistry()
+		}
+	}
+	ePackage := packageRegistry.GetPackage(nsURI)
+	if ePackage == nil {
+		d.error(node, fmt.Sprintf("unable to find package '%v'", nsURI))
+		return nil
+	}
+	eClass, _ := ePackage.GetEClassifier(name).(EClass)
+	if eClass == nil {
+		d.error(node, fmt.Sprintf("unable to find class '%v' in package '%v'", name, nsURI))
+		return nil
+	}
+	d.classes[className] = eClass
+	return eClass
+}
+
+func (d *YAMLDecoder) getData(node *yaml.Node, eFeature EStructuralFeature) (value any, ok bool) {
+	eDataType := eFeature.GetEType().(EDataType)
+	eFactory := eDataType.GetEPackage().GetEFactoryInstance()
+	defer func() {
+		// factories panic on invalid literals
+		if r := recover(); r != nil {
+			d.error(node, fmt.Sprintf("invalid value '%v' for feature '%v': %v", node.Value, eFeature.GetName(), r))
+			value, ok = nil, false
+		}
+	}()
+	return eFactory.CreateFromString(eDataType, node.Value), true
+}
+
+func (d *YAMLDecoder) getCommentsAdapter(eObject EObject) *yamlCommentsAdapter {
+	comments := getYAMLCommentsAdapter(eObject)
+	if comments == nil {
+		comments = &yamlCommentsAdapter{keys: map[string]yamlComments{}, values: map[string]yamlComments{}}
+		eObject.EAdapters().Add(comments)
+	}
+	return comments
+}
+
+func (d *YAMLDecoder) error(node *yaml.Node, message string) {
+	d.errorFn(NewEDiagnosticImpl(message, d.getLocation(), node.Line, node.Column))
+}
+
+func (d *YAMLDecoder) newSyntaxError(err error) EDiagnostic {
+	line := 0
+	if matches := yamlErrorLineRegexp.FindStringSubmatch(err.Error()); matches != nil {
+		line, _ = strconv.Atoi(matches[1])
+	}
+	return NewEDiagnosticImpl(err.Error(), d.getLocation(), line, 0)
+}
+
+func (d *YAMLDecoder) getLocation() string {
+	if d.resource != nil {
+		if uri := d.resource.GetURI(); uri != nil {
+			return uri.String()
+		}
+	}
+	return ""
+}
+
+func joinYAMLComments(first string, second string) string {
+	if len(first) == 0 {
+		return second
+	} else if len(second) == 0 {
+		return first
+	}
+	return first + "\n" + second
+}
@@ -0,0 +1,227 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+package ecore
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLEncoder writes resources with the same feature mapping as the JSONEncoder.
+// Each root object of the resource is a yaml document.
+type YAMLEncoder struct {
+	resource        EResource
+	w               io.Writer
+	featureKinds    map[EStructuralFeature]jsonFeatureKind
+	idAttributeName string
+	indent          int
+}
+
+func NewYAMLEncoder(resource EResource, w io.Writer, options map[string]any) *YAMLEncoder {
+	e := &YAMLEncoder{
+		resource:     resource,
+		w:            w,
+		featureKinds: map[EStructuralFeature]jsonFeatureKind{},
+		indent:       2,
+	}
+	if options != nil {
+		e.idAttributeName, _ = options[YAML_OPTION_ID_ATTRIBUTE_NAME].(string)
+		if indent, isIndent := options[YAML_OPTION_INDENT].(int); isIndent && indent > 0 {
+			e.indent = indent
+		}
+	}
+	return e
+}
+
+func (e *YAMLEncoder) EncodeResource() {
+	documents := []*yaml.Node{}
+	for it := e.resource.GetContents().Iterator(); it.HasNext(); {
+		documents = append(documents, e.encodeDocument(it.Next().(EObject)))
+	}
+	if err := e.encodeDocuments(documents); err != nil {
+		resourcePath := ""
+		if e.resource.GetURI() != nil {
+			resourcePath = e.resource.GetURI().String()
+		}
+		e.resource.GetErrors().Add(NewEDiagnosticImpl(err.Error(), resourcePath, 0, 0))
+	}
+}
+
+func (e *YAMLEncoder) EncodeObject(object EObject) error {
+	return e.encodeDocuments([]*yaml.Node{e.encodeDocument(object)})
+}
+
+func (e *YAMLEncoder) encodeDocuments(documents []*yaml.Node) error {
+	encoder := yaml.NewEncoder(e.w)
+	encoder.SetIndent(e.indent)
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+func (e *YAMLEncoder) encodeDocument(eObject EObject) *yaml.Node {
+	return &yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Content: []*yaml.Node{e.encodeObject(eObject)},
+	}
+}
+
+func (e *YAMLEncoder) encodeObject(eObject EObject) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	comments := getYAMLCommentsAdapter(eObject)
+	if comments != nil {
+		node.HeadComment = comments.object.head
+		node.LineComment = comments.object.line
+		node.FootComment = comments.object.foot
+	}
+	eClass := eObject.EClass()
+	// class
+	e.appendScalar(node, "eClass", getJSONClassName(eClass))
+	// id
+	if idManager := e.resource.GetObjectIDManager(); len(e.idAttributeName) > 0 && idManager != nil {
+		if id := idManager.GetID(eObject); id != nil {
+			e.appendScalar(node, e.idAttributeName, fmt.Sprintf("%v", id))
+		}
+	}
+	// features
+	for itFeature := eClass.GetEAllStructuralFeatures().Iterator(); itFeature.HasNext(); {
+		eFeature := itFeature.Next().(EStructuralFeature)
+		e.encodeFeatureValue(node, eObject, eFeature)
+	}
+	// restore comments of keys and values
+	if comments != nil {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode := node.Content[i]
+			if c, isComment := comments.keys[keyNode.Value]; isComment {
+				keyNode.HeadComment = joinYAMLComments(c.head, keyNode.HeadComment)
+				keyNode.LineComment = c.line
+				keyNode.FootComment = c.foot
+			}
+			if c, isComment := comments.values[keyNode.Value]; isComment {
+				valueNode := node.Content[i+1]
+				valueNode.HeadComment = c.head
+				valueNode.LineComment = c.line
+				valueNode.FootComment = c.foot
+			}
+		}
+	}
+	return node
+}
+
+func (e *YAMLEncoder) encodeObjectReference(eObject EObject) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	e.appendScalar(node, "eClass", getJSONClassName(eObject.EClass()))
+	e.appendScalar(node, "eRef", e.getReference(eObject))
+	return node
+}
+
+func (e *YAMLEncoder) encodeFeatureValue(node *yaml.Node, eObject EObject, eFeature EStructuralFeature) {
+	if !eObject.EIsSet(eFeature) {
+		return
+	}
+
+	// compute feature kind
+	kind, ok := e.featureKinds[eFeature]
+	if !ok {
+		kind = getJSONCodecFeatureKind(eFeature)
+		e.featureKinds[eFeature] = kind
+	}
+
+	value := eObject.EGetResolve(eFeature, false)
+	switch kind {
+	case jfkTransient:
+	case jfkData:
+		if str, ok := e.getData(value, eFeature); ok {
+			e.appendScalar(node, eFeature.GetName(), str)
+		}
+	case jfkDataList:
+		l := value.(EList)
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for it := l.Iterator(); it.HasNext(); {
+			if str, ok := e.getData(it.Next(), eFeature); ok {
+				sequence.Content = append(sequence.Content, newYAMLScalar(str))
+			}
+		}
+		e.appendNode(node, eFeature.GetName(), sequence)
+	case jfkObject:
+		e.appendNode(node, eFeature.GetName(), e.encodeObject(value.(EObject)))
+	case jfkObjectList:
+		l := value.(EList)
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for it := l.Iterator(); it.HasNext(); {
+			sequence.Content = append(sequence.Content, e.encodeObject(it.Next().(EObject)))
+		}
+		e.appendNode(node, eFeature.GetName(), sequence)
+	case jfkObjectReference:
+		e.appendNode(node, eFeature.GetName(), e.encodeObjectReference(value.(EObject)))
+	case jfkObjectReferenceList:
+		l := value.(EList)
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for it := l.Iterator(); it.HasNext(); {
+			sequence.Content = append(sequence.Content, e.encodeObjectReference(it.Next().(EObject)))
+		}
+		e.appendNode(node, eFeature.GetName(), sequence)
+	}
+}
+
+func (e *YAMLEncoder) appendScalar(node *yaml.Node, key string, value string) {
+	e.appendNode(node, key, newYAMLScalar(value))
+}
+
+func (e *YAMLEncoder) appendNode(node *yaml.Node, key string, value *yaml.Node) {
+	keyNode := newYAMLScalar(key)
+	// comments of a mapping value are attached to its key
+	if value.Kind == yaml.MappingNode && len(value.HeadComment) > 0 {
+		keyNode.HeadComment = value.HeadComment
+		value.HeadComment = ""
+	}
+	node.Content = append(node.Content, keyNode, value)
+}
+
+func (e *YAMLEncoder) getData(value any, f EStructuralFeature) (string, bool) {
+	if value == nil {
+		return "", false
+	} else {
+		d := f.GetEType().(EDataType)
+		p := d.GetEPackage()
+		f := p.GetEFactoryInstance()
+		s := f.ConvertToString(d, value)
+		return s, true
+	}
+}
+
+func (e *YAMLEncoder) getReference(eObject EObject) string {
+	eInternal, _ := eObject.(EObjectInternal)
+	if eInternal != nil {
+		objectURI := eInternal.EProxyURI()
+		if objectURI == nil {
+			eOtherResource := eObject.EResource()
+			if eOtherResource == nil {
+				return ""
+			}
+			objectURI = NewURIBuilder(eOtherResource.GetURI()).SetFragment(eOtherResource.GetURIFragment(eObject)).URI()
+		}
+		if uri := e.resource.GetURI(); uri != nil {
+			objectURI = uri.Relativize(objectURI)
+		}
+		return objectURI.String()
+	}
+	return ""
+}
+
+func newYAMLScalar(value string) *yaml.Node {
+	// values are written plain when possible, decoder reads them as strings
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
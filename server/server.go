@@ -0,0 +1,602 @@
+// *****************************************************************************
+// Copyright(c) 2021 MASA Group
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+//
+// *****************************************************************************
+
+// Package server exposes an ecore.EResourceSet as a REST API.
+//
+// Resources are identified by the 'uri' query parameter and objects by the
+// 'fragment' query parameter:
+//
+//	GET    /resources                                 list resources of the resource set
+//	GET    /resource?uri=                             encode a resource
+//	POST   /resource/save?uri=                        save a resource
+//	GET    /object?uri=&fragment=                     encode an object
+//	PATCH  /object?uri=&fragment=                     set features of an object
+//	POST   /object/elements?uri=&fragment=&feature=   add an element to a many valued feature
+//	DELETE /object/elements?uri=&fragment=&feature=   remove an element of a many valued feature
+//	GET    /package?nsURI=                            encode a package of the package registry
+//
+// Encoding format is negotiated with the Accept header: media types are mapped
+// to extensions and the codec is retrieved from the codec registry.
+//
+// Feature values are written in json: a string, a number or a boolean for data,
+// {"eRef": uri} for a reference to an existing object, {"eClass": class, ...} for a
+// new object, an array for many valued features and null to unset a feature.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/masagroup/soft.go/ecore"
+)
+
+var defaultMediaTypes = map[string]string{
+	"application/xml":          "xml",
+	"text/xml":                 "xml",
+	"application/xmi+xml":      "ecore",
+	"application/json":         "json",
+	"application/octet-stream": "bin",
+	"application/x-protobuf":   "pb",
+	"application/yaml":         "yaml",
+}
+
+type serverOption interface {
+	apply(*Server)
+}
+
+type funcServerOption struct {
+	f func(*Server)
+}
+
+func (fso *funcServerOption) apply(s *Server) {
+	fso.f(s)
+}
+
+func newFuncServerOption(f func(*Server)) *funcServerOption {
+	return &funcServerOption{
+		f: f,
+	}
+}
+
+// ServerMediaType maps a media type to the extension used to retrieve its codec
+func ServerMediaType(mediaType string, extension string) serverOption {
+	return newFuncServerOption(func(s *Server) {
+		s.mediaTypes[mediaType] = extension
+	})
+}
+
+// ServerCodecOptions sets options given to codecs encoders and decoders
+func ServerCodecOptions(options map[string]any) serverOption {
+	return newFuncServerOption(func(s *Server) {
+		s.codecOptions = options
+	})
+}
+
+// Server is an http.Handler serving a resource set.
+// Requests are serialized since models are not safe for concurrent use.
+type Server struct {
+	mutex         sync.Mutex
+	resourceSet   ecore.EResourceSet
+	codecRegistry ecore.ECodecRegistry
+	codecOptions  map[string]any
+	mediaTypes    map[string]string
+	mux           *http.ServeMux
+}
+
+type resourceInfo struct {
+	URI      string   `json:"uri"`
+	Loaded   bool     `json:"loaded"`
+	Contents int      `json:"contents"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func newHTTPError(status int, format string, args ...any) *httpError {
+	return &httpError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+func NewServer(resourceSet ecore.EResourceSet, opts ...serverOption) *Server {
+	s := &Server{
+		resourceSet: resourceSet,
+		mediaTypes:  map[string]string{},
+		mux:         http.NewServeMux(),
+	}
+	for mediaType, extension := range defaultMediaTypes {
+		s.mediaTypes[mediaType] = extension
+	}
+	// json codec only has an encoder and is not registered by default
+	s.codecRegistry = ecore.NewECodecRegistryImplWithDelegate(resourceSet.GetCodecRegistry())
+	s.codecRegistry.GetExtensionToCodecMap()["json"] = &ecore.JSONCodec{}
+	for _, opt := range opts {
+		opt.apply(s)
+	}
+	s.handle("GET /resources", s.getResources)
+	s.handle("GET /resource", s.getResource)
+	s.handle("POST /resource/save", s.saveResource)
+	s.handle("GET /object", s.getObject)
+	s.handle("PATCH /object", s.patchObject)
+	s.handle("POST /object/elements", s.addElement)
+	s.handle("DELETE /object/elements", s.removeElement)
+	s.handle("GET /package", s.getPackage)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handle(pattern string, handler func(w http.ResponseWriter, r *http.Request) error) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if err := handler(w, r); err != nil {
+			status := http.StatusInternalServerError
+			if e, _ := err.(*httpError); e != nil {
+				status = e.status
+			}
+			http.Error(w, err.Error(), status)
+		}
+	})
+}
+
+func (s *Server) getResources(w http.ResponseWriter, r *http.Request) error {
+	infos := []resourceInfo{}
+	for it := s.resourceSet.GetResources().Iterator(); it.HasNext(); {
+		resource := it.Next().(ecore.EResource)
+		info := resourceInfo{Loaded: resource.IsLoaded(), Contents: resource.GetContents().Size()}
+		if uri := resource.GetURI(); uri != nil {
+			info.URI = uri.String()
+		}
+		info.Errors = diagnosticMessages(resource.GetErrors(), 0)
+		infos = append(infos, info)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(infos)
+}
+
+func (s *Server) getResource(w http.ResponseWriter, r *http.Request) error {
+	resource, err := s.getRequestResource(r)
+	if err != nil {
+		return err
+	}
+	return s.encode(w, r, resource, func(encoder ecore.EEncoder) error {
+		errors := resource.GetErrors()
+		size := errors.Size()
+		encoder.EncodeResource()
+		if messages := diagnosticMessages(errors, size); len(messages) > 0 {
+			return newHTTPError(http.StatusInternalServerError, "%s", strings.Join(messages, "\n"))
+		}
+		return nil
+	})
+}
+
+func (s *Server) saveResource(w http.ResponseWriter, r *http.Request) error {
+	resource, err := s.getRequestResource(r)
+	if err != nil {
+		return err
+	}
+	resource.SaveWithOptions(s.codecOptions)
+	if messages := diagnosticMessages(resource.GetErrors(), 0); len(messages) > 0 {
+		return newHTTPError(http.StatusInternalServerError, "%s", strings.Join(messages, "\n"))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request) error {
+	resource, eObject, err := s.getRequestObject(r)
+	if err != nil {
+		return err
+	}
+	return s.encode(w, r, resource, func(encoder ecore.EEncoder) error {
+		return encoder.EncodeObject(eObject)
+	})
+}
+
+func (s *Server) patchObject(w http.ResponseWriter, r *http.Request) error {
+	resource, eObject, err := s.getRequestObject(r)
+	if err != nil {
+		return err
+	}
+	values := map[string]json.RawMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		return newHTTPError(http.StatusBadRequest, "invalid body: %v", err)
+	}
+	// check all features before modifying the object
+	eClass := eObject.EClass()
+	for name := range values {
+		eFeature := eClass.GetEStructuralFeatureFromName(name)
+		if eFeature == nil {
+			return newHTTPError(http.StatusBadRequest, "unknown feature '%s' in class '%s'", name, eClass.GetName())
+		} else if !eFeature.IsChangeable() {
+			return newHTTPError(http.StatusBadRequest, "feature '%s' is not changeable", name)
+		}
+	}
+	// decode all values before modifying the object
+	// features are handled in class order so that results and notifications are deterministic
+	type featureValue struct {
+		eFeature ecore.EStructuralFeature
+		value    any
+	}
+	featureValues := []featureValue{}
+	for itFeature := eClass.GetEAllStructuralFeatures().Iterator(); itFeature.HasNext(); {
+		eFeature := itFeature.Next().(ecore.EStructuralFeature)
+		raw, isPatched := values[eFeature.GetName()]
+		if !isPatched {
+			continue
+		}
+		value, err := s.getFeatureValue(resource, eFeature, raw)
+		if err != nil {
+			return err
+		}
+		featureValues = append(featureValues, featureValue{eFeature: eFeature, value: value})
+	}
+	for _, featureValue := range featureValues {
+		setFeatureValue(eObject, featureValue.eFeature, featureValue.value)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) addElement(w http.ResponseWriter, r *http.Request) error {
+	resource, eObject, eFeature, err := s.getRequestManyFeature(r)
+	if err != nil {
+		return err
+	}
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		return newHTTPError(http.StatusBadRequest, "invalid body: %v", err)
+	}
+	value, err := s.getValue(resource, eFeature, raw)
+	if err != nil {
+		return err
+	}
+	l := eObject.EGetResolve(eFeature, false).(ecore.EList)
+	if index := r.URL.Query().Get("index"); len(index) > 0 {
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 || i > l.Size() {
+			return newHTTPError(http.StatusBadRequest, "invalid index '%s'", index)
+		}
+		if !l.Insert(i, value) {
+			return newHTTPError(http.StatusConflict, "unable to insert value in feature '%s'", eFeature.GetName())
+		}
+	} else if !l.Add(value) {
+		return newHTTPError(http.StatusConflict, "unable to add value to feature '%s'", eFeature.GetName())
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) removeElement(w http.ResponseWriter, r *http.Request) error {
+	_, eObject, eFeature, err := s.getRequestManyFeature(r)
+	if err != nil {
+		return err
+	}
+	l := eObject.EGetResolve(eFeature, false).(ecore.EList)
+	index := r.URL.Query().Get("index")
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 || i >= l.Size() {
+		return newHTTPError(http.StatusBadRequest, "invalid index '%s'", index)
+	}
+	l.RemoveAt(i)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) getPackage(w http.ResponseWriter, r *http.Request) error {
+	nsURI := r.URL.Query().Get("nsURI")
+	ePackage := s.resourceSet.GetPackageRegistry().GetPackage(nsURI)
+	if ePackage == nil {
+		return newHTTPError(http.StatusNotFound, "package '%s' not found", nsURI)
+	}
+	resource := ePackage.EResource()
+	if resource == nil {
+		// encoders only need the uri of the resource
+		resource = ecore.NewEResourceImpl()
+		resource.SetURI(ecore.NewURI(nsURI + ".ecore"))
+	}
+	return s.encode(w, r, resource, func(encoder ecore.EEncoder) error {
+		return encoder.EncodeObject(ePackage)
+	})
+}
+
+func (s *Server) getRequestResource(r *http.Request) (ecore.EResource, error) {
+	uri := r.URL.Query().Get("uri")
+	if len(uri) == 0 {
+		return nil, newHTTPError(http.StatusBadRequest, "missing 'uri' parameter")
+	}
+	// resources are never loaded on demand: only resources of the resource set are served
+	resource := s.resourceSet.GetResource(ecore.NewURI(uri), false)
+	if resource == nil {
+		return nil, newHTTPError(http.StatusNotFound, "resource '%s' not found", uri)
+	}
+	return resource, nil
+}
+
+func (s *Server) getRequestObject(r *http.Request) (ecore.EResource, ecore.EObject, error) {
+	resource, err := s.getRequestResource(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	fragment := r.URL.Query().Get("fragment")
+	eObject := resource.GetEObject(fragment)
+	if eObject == nil {
+		return nil, nil, newHTTPError(http.StatusNotFound, "object '%s' not found in resource '%s'", fragment, resource.GetURI())
+	}
+	return resource, eObject, nil
+}
+
+func (s *Server) getRequestManyFeature(r *http.Request) (ecore.EResource, ecore.EObject, ecore.EStructuralFeature, error) {
+	resource, eObject, err := s.getRequestObject(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	name := r.URL.Query().Get("feature")
+	eFeature := eObject.EClass().GetEStructuralFeatureFromName(name)
+	if eFeature == nil {
+		return nil, nil, nil, newHTTPError(http.StatusBadRequest, "unknown feature '%s' in class '%s'", name, eObject.EClass().GetName())
+	} else if !eFeature.IsMany() {
+		return nil, nil, nil, newHTTPError(http.StatusBadRequest, "feature '%s' is not many valued", name)
+	} else if !eFeature.IsChangeable() {
+		return nil, nil, nil, newHTTPError(http.StatusBadRequest, "feature '%s' is not changeable", name)
+	}
+	return resource, eObject, eFeature, nil
+}
+
+// getFeatureValue decodes the value of a feature: nil for a json null, a list of values for a many feature
+func (s *Server) getFeatureValue(resource ecore.EResource, eFeature ecore.EStructuralFeature, raw json.RawMessage) (any, error) {
+	if isJSONNull(raw) {
+		return nil, nil
+	}
+	if !eFeature.IsMany() {
+		return s.getValue(resource, eFeature, raw)
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(raw, &raws); err != nil {
+		return nil, newHTTPError(http.StatusBadRequest, "feature '%s' value must be an array", eFeature.GetName())
+	}
+	values := []any{}
+	for _, raw := range raws {
+		value, err := s.getValue(resource, eFeature, raw)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// setFeatureValue applies a value decoded by getFeatureValue
+func setFeatureValue(eObject ecore.EObject, eFeature ecore.EStructuralFeature, value any) {
+	if value == nil {
+		eObject.EUnset(eFeature)
+	} else if values, isMany := value.([]any); isMany && eFeature.IsMany() {
+		l := eObject.EGetResolve(eFeature, false).(ecore.EList)
+		l.Clear()
+		l.AddAll(ecore.NewBasicEList(values))
+	} else {
+		eObject.ESet(eFeature, value)
+	}
+}
+
+func (s *Server) getValue(resource ecore.EResource, eFeature ecore.EStructuralFeature, raw json.RawMessage) (value any, err error) {
+	switch eType := eFeature.GetEType().(type) {
+	case ecore.EDataType:
+		var literal string
+		if err := json.Unmarshal(raw, &literal); err != nil {
+			// numbers and booleans are converted from their json representation
+			literal = strings.TrimSpace(string(raw))
+		}
+		defer func() {
+			// factories panic on invalid literals
+			if r := recover(); r != nil {
+				value, err = nil, newHTTPError(http.StatusBadRequest, "invalid value '%s' for feature '%s'", literal, eFeature.GetName())
+			}
+		}()
+		return eType.GetEPackage().GetEFactoryInstance().CreateFromString(eType, literal), nil
+	case ecore.EClass:
+		return s.getObjectValue(resource, eFeature, eType, raw)
+	}
+	return nil, newHTTPError(http.StatusBadRequest, "feature '%s' has no type", eFeature.GetName())
+}
+
+func (s *Server) getObjectValue(resource ecore.EResource, eFeature ecore.EStructuralFeature, eType ecore.EClass, raw json.RawMessage) (ecore.EObject, error) {
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, newHTTPError(http.StatusBadRequest, "feature '%s' value must be an object", eFeature.GetName())
+	}
+	var eObject ecore.EObject
+	if ref, isRef := values["eRef"]; isRef {
+		// existing object
+		var uriStr string
+		if err := json.Unmarshal(ref, &uriStr); err != nil {
+			return nil, newHTTPError(http.StatusBadRequest, "invalid 'eRef' for feature '%s'", eFeature.GetName())
+		}
+		uri := ecore.NewURI(uriStr)
+		if uri.TrimFragment().IsEmpty() {
+			eObject = resource.GetEObject(uri.Fragment())
+		} else {
+			if resourceURI := resource.GetURI(); resourceURI != nil {
+				uri = resourceURI.Resolve(uri)
+			}
+			eObject = s.resourceSet.GetEObject(uri, false)
+		}
+		if eObject == nil {
+			return nil, newHTTPError(http.StatusNotFound, "object '%s' not found", uriStr)
+		}
+	} else if class, isClass := values["eClass"]; isClass {
+		// new object
+		var className string
+		if err := json.Unmarshal(class, &className); err != nil {
+			return nil, newHTTPError(http.StatusBadRequest, "invalid 'eClass' for feature '%s'", eFeature.GetName())
+		}
+		eClass, err := s.getClass(className)
+		if err != nil {
+			return nil, err
+		}
+		eObject = eClass.GetEPackage().GetEFactoryInstance().Create(eClass)
+		for name, value := range values {
+			if name == "eClass" {
+				continue
+			}
+			eChildFeature := eClass.GetEStructuralFeatureFromName(name)
+			if eChildFeature == nil {
+				return nil, newHTTPError(http.StatusBadRequest, "unknown feature '%s' in class '%s'", name, eClass.GetName())
+			}
+			childValue, err := s.getFeatureValue(resource, eChildFeature, value)
+			if err != nil {
+				return nil, err
+			}
+			setFeatureValue(eObject, eChildFeature, childValue)
+		}
+	} else {
+		return nil, newHTTPError(http.StatusBadRequest, "feature '%s' value must have an 'eRef' or an 'eClass'", eFeature.GetName())
+	}
+	if !eType.IsSuperTypeOf(eObject.EClass()) {
+		return nil, newHTTPError(http.StatusBadRequest, "invalid class '%s' for feature '%s'", eObject.EClass().GetName(), eFeature.GetName())
+	}
+	return eObject, nil
+}
+
+func (s *Server) getClass(className string) (ecore.EClass, error) {
+	index := strings.LastIndex(className, "#//")
+	if index == -1 {
+		return nil, newHTTPError(http.StatusBadRequest, "invalid class name '%s'", className)
+	}
+	nsURI := className[:index]
+	ePackage := s.resourceSet.GetPackageRegistry().GetPackage(nsURI)
+	if ePackage == nil {
+		return nil, newHTTPError(http.StatusBadRequest, "package '%s' not found", nsURI)
+	}
+	eClass, _ := ePackage.GetEClassifier(className[index+3:]).(ecore.EClass)
+	if eClass == nil {
+		return nil, newHTTPError(http.StatusBadRequest, "class '%s' not found", className)
+	}
+	return eClass, nil
+}
+
+// encode negotiates the codec of the response and writes what encodeFn encodes
+func (s *Server) encode(w http.ResponseWriter, r *http.Request, resource ecore.EResource, encodeFn func(encoder ecore.EEncoder) error) error {
+	mediaType, codec := s.negotiate(r, resource)
+	if codec == nil {
+		return newHTTPError(http.StatusNotAcceptable, "no codec for '%s'", r.Header.Get("Accept"))
+	}
+	var buffer bytes.Buffer
+	encoder := codec.NewEncoder(resource, &buffer, s.codecOptions)
+	if encoder == nil {
+		return newHTTPError(http.StatusNotAcceptable, "no encoder for '%s'", mediaType)
+	}
+	if err := encodeFn(encoder); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", mediaType)
+	_, err := io.Copy(w, &buffer)
+	return err
+}
+
+func (s *Server) negotiate(r *http.Request, resource ecore.EResource) (string, ecore.ECodec) {
+	for _, mediaType := range parseAccept(r.Header.Get("Accept")) {
+		if mediaType == "*/*" {
+			// native format of the resource
+			if uri := resource.GetURI(); uri != nil {
+				if codec := s.codecRegistry.GetCodec(uri); codec != nil {
+					return s.getMediaType(uri), codec
+				}
+			}
+		} else if extension, isExtension := s.mediaTypes[mediaType]; isExtension {
+			if codec := s.codecRegistry.GetCodec(ecore.NewURI("resource." + extension)); codec != nil {
+				return mediaType, codec
+			}
+		}
+	}
+	return "", nil
+}
+
+func (s *Server) getMediaType(uri *ecore.URI) string {
+	path := uri.Path()
+	extension := path[strings.LastIndex(path, ".")+1:]
+	mediaTypes := []string{}
+	for mediaType, e := range s.mediaTypes {
+		if e == extension {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		return "application/octet-stream"
+	}
+	// deterministic choice when several media types share an extension
+	sort.Strings(mediaTypes)
+	return mediaTypes[0]
+}
+
+// parseAccept returns the media types of an Accept header ordered by quality
+func parseAccept(accept string) []string {
+	if len(strings.TrimSpace(accept)) == 0 {
+		return []string{"*/*"}
+	}
+	type acceptedType struct {
+		mediaType string
+		quality   float64
+	}
+	accepted := []acceptedType{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, isQuality := params["q"]; isQuality {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			accepted = append(accepted, acceptedType{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+	mediaTypes := []string{}
+	for _, a := range accepted {
+		mediaTypes = append(mediaTypes, a.mediaType)
+	}
+	return mediaTypes
+}
+
+func diagnosticMessages(diagnostics ecore.EList, from int) []string {
+	messages := []string{}
+	for i := from; i < diagnostics.Size(); i++ {
+		messages = append(messages, diagnostics.Get(i).(ecore.EDiagnostic).GetMessage())
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return messages
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return strings.TrimSpace(string(raw)) == "null"
+}
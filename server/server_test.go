@@ -0,0 +1,248 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/masagroup/soft.go/ecore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testServer struct {
+	*httptest.Server
+	resourceSet ecore.EResourceSet
+	resource    ecore.EResource
+	ePackage    ecore.EPackage
+	modelURI    string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	// copy model so that it can be saved
+	modelPath := filepath.Join(t.TempDir(), "library.complex.xml")
+	bytes, err := os.ReadFile("testdata/library.complex.xml")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(modelPath, bytes, 0644))
+
+	resourceSet := ecore.NewEResourceSetImpl()
+	ePackageResource := resourceSet.GetResource(ecore.NewURI("testdata/library.complex.ecore"), true)
+	require.True(t, ePackageResource.GetErrors().Empty())
+	ePackage := ePackageResource.GetContents().Get(0).(ecore.EPackage)
+	resourceSet.GetPackageRegistry().RegisterPackage(ePackage)
+
+	options := map[string]any{ecore.XML_OPTION_EXTENDED_META_DATA: ecore.NewExtendedMetaData()}
+	modelURI := ecore.CreateFileURI(modelPath)
+	resource := resourceSet.CreateResource(modelURI)
+	resource.LoadWithOptions(options)
+	require.True(t, resource.IsLoaded())
+	require.True(t, resource.GetErrors().Empty())
+
+	s := httptest.NewServer(NewServer(resourceSet, ServerCodecOptions(options)))
+	t.Cleanup(s.Close)
+	return &testServer{Server: s, resourceSet: resourceSet, resource: resource, ePackage: ePackage, modelURI: modelURI.String()}
+}
+
+func (s *testServer) do(t *testing.T, method string, path string, query url.Values, accept string, body string) (*http.Response, string) {
+	request, err := http.NewRequest(method, s.URL+path+"?"+query.Encode(), strings.NewReader(body))
+	require.NoError(t, err)
+	if len(accept) > 0 {
+		request.Header.Set("Accept", accept)
+	}
+	response, err := s.Client().Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return response, string(content)
+}
+
+func (s *testServer) getFeature(eObject ecore.EObject, name string) any {
+	return eObject.EGet(eObject.EClass().GetEStructuralFeatureFromName(name))
+}
+
+func TestServer_GetResources(t *testing.T) {
+	s := newTestServer(t)
+	response, body := s.do(t, http.MethodGet, "/resources", nil, "", "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	infos := []resourceInfo{}
+	require.NoError(t, json.Unmarshal([]byte(body), &infos))
+	require.Len(t, infos, 2)
+	assert.Equal(t, "testdata/library.complex.ecore", infos[0].URI)
+	assert.Equal(t, s.modelURI, infos[1].URI)
+	assert.True(t, infos[1].Loaded)
+	assert.Equal(t, 1, infos[1].Contents)
+}
+
+func TestServer_GetResource(t *testing.T) {
+	s := newTestServer(t)
+	response, body := s.do(t, http.MethodGet, "/resource", url.Values{"uri": {s.modelURI}}, "", "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/xml", response.Header.Get("Content-Type"))
+	assert.Contains(t, body, "<library")
+
+	response, _ = s.do(t, http.MethodGet, "/resource", url.Values{"uri": {"unknown.xml"}}, "", "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, _ = s.do(t, http.MethodGet, "/resource", nil, "", "")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestServer_GetObject_Negotiation(t *testing.T) {
+	s := newTestServer(t)
+	query := url.Values{"uri": {s.modelURI}, "fragment": {"//@library/@books.0"}}
+
+	response, body := s.do(t, http.MethodGet, "/object", query, "application/json", "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	assert.Contains(t, body, `"title":"Title 0"`)
+
+	response, body = s.do(t, http.MethodGet, "/object", query, "text/html;q=1, application/octet-stream;q=0.5", "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/octet-stream", response.Header.Get("Content-Type"))
+	assert.NotEmpty(t, body)
+
+	response, _ = s.do(t, http.MethodGet, "/object", query, "text/html", "")
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+
+	query.Set("fragment", "//@library/@books.10")
+	response, _ = s.do(t, http.MethodGet, "/object", query, "", "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestServer_PatchObject(t *testing.T) {
+	s := newTestServer(t)
+	query := url.Values{"uri": {s.modelURI}, "fragment": {"//@library/@books.1"}}
+	eBook := s.resource.GetEObject("//@library/@books.1")
+	require.NotNil(t, eBook)
+
+	response, body := s.do(t, http.MethodPatch, "/object", query, "", `{"title":"Dune","pages":412,"category":"ScienceFiction"}`)
+	require.Equal(t, http.StatusNoContent, response.StatusCode, body)
+	assert.Equal(t, "Dune", s.getFeature(eBook, "title"))
+	assert.Equal(t, 412, s.getFeature(eBook, "pages"))
+
+	response, _ = s.do(t, http.MethodPatch, "/object", query, "", `{"category":null}`)
+	require.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.False(t, eBook.EIsSet(eBook.EClass().GetEStructuralFeatureFromName("category")))
+
+	response, _ = s.do(t, http.MethodPatch, "/object", query, "", `{"unknown":"value"}`)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// invalid values leave the object unchanged
+	response, _ = s.do(t, http.MethodPatch, "/object", query, "", `{"title":"Hyperion","category":"Unknown"}`)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Equal(t, "Dune", s.getFeature(eBook, "title"))
+
+	// reference
+	query.Set("fragment", "//@library/@employees.0")
+	response, body = s.do(t, http.MethodPatch, "/object", query, "", `{"manager":{"eRef":"#//@library/@employees.1"}}`)
+	require.Equal(t, http.StatusNoContent, response.StatusCode, body)
+	eEmployee := s.resource.GetEObject("//@library/@employees.0")
+	assert.Equal(t, s.resource.GetEObject("//@library/@employees.1"), s.getFeature(eEmployee, "manager"))
+
+	response, _ = s.do(t, http.MethodPatch, "/object", query, "", `{"manager":{"eRef":"#//@library/@books.0"}}`)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, _ = s.do(t, http.MethodPatch, "/object", query, "", `{"manager":{"eRef":"#//@library/@employees.5"}}`)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+type featureRecorder struct {
+	ecore.AbstractEAdapter
+	features []string
+}
+
+func (r *featureRecorder) NotifyChanged(notification ecore.ENotification) {
+	if eFeature := notification.GetFeature(); eFeature != nil {
+		r.features = append(r.features, eFeature.GetName())
+	}
+}
+
+func TestServer_PatchObject_Order(t *testing.T) {
+	s := newTestServer(t)
+	query := url.Values{"uri": {s.modelURI}, "fragment": {"//@library/@books.1"}}
+	eBook := s.resource.GetEObject("//@library/@books.1")
+	require.NotNil(t, eBook)
+
+	// features are set in class order whatever the order of the request
+	expected := []string{}
+	for itFeature := eBook.EClass().GetEAllStructuralFeatures().Iterator(); itFeature.HasNext(); {
+		switch name := itFeature.Next().(ecore.EStructuralFeature).GetName(); name {
+		case "title", "pages", "category":
+			expected = append(expected, name)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		recorder := &featureRecorder{}
+		eBook.EAdapters().Add(recorder)
+		response, body := s.do(t, http.MethodPatch, "/object", query, "", `{"category":"Biography","pages":100,"title":"Dune"}`)
+		require.Equal(t, http.StatusNoContent, response.StatusCode, body)
+		eBook.EAdapters().Remove(recorder)
+		assert.Equal(t, expected, recorder.features)
+	}
+}
+
+func TestServer_AddRemoveElements(t *testing.T) {
+	s := newTestServer(t)
+	eLibrary := s.resource.GetEObject("//@library")
+	require.NotNil(t, eLibrary)
+	eBooks := s.getFeature(eLibrary, "books").(ecore.EList)
+	require.Equal(t, 2, eBooks.Size())
+
+	query := url.Values{"uri": {s.modelURI}, "fragment": {"//@library"}, "feature": {"books"}}
+	body := `{"eClass":"http:///org/eclipse/emf/examples/library/library.ecore/1.0.0#//Book","title":"Dune","author":{"eRef":"#//@library/@writers.0"}}`
+	response, content := s.do(t, http.MethodPost, "/object/elements", query, "", body)
+	require.Equal(t, http.StatusNoContent, response.StatusCode, content)
+	require.Equal(t, 3, eBooks.Size())
+	eBook := eBooks.Get(2).(ecore.EObject)
+	assert.Equal(t, "Dune", s.getFeature(eBook, "title"))
+	assert.Equal(t, s.resource.GetEObject("//@library/@writers.0"), s.getFeature(eBook, "author"))
+
+	query.Set("index", "0")
+	response, _ = s.do(t, http.MethodDelete, "/object/elements", query, "", "")
+	require.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Equal(t, 2, eBooks.Size())
+
+	query.Set("index", "5")
+	response, _ = s.do(t, http.MethodDelete, "/object/elements", query, "", "")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	query.Set("feature", "name")
+	response, _ = s.do(t, http.MethodPost, "/object/elements", query, "", `"value"`)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestServer_SaveResource(t *testing.T) {
+	s := newTestServer(t)
+	query := url.Values{"uri": {s.modelURI}, "fragment": {"//@library"}}
+	response, _ := s.do(t, http.MethodPatch, "/object", query, "", `{"name":"City Library"}`)
+	require.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	response, body := s.do(t, http.MethodPost, "/resource/save", query, "", "")
+	require.Equal(t, http.StatusNoContent, response.StatusCode, body)
+
+	bytes, err := os.ReadFile(ecore.NewURI(s.modelURI).Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(bytes), `name="City Library"`)
+}
+
+func TestServer_GetPackage(t *testing.T) {
+	s := newTestServer(t)
+	response, body := s.do(t, http.MethodGet, "/package", url.Values{"nsURI": {s.ePackage.GetNsURI()}}, "", "")
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, body, `nsURI="http:///org/eclipse/emf/examples/library/library.ecore/1.0.0"`)
+
+	response, _ = s.do(t, http.MethodGet, "/package", url.Values{"nsURI": {"unknown"}}, "", "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestParseAccept(t *testing.T) {
+	assert.Equal(t, []string{"*/*"}, parseAccept(""))
+	assert.Equal(t, []string{"application/json", "application/xml"}, parseAccept("application/xml;q=0.2, application/json, text/html;q=0"))
+}